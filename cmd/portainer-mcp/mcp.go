@@ -2,13 +2,19 @@ package main
 
 import (
 	"flag"
+	"os"
+	"strings"
 
 	"github.com/portainer/portainer-mcp/internal/mcp"
 	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-const defaultToolsPath = "tools.yaml"
+const (
+	defaultToolsPath   = "tools.yaml"
+	defaultPromptsPath = "prompts.yaml"
+)
 
 var (
 	Version   string
@@ -17,6 +23,10 @@ var (
 )
 
 func main() {
+	// Logs are written to stderr and forwarded to the MCP clients as notifications
+	logForwarder := mcp.NewLogForwarder()
+	log.Logger = log.Output(zerolog.MultiLevelWriter(os.Stderr, logForwarder))
+
 	log.Info().
 		Str("version", Version).
 		Str("build-date", BuildDate).
@@ -26,10 +36,14 @@ func main() {
 	serverFlag := flag.String("server", "", "The Portainer server URL")
 	tokenFlag := flag.String("token", "", "The authentication token for the Portainer server")
 	toolsFlag := flag.String("tools", "", "The path to the tools YAML file")
+	promptsFlag := flag.String("prompts", "", "The path to the prompts YAML file")
+	strippingProfilesFlag := flag.String("stripping-profiles", "", "The path to a YAML file defining custom stripping profiles for the Kubernetes responses")
 	readOnlyFlag := flag.Bool("read-only", false, "Run in read-only mode")
 	disableVersionCheckFlag := flag.Bool("disable-version-check", false, "Disable Portainer server version check")
 	basePathFlag := flag.String("base-path", "", "Custom base path for the Portainer API (e.g., '/portainer/api' for subpath deployments)")
 	httpFlag := flag.Bool("http", false, "Enable HTTP/SSE transport instead of stdio")
+	maxResponseSizeFlag := flag.Int("max-response-size", mcp.DefaultMaxResponseSize, "Maximum size in bytes of a proxy response returned in a single tool result, larger responses are split in chunks (0 to disable)")
+	execAllowlistFlag := flag.String("exec-allowlist", strings.Join(mcp.DefaultExecAllowlist, ","), "Comma-separated list of the commands that can be run in the containers by the execInContainer tool ('*' to allow any command)")
 	addrFlag := flag.String("addr", ":3000", "Address to listen on when using HTTP transport (e.g., ':3000' or '0.0.0.0:3000')")
 
 	flag.Parse()
@@ -56,6 +70,23 @@ func main() {
 		log.Info().Msg("created tools.yaml file")
 	}
 
+	promptsPath := *promptsFlag
+	if promptsPath == "" {
+		promptsPath = defaultPromptsPath
+	}
+
+	// Same for the prompts.yaml file
+	exists, err = tooldef.CreatePromptsFileIfNotExists(promptsPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create prompts.yaml file")
+	}
+
+	if exists {
+		log.Info().Msg("using existing prompts.yaml file")
+	} else {
+		log.Info().Msg("created prompts.yaml file")
+	}
+
 	transport := "stdio"
 	if *httpFlag {
 		transport = "http"
@@ -64,17 +95,28 @@ func main() {
 	log.Info().
 		Str("portainer-host", *serverFlag).
 		Str("tools-path", toolsPath).
+		Str("prompts-path", promptsPath).
+		Str("stripping-profiles-path", *strippingProfilesFlag).
 		Bool("read-only", *readOnlyFlag).
 		Bool("disable-version-check", *disableVersionCheckFlag).
 		Str("base-path", *basePathFlag).
 		Str("transport", transport).
 		Str("addr", *addrFlag).
+		Int("max-response-size", *maxResponseSizeFlag).
+		Str("exec-allowlist", *execAllowlistFlag).
 		Msg("starting MCP server")
 
 	// Build server options
 	serverOpts := []mcp.ServerOption{
 		mcp.WithReadOnly(*readOnlyFlag),
 		mcp.WithDisableVersionCheck(*disableVersionCheckFlag),
+		mcp.WithPromptsPath(promptsPath),
+		mcp.WithLogForwarder(logForwarder),
+		mcp.WithMaxResponseSize(*maxResponseSizeFlag),
+		mcp.WithExecAllowlist(parseExecAllowlist(*execAllowlistFlag)),
+	}
+	if *strippingProfilesFlag != "" {
+		serverOpts = append(serverOpts, mcp.WithStrippingProfilesPath(*strippingProfilesFlag))
 	}
 	if *basePathFlag != "" {
 		serverOpts = append(serverOpts, mcp.WithBasePath(*basePathFlag))
@@ -94,7 +136,15 @@ func main() {
 	server.AddTeamFeatures()
 	server.AddAccessGroupFeatures()
 	server.AddDockerProxyFeatures()
+	server.AddContainerFeatures()
+	server.AddImageFeatures()
+	server.AddVolumeFeatures()
+	server.AddNetworkFeatures()
+	server.AddSwarmFeatures()
 	server.AddKubernetesProxyFeatures()
+	server.AddKubernetesFeatures()
+	server.AddHelmFeatures()
+	server.AddPromptFeatures()
 
 	if *httpFlag {
 		log.Info().Str("addr", *addrFlag).Msg("starting HTTP/SSE server")
@@ -108,3 +158,14 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to start server")
 	}
 }
+
+// parseExecAllowlist parses the comma-separated list of the commands allowed by the execInContainer tool
+func parseExecAllowlist(value string) []string {
+	commands := []string{}
+	for _, command := range strings.Split(value, ",") {
+		if command = strings.TrimSpace(command); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
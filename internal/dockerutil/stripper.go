@@ -0,0 +1,286 @@
+package dockerutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+)
+
+// ProcessRawDockerAPIResponse takes an HTTP response, processes the JSON body,
+// removes the verbose fields from any Docker resource(s) found (containers, images, networks)
+// and returns the modified JSON bytes.
+func ProcessRawDockerAPIResponse(httpResp *http.Response) ([]byte, error) {
+	if httpResp == nil {
+		return nil, fmt.Errorf("http response is nil")
+	}
+	if httpResp.Body == nil {
+		if httpResp.StatusCode != http.StatusNoContent && httpResp.ContentLength != 0 {
+			return nil, fmt.Errorf("http response body is nil but content was expected (status: %s)", httpResp.Status)
+		}
+		return []byte{}, nil // Return empty bytes if no body and appropriate status
+	}
+	defer httpResp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if len(bodyBytes) == 0 {
+		return bodyBytes, nil // Valid empty body
+	}
+
+	return StripDockerResources(bodyBytes)
+}
+
+// StripDockerResources strips the Docker resource or list of resources contained in a JSON document.
+// Documents that are not Docker containers, images or networks are returned without the empty fields.
+func StripDockerResources(data []byte) ([]byte, error) {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w. Body: %s", err, string(data))
+	}
+
+	switch v := document.(type) {
+	case []any:
+		for i, item := range v {
+			if resource, ok := item.(map[string]any); ok {
+				v[i] = stripResource(resource)
+			}
+		}
+	case map[string]any:
+		document = stripResource(v)
+	}
+
+	return json.Marshal(removeEmpty(document))
+}
+
+// stripResource strips a single Docker resource according to its kind
+func stripResource(resource map[string]any) map[string]any {
+	switch {
+	case isContainer(resource):
+		stripContainer(resource)
+	case isImage(resource):
+		stripImage(resource)
+	case isNetwork(resource):
+		stripNetwork(resource)
+	}
+
+	return resource
+}
+
+// isContainer checks if the resource is a container (inspect or list item)
+func isContainer(resource map[string]any) bool {
+	_, hasState := resource["State"]
+	_, hasHostConfig := resource["HostConfig"]
+	_, hasImage := resource["Image"]
+	return hasState && hasImage || hasHostConfig && hasImage
+}
+
+// isImage checks if the resource is an image (inspect or list item)
+func isImage(resource map[string]any) bool {
+	_, hasRepoTags := resource["RepoTags"]
+	_, hasRootFS := resource["RootFS"]
+	return hasRepoTags || hasRootFS
+}
+
+// isNetwork checks if the resource is a network
+func isNetwork(resource map[string]any) bool {
+	_, hasIPAM := resource["IPAM"]
+	_, hasDriver := resource["Driver"]
+	return hasIPAM && hasDriver
+}
+
+// stripContainer drops the storage driver details and the default host configuration values,
+// collapses the network settings and summarizes the mounts of a container
+func stripContainer(container map[string]any) {
+	delete(container, "GraphDriver")
+	delete(container, "ExecIDs")
+
+	if hostConfig, ok := container["HostConfig"].(map[string]any); ok {
+		container["HostConfig"] = removeZeroValues(hostConfig)
+	}
+
+	if networkSettings, ok := container["NetworkSettings"].(map[string]any); ok {
+		container["NetworkSettings"] = collapseNetworkSettings(networkSettings)
+	}
+
+	if mounts, ok := container["Mounts"].([]any); ok {
+		container["Mounts"] = summarizeMounts(mounts)
+	}
+}
+
+// stripImage drops the storage driver details and the deprecated container configuration of an image,
+// and replaces its layers with their count
+func stripImage(image map[string]any) {
+	delete(image, "GraphDriver")
+	delete(image, "ContainerConfig")
+	delete(image, "Container")
+
+	if rootFS, ok := image["RootFS"].(map[string]any); ok {
+		if layers, ok := rootFS["Layers"].([]any); ok {
+			rootFS["Layers"] = len(layers)
+		}
+	}
+}
+
+// stripNetwork reduces the containers attached to a network to their name and addresses
+func stripNetwork(network map[string]any) {
+	containers, ok := network["Containers"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	for id, value := range containers {
+		endpoint, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		containers[id] = pick(endpoint, "Name", "IPv4Address", "IPv6Address")
+	}
+}
+
+// collapseNetworkSettings keeps the published ports and the addresses of the container in each network
+func collapseNetworkSettings(settings map[string]any) map[string]any {
+	collapsed := pick(settings, "Ports")
+
+	networks, ok := settings["Networks"].(map[string]any)
+	if !ok {
+		return collapsed
+	}
+
+	collapsedNetworks := make(map[string]any, len(networks))
+	for name, value := range networks {
+		endpoint, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		network := pick(endpoint, "Gateway", "GlobalIPv6Address", "Aliases", "DNSNames")
+		if ipAddress, ok := endpoint["IPAddress"].(string); ok && ipAddress != "" {
+			if prefixLen, ok := endpoint["IPPrefixLen"].(float64); ok && prefixLen > 0 {
+				ipAddress = fmt.Sprintf("%s/%d", ipAddress, int(prefixLen))
+			}
+			network["IPAddress"] = ipAddress
+		}
+		if networkID, ok := endpoint["NetworkID"].(string); ok {
+			network["NetworkID"] = models.ShortDockerID(networkID)
+		}
+
+		collapsedNetworks[name] = network
+	}
+	collapsed["Networks"] = collapsedNetworks
+
+	return collapsed
+}
+
+// summarizeMounts summarizes each mount into a single line, e.g. "volume data -> /data (rw)"
+func summarizeMounts(mounts []any) []any {
+	summaries := make([]any, 0, len(mounts))
+
+	for _, value := range mounts {
+		mount, ok := value.(map[string]any)
+		if !ok {
+			summaries = append(summaries, value)
+			continue
+		}
+
+		mountType, _ := mount["Type"].(string)
+		source, _ := mount["Name"].(string)
+		if source == "" {
+			source, _ = mount["Source"].(string)
+		}
+		destination, _ := mount["Destination"].(string)
+
+		mode := "ro"
+		if rw, ok := mount["RW"].(bool); ok && rw {
+			mode = "rw"
+		}
+
+		summaries = append(summaries, strings.TrimSpace(fmt.Sprintf("%s %s -> %s (%s)", mountType, source, destination, mode)))
+	}
+
+	return summaries
+}
+
+// pick returns a new map containing the values of the given keys, skipping the empty and zero values
+func pick(source map[string]any, keys ...string) map[string]any {
+	picked := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if value, ok := source[key]; ok && !isEmpty(value) && !isZero(value) {
+			picked[key] = value
+		}
+	}
+	return picked
+}
+
+// removeZeroValues recursively removes the zero values (false, 0, empty strings), nulls, empty
+// maps and empty arrays from a map. It is used for the sections where the zero values are the defaults.
+func removeZeroValues(values map[string]any) map[string]any {
+	for key, value := range values {
+		if nested, ok := value.(map[string]any); ok {
+			value = removeZeroValues(nested)
+			values[key] = value
+		}
+
+		if isEmpty(value) || isZero(value) {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
+// removeEmpty recursively removes the nulls, empty maps and empty arrays from a JSON document
+func removeEmpty(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			nested = removeEmpty(nested)
+			if isEmpty(nested) {
+				delete(v, key)
+				continue
+			}
+			v[key] = nested
+		}
+		return v
+	case []any:
+		for i, nested := range v {
+			v[i] = removeEmpty(nested)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// isEmpty checks if a JSON value is null, an empty map or an empty array
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// isZero checks if a JSON value is false, 0 or an empty string
+func isZero(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,218 @@
+package dockerutil
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const containerInspect = `{
+	"Id": "4fa6e0f0c6786287e131c3852c58a2e01cc697a68231826813597e4994f1d6e2",
+	"Name": "/web",
+	"Image": "sha256:abc",
+	"State": {"Status": "exited", "Running": false, "ExitCode": 137, "OOMKilled": true},
+	"ExecIDs": null,
+	"GraphDriver": {"Name": "overlay2", "Data": {"LowerDir": "/var/lib/docker/overlay2/l1"}},
+	"HostConfig": {
+		"NetworkMode": "bridge",
+		"Memory": 0,
+		"Privileged": false,
+		"RestartPolicy": {"Name": "always", "MaximumRetryCount": 0},
+		"Binds": ["/srv/web:/usr/share/nginx/html:ro"],
+		"Devices": [],
+		"LogConfig": {"Type": "json-file", "Config": {}},
+		"CpuShares": 0,
+		"Isolation": ""
+	},
+	"Config": {"Image": "nginx:latest", "Labels": {}, "Env": ["PATH=/usr/bin"]},
+	"Mounts": [
+		{"Type": "bind", "Source": "/srv/web", "Destination": "/usr/share/nginx/html", "Mode": "ro", "RW": false},
+		{"Type": "volume", "Name": "data", "Source": "/var/lib/docker/volumes/data/_data", "Destination": "/data", "RW": true}
+	],
+	"NetworkSettings": {
+		"Bridge": "",
+		"SandboxID": "0123456789abcdef",
+		"SandboxKey": "/var/run/docker/netns/0123",
+		"Ports": {"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}]},
+		"Networks": {
+			"bridge": {
+				"IPAMConfig": null,
+				"Links": null,
+				"Aliases": null,
+				"MacAddress": "02:42:ac:11:00:02",
+				"NetworkID": "7ea29fc1412292a2d7bba362f9253545fecdfa8ce9a6e37dd10ba8bee7129812",
+				"EndpointID": "fffd8de4cbc6",
+				"Gateway": "172.17.0.1",
+				"IPAddress": "172.17.0.2",
+				"IPPrefixLen": 16,
+				"GlobalIPv6Address": "",
+				"DriverOpts": null
+			}
+		}
+	}
+}`
+
+const strippedContainerInspect = `{
+	"Id": "4fa6e0f0c6786287e131c3852c58a2e01cc697a68231826813597e4994f1d6e2",
+	"Name": "/web",
+	"Image": "sha256:abc",
+	"State": {"Status": "exited", "Running": false, "ExitCode": 137, "OOMKilled": true},
+	"HostConfig": {
+		"NetworkMode": "bridge",
+		"RestartPolicy": {"Name": "always"},
+		"Binds": ["/srv/web:/usr/share/nginx/html:ro"],
+		"LogConfig": {"Type": "json-file"}
+	},
+	"Config": {"Image": "nginx:latest", "Env": ["PATH=/usr/bin"]},
+	"Mounts": [
+		"bind /srv/web -> /usr/share/nginx/html (ro)",
+		"volume data -> /data (rw)"
+	],
+	"NetworkSettings": {
+		"Ports": {"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}]},
+		"Networks": {
+			"bridge": {
+				"NetworkID": "7ea29fc14122",
+				"Gateway": "172.17.0.1",
+				"IPAddress": "172.17.0.2/16"
+			}
+		}
+	}
+}`
+
+func TestStripDockerResources(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedResult string
+		expectedError  bool
+	}{
+		{
+			name:           "container inspect",
+			input:          containerInspect,
+			expectedResult: strippedContainerInspect,
+		},
+		{
+			name:           "container list",
+			input:          fmt.Sprintf("[%s]", containerInspect),
+			expectedResult: fmt.Sprintf("[%s]", strippedContainerInspect),
+		},
+		{
+			name: "image inspect",
+			input: `{
+				"Id": "sha256:abc",
+				"RepoTags": ["nginx:latest"],
+				"Container": "",
+				"ContainerConfig": {"Hostname": ""},
+				"GraphDriver": {"Name": "overlay2"},
+				"RootFS": {"Type": "layers", "Layers": ["sha256:1", "sha256:2", "sha256:3"]},
+				"Size": 187000000
+			}`,
+			expectedResult: `{
+				"Id": "sha256:abc",
+				"RepoTags": ["nginx:latest"],
+				"RootFS": {"Type": "layers", "Layers": 3},
+				"Size": 187000000
+			}`,
+		},
+		{
+			name: "network inspect",
+			input: `{
+				"Name": "bridge",
+				"Driver": "bridge",
+				"IPAM": {"Driver": "default", "Options": null, "Config": [{"Subnet": "172.17.0.0/16"}]},
+				"Containers": {
+					"4fa6e0f0c678": {"Name": "web", "EndpointID": "fffd8de4cbc6", "MacAddress": "02:42:ac:11:00:02", "IPv4Address": "172.17.0.2/16", "IPv6Address": ""}
+				},
+				"Options": {}
+			}`,
+			expectedResult: `{
+				"Name": "bridge",
+				"Driver": "bridge",
+				"IPAM": {"Driver": "default", "Config": [{"Subnet": "172.17.0.0/16"}]},
+				"Containers": {
+					"4fa6e0f0c678": {"Name": "web", "IPv4Address": "172.17.0.2/16"}
+				}
+			}`,
+		},
+		{
+			name:           "other resource",
+			input:          `{"Name": "data", "Driver": "local", "Labels": null, "Options": {}}`,
+			expectedResult: `{"Name": "data", "Driver": "local"}`,
+		},
+		{
+			name:          "invalid JSON",
+			input:         "invalid json",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := StripDockerResources([]byte(tt.input))
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, string(result))
+		})
+	}
+}
+
+func TestProcessRawDockerAPIResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		httpResp       *http.Response
+		expectedResult string
+		expectedError  bool
+	}{
+		{
+			name:          "nil response",
+			httpResp:      nil,
+			expectedError: true,
+		},
+		{
+			name:           "nil body with no content",
+			httpResp:       &http.Response{StatusCode: http.StatusNoContent},
+			expectedResult: "",
+		},
+		{
+			name: "empty body",
+			httpResp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("")),
+			},
+			expectedResult: "",
+		},
+		{
+			name: "container inspect",
+			httpResp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(containerInspect)),
+			},
+			expectedResult: strippedContainerInspect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessRawDockerAPIResponse(tt.httpResp)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedResult == "" {
+				assert.Empty(t, result)
+				return
+			}
+			assert.JSONEq(t, tt.expectedResult, string(result))
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package k8sutil
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Built-in stripping profile names
+const (
+	// ProfileMinimal only removes the managedFields
+	ProfileMinimal = "minimal"
+	// ProfileStandard also removes the metadata that is rarely useful to read a resource
+	ProfileStandard = "standard"
+	// ProfileSummary only keeps the identity, labels, key spec fields and status of a resource
+	ProfileSummary = "summary"
+)
+
+// StrippingProfile defines the fields removed from the Kubernetes resources on top of the managedFields.
+// When KeepFields is set, only the listed fields are kept after the removals.
+type StrippingProfile struct {
+	// Name is the name used to select the profile
+	Name string `yaml:"name"`
+	// RemoveMetadataFields lists the metadata fields to remove (e.g. uid, resourceVersion)
+	RemoveMetadataFields []string `yaml:"removeMetadataFields"`
+	// RemoveAnnotations lists the annotations to remove
+	RemoveAnnotations []string `yaml:"removeAnnotations"`
+	// KeepFields lists the dot-separated paths of the fields to keep (e.g. spec.containers.image).
+	// Arrays are traversed, so a path applies to every element of the arrays it goes through.
+	// The apiVersion and kind are always kept.
+	KeepFields []string `yaml:"keepFields"`
+}
+
+// StrippingProfilesConfig represents the stripping profiles YAML configuration
+type StrippingProfilesConfig struct {
+	Profiles []StrippingProfile `yaml:"profiles"`
+}
+
+// MinimalProfile only removes the managedFields
+var MinimalProfile = StrippingProfile{
+	Name: ProfileMinimal,
+}
+
+// StandardProfile removes the managedFields, the last applied configuration and the bookkeeping metadata
+var StandardProfile = StrippingProfile{
+	Name:                 ProfileStandard,
+	RemoveMetadataFields: []string{"uid", "resourceVersion", "generation"},
+	RemoveAnnotations:    []string{"kubectl.kubernetes.io/last-applied-configuration"},
+}
+
+// SummaryProfile keeps the name, namespace, labels, key spec fields and status conditions of a resource
+var SummaryProfile = StrippingProfile{
+	Name: ProfileSummary,
+	KeepFields: []string{
+		"metadata.name",
+		"metadata.namespace",
+		"metadata.labels",
+		"metadata.creationTimestamp",
+		"spec.replicas",
+		"spec.selector",
+		"spec.type",
+		"spec.clusterIP",
+		"spec.ports",
+		"spec.nodeName",
+		"spec.containers.name",
+		"spec.containers.image",
+		"spec.template.spec.containers.name",
+		"spec.template.spec.containers.image",
+		"status.phase",
+		"status.replicas",
+		"status.readyReplicas",
+		"status.availableReplicas",
+		"status.conditions",
+	},
+}
+
+// BuiltinStrippingProfiles returns the built-in stripping profiles indexed by name
+func BuiltinStrippingProfiles() map[string]StrippingProfile {
+	return map[string]StrippingProfile{
+		ProfileMinimal:  MinimalProfile,
+		ProfileStandard: StandardProfile,
+		ProfileSummary:  SummaryProfile,
+	}
+}
+
+// LoadStrippingProfilesFromYAML loads the custom stripping profiles from a YAML file.
+// It returns the built-in profiles along with the custom ones, indexed by name. A custom profile
+// replaces the built-in profile with the same name.
+func LoadStrippingProfilesFromYAML(filePath string) (map[string]StrippingProfile, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config StrippingProfilesConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	profiles := BuiltinStrippingProfiles()
+	custom := map[string]bool{}
+	for _, profile := range config.Profiles {
+		if profile.Name == "" {
+			return nil, fmt.Errorf("stripping profile name is required")
+		}
+
+		if custom[profile.Name] {
+			return nil, fmt.Errorf("stripping profile '%s' is already defined", profile.Name)
+		}
+		custom[profile.Name] = true
+
+		for _, path := range profile.KeepFields {
+			if path == "" || slices.Contains(strings.Split(path, "."), "") {
+				return nil, fmt.Errorf("invalid keep field '%s' in stripping profile '%s'", path, profile.Name)
+			}
+		}
+
+		profiles[profile.Name] = profile
+	}
+
+	return profiles, nil
+}
+
+// apply removes the fields selected by the profile from an Unstructured object, in place
+func (p StrippingProfile) apply(obj *unstructured.Unstructured) {
+	if obj == nil || obj.Object == nil {
+		return // Nothing to do
+	}
+
+	if metadata, ok := obj.Object["metadata"].(map[string]any); ok {
+		for _, field := range p.RemoveMetadataFields {
+			delete(metadata, field)
+		}
+
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			for _, annotation := range p.RemoveAnnotations {
+				delete(annotations, annotation)
+			}
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+
+	if len(p.KeepFields) == 0 {
+		return
+	}
+
+	// The apiVersion and kind are always kept, they are required to encode the object
+	kept := map[string]any{}
+	for _, path := range append([]string{"apiVersion", "kind"}, p.KeepFields...) {
+		if projected, ok := projectField(obj.Object, strings.Split(path, "."), kept).(map[string]any); ok {
+			kept = projected
+		}
+	}
+	obj.Object = compactProjection(kept).(map[string]any)
+}
+
+// projectedArray is an array built by projectField. Its elements are aligned with the source array so that
+// several paths can be projected on the same elements, the elements where none of the paths exist are nil.
+type projectedArray []any
+
+// projectField copies the value at the path from the source into the destination and returns the destination.
+// When an array is found along the path, the rest of the path is projected on each of its elements.
+// The projected arrays must be converted with compactProjection once all the paths are projected.
+func projectField(source any, path []string, destination any) any {
+	if len(path) == 0 {
+		return source
+	}
+
+	switch v := source.(type) {
+	case map[string]any:
+		value, ok := v[path[0]]
+		if !ok {
+			return destination
+		}
+
+		destinationMap, ok := destination.(map[string]any)
+		if !ok {
+			destinationMap = map[string]any{}
+		}
+
+		if projected := projectField(value, path[1:], destinationMap[path[0]]); projected != nil {
+			destinationMap[path[0]] = projected
+		}
+		if len(destinationMap) == 0 {
+			return destination
+		}
+		return destinationMap
+	case []any:
+		destinationSlice, ok := destination.(projectedArray)
+		if !ok || len(destinationSlice) != len(v) {
+			destinationSlice = make(projectedArray, len(v))
+		}
+
+		found := false
+		for i, item := range v {
+			if projected := projectField(item, path, destinationSlice[i]); projected != nil {
+				destinationSlice[i] = projected
+				found = true
+			}
+		}
+		if !found {
+			return destination
+		}
+		return destinationSlice
+	default:
+		return destination
+	}
+}
+
+// compactProjection converts the projected arrays of a projected value to plain arrays, skipping the
+// elements where none of the projected paths exist
+func compactProjection(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			v[key] = compactProjection(field)
+		}
+		return v
+	case projectedArray:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				items = append(items, compactProjection(item))
+			}
+		}
+		return items
+	default:
+		return value
+	}
+}
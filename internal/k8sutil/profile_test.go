@@ -0,0 +1,295 @@
+package k8sutil
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deploymentWithMetadata = `{
+	"apiVersion": "apps/v1",
+	"kind": "Deployment",
+	"metadata": {
+		"name": "web",
+		"namespace": "default",
+		"uid": "6f1d2c3b",
+		"resourceVersion": "12345",
+		"generation": 3,
+		"labels": {"app": "web"},
+		"annotations": {
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			"deployment.kubernetes.io/revision": "3"
+		},
+		"managedFields": [{"manager": "kubectl"}]
+	},
+	"spec": {
+		"replicas": 2,
+		"strategy": {"type": "RollingUpdate"},
+		"template": {
+			"spec": {
+				"containers": [
+					{"name": "nginx", "image": "nginx:1.27", "ports": [{"containerPort": 80}]},
+					{"name": "sidecar", "image": "busybox"}
+				]
+			}
+		}
+	},
+	"status": {
+		"replicas": 2,
+		"readyReplicas": 1,
+		"observedGeneration": 3,
+		"conditions": [{"type": "Available", "status": "False"}]
+	}
+}`
+
+func TestProcessRawKubernetesAPIResponseWithProfile(t *testing.T) {
+	tests := []struct {
+		name           string
+		profile        StrippingProfile
+		expectedResult string
+	}{
+		{
+			name:    "minimal profile",
+			profile: MinimalProfile,
+			expectedResult: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {
+					"name": "web",
+					"namespace": "default",
+					"uid": "6f1d2c3b",
+					"resourceVersion": "12345",
+					"generation": 3,
+					"labels": {"app": "web"},
+					"annotations": {
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+						"deployment.kubernetes.io/revision": "3"
+					}
+				},
+				"spec": {
+					"replicas": 2,
+					"strategy": {"type": "RollingUpdate"},
+					"template": {
+						"spec": {
+							"containers": [
+								{"name": "nginx", "image": "nginx:1.27", "ports": [{"containerPort": 80}]},
+								{"name": "sidecar", "image": "busybox"}
+							]
+						}
+					}
+				},
+				"status": {
+					"replicas": 2,
+					"readyReplicas": 1,
+					"observedGeneration": 3,
+					"conditions": [{"type": "Available", "status": "False"}]
+				}
+			}`,
+		},
+		{
+			name:    "standard profile",
+			profile: StandardProfile,
+			expectedResult: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {
+					"name": "web",
+					"namespace": "default",
+					"labels": {"app": "web"},
+					"annotations": {"deployment.kubernetes.io/revision": "3"}
+				},
+				"spec": {
+					"replicas": 2,
+					"strategy": {"type": "RollingUpdate"},
+					"template": {
+						"spec": {
+							"containers": [
+								{"name": "nginx", "image": "nginx:1.27", "ports": [{"containerPort": 80}]},
+								{"name": "sidecar", "image": "busybox"}
+							]
+						}
+					}
+				},
+				"status": {
+					"replicas": 2,
+					"readyReplicas": 1,
+					"observedGeneration": 3,
+					"conditions": [{"type": "Available", "status": "False"}]
+				}
+			}`,
+		},
+		{
+			name:    "summary profile",
+			profile: SummaryProfile,
+			expectedResult: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {
+					"name": "web",
+					"namespace": "default",
+					"labels": {"app": "web"}
+				},
+				"spec": {
+					"replicas": 2,
+					"template": {
+						"spec": {
+							"containers": [
+								{"name": "nginx", "image": "nginx:1.27"},
+								{"name": "sidecar", "image": "busybox"}
+							]
+						}
+					}
+				},
+				"status": {
+					"replicas": 2,
+					"readyReplicas": 1,
+					"conditions": [{"type": "Available", "status": "False"}]
+				}
+			}`,
+		},
+		{
+			name: "custom profile",
+			profile: StrippingProfile{
+				Name:       "images",
+				KeepFields: []string{"metadata.name", "spec.template.spec.containers.image"},
+			},
+			expectedResult: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {"name": "web"},
+				"spec": {"template": {"spec": {"containers": [{"image": "nginx:1.27"}, {"image": "busybox"}]}}}
+			}`,
+		},
+		{
+			name: "custom profile with a path missing from some array items",
+			profile: StrippingProfile{
+				Name:       "ports",
+				KeepFields: []string{"spec.template.spec.containers.ports"},
+			},
+			// The sidecar container has no ports, it is skipped instead of being kept as null
+			expectedResult: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"spec": {"template": {"spec": {"containers": [{"ports": [{"containerPort": 80}]}]}}}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessRawKubernetesAPIResponseWithProfile(createJSONResponse(http.StatusOK, deploymentWithMetadata), tt.profile)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, string(result))
+		})
+	}
+}
+
+func TestProcessRawKubernetesAPIResponseWithProfile_List(t *testing.T) {
+	list := `{
+		"apiVersion": "v1",
+		"kind": "PodList",
+		"metadata": {"resourceVersion": "999"},
+		"items": [
+			{"metadata": {"name": "web-1", "uid": "1", "managedFields": []}, "spec": {"nodeName": "node-1", "restartPolicy": "Always"}, "status": {"phase": "Running"}},
+			{"metadata": {"name": "web-2", "uid": "2"}, "spec": {"nodeName": "node-2"}, "status": {"phase": "Pending"}}
+		]
+	}`
+
+	result, err := ProcessRawKubernetesAPIResponseWithProfile(createJSONResponse(http.StatusOK, list), SummaryProfile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"apiVersion": "v1",
+		"kind": "PodList",
+		"metadata": {"resourceVersion": "999"},
+		"items": [
+			{"metadata": {"name": "web-1"}, "spec": {"nodeName": "node-1"}, "status": {"phase": "Running"}},
+			{"metadata": {"name": "web-2"}, "spec": {"nodeName": "node-2"}, "status": {"phase": "Pending"}}
+		]
+	}`, string(result))
+}
+
+func TestLoadStrippingProfilesFromYAML(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expectedProfiles []string
+		expectedError    string
+	}{
+		{
+			name: "custom profiles",
+			content: `profiles:
+  - name: images
+    keepFields:
+      - metadata.name
+      - spec.containers.image
+  - name: no-annotations
+    removeMetadataFields:
+      - annotations
+`,
+			expectedProfiles: []string{ProfileMinimal, ProfileStandard, ProfileSummary, "images", "no-annotations"},
+		},
+		{
+			name:             "no custom profiles",
+			content:          "profiles: []\n",
+			expectedProfiles: []string{ProfileMinimal, ProfileStandard, ProfileSummary},
+		},
+		{
+			name: "missing name",
+			content: `profiles:
+  - keepFields: [metadata.name]
+`,
+			expectedError: "stripping profile name is required",
+		},
+		{
+			name: "redefined built-in profile",
+			content: `profiles:
+  - name: minimal
+    removeMetadataFields: [uid]
+`,
+			expectedProfiles: []string{ProfileMinimal, ProfileStandard, ProfileSummary},
+		},
+		{
+			name: "duplicated custom profile",
+			content: `profiles:
+  - name: images
+  - name: images
+`,
+			expectedError: "stripping profile 'images' is already defined",
+		},
+		{
+			name: "invalid keep field",
+			content: `profiles:
+  - name: broken
+    keepFields: [metadata..name]
+`,
+			expectedError: "invalid keep field 'metadata..name' in stripping profile 'broken'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "profiles.yaml")
+			require.NoError(t, os.WriteFile(filePath, []byte(tt.content), 0644))
+
+			profiles, err := LoadStrippingProfilesFromYAML(filePath)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(profiles))
+			for name := range profiles {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, tt.expectedProfiles, names)
+		})
+	}
+
+	_, err := LoadStrippingProfilesFromYAML(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -32,9 +32,6 @@ func removeManagedFieldsFromUnstructuredObject(obj *unstructured.Unstructured) e
 	// Delete the managedFields key from the metadata map
 	delete(metadataMap, "managedFields")
 
-	// TODO: Consider also removing other verbose fields here, e.g., ownerReferences, if needed.
-	// delete(metadataMap, "ownerReferences")
-
 	// Set the modified metadata back to the object
 	err = unstructured.SetNestedField(obj.Object, metadataMap, "metadata")
 	if err != nil {
@@ -44,9 +41,16 @@ func removeManagedFieldsFromUnstructuredObject(obj *unstructured.Unstructured) e
 }
 
 // ProcessRawKubernetesAPIResponse takes an HTTP response, processes the JSON body,
-// removes managedFields (and potentially other verbose metadata) from any Kubernetes resource(s) found,
+// removes managedFields from any Kubernetes resource(s) found,
 // and returns the modified JSON bytes.
 func ProcessRawKubernetesAPIResponse(httpResp *http.Response) ([]byte, error) {
+	return ProcessRawKubernetesAPIResponseWithProfile(httpResp, MinimalProfile)
+}
+
+// ProcessRawKubernetesAPIResponseWithProfile takes an HTTP response, processes the JSON body,
+// removes managedFields and the fields selected by the stripping profile from any Kubernetes resource(s) found,
+// and returns the modified JSON bytes.
+func ProcessRawKubernetesAPIResponseWithProfile(httpResp *http.Response, profile StrippingProfile) ([]byte, error) {
 	if httpResp == nil {
 		return nil, fmt.Errorf("http response is nil")
 	}
@@ -86,6 +90,7 @@ func ProcessRawKubernetesAPIResponse(httpResp *http.Response) ([]byte, error) {
 			if err := removeManagedFieldsFromUnstructuredObject(&list.Items[i]); err != nil {
 				return nil, fmt.Errorf("failed to remove managedFields from item %d in list: %w", i, err)
 			}
+			profile.apply(&list.Items[i])
 		}
 		return json.Marshal(list)
 	} else {
@@ -95,6 +100,7 @@ func ProcessRawKubernetesAPIResponse(httpResp *http.Response) ([]byte, error) {
 		if err := removeManagedFieldsFromUnstructuredObject(uObj); err != nil {
 			return nil, fmt.Errorf("failed to remove managedFields from single object: %w", err)
 		}
+		profile.apply(uObj)
 		return json.Marshal(uObj)
 	}
 }
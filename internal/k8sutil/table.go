@@ -0,0 +1,126 @@
+package k8sutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	// TableAcceptHeader is the Accept header requesting the server-side Table format.
+	// The resources that do not support it are rejected with a 406 Not Acceptable response.
+	TableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io"
+	// tableColumnPadding is the number of spaces between the columns, as used by kubectl
+	tableColumnPadding = 3
+)
+
+// table is a meta.k8s.io/v1 Table returned by the Kubernetes API
+type table struct {
+	Kind              string                  `json:"kind"`
+	ColumnDefinitions []tableColumnDefinition `json:"columnDefinitions"`
+	Rows              []tableRow              `json:"rows"`
+}
+
+// tableColumnDefinition describes a column of a Table
+type tableColumnDefinition struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// tableRow is a row of a Table, with the partial object metadata when requested with includeObject=Metadata
+type tableRow struct {
+	Cells  []any `json:"cells"`
+	Object struct {
+		Metadata struct {
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	} `json:"object"`
+}
+
+// FormatTable renders a Table returned by the Kubernetes API as a text table similar to the output of kubectl get.
+// Only the columns with priority 0 are rendered, unless wide is true. A NAMESPACE column is added when the
+// rows belong to several namespaces.
+func FormatTable(data []byte, wide bool) (string, error) {
+	var t table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return "", fmt.Errorf("failed to unmarshal Table: %w", err)
+	}
+
+	if t.Kind != "Table" {
+		return "", fmt.Errorf("the Kubernetes API did not return a Table for this resource (kind: %s)", t.Kind)
+	}
+
+	if len(t.Rows) == 0 {
+		return "No resources found.", nil
+	}
+
+	var columns []int
+	for i, column := range t.ColumnDefinitions {
+		if wide || column.Priority == 0 {
+			columns = append(columns, i)
+		}
+	}
+
+	withNamespace := hasSeveralNamespaces(t.Rows)
+
+	header := make([]string, 0, len(columns)+1)
+	if withNamespace {
+		header = append(header, "NAMESPACE")
+	}
+	for _, i := range columns {
+		header = append(header, strings.ToUpper(t.ColumnDefinitions[i].Name))
+	}
+
+	lines := [][]string{header}
+	for _, row := range t.Rows {
+		line := make([]string, 0, len(header))
+		if withNamespace {
+			line = append(line, row.Object.Metadata.Namespace)
+		}
+		for _, i := range columns {
+			var cell any
+			if i < len(row.Cells) {
+				cell = row.Cells[i]
+			}
+			line = append(line, formatTableCell(cell))
+		}
+		lines = append(lines, line)
+	}
+
+	var sb strings.Builder
+	writer := tabwriter.NewWriter(&sb, 0, 0, tableColumnPadding, ' ', 0)
+	for _, line := range lines {
+		fmt.Fprintln(writer, strings.Join(line, "\t"))
+	}
+	if err := writer.Flush(); err != nil {
+		return "", fmt.Errorf("failed to render Table: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// hasSeveralNamespaces checks if the rows belong to more than one namespace
+func hasSeveralNamespaces(rows []tableRow) bool {
+	for _, row := range rows[1:] {
+		if row.Object.Metadata.Namespace != rows[0].Object.Metadata.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTableCell formats a Table cell, the empty cells are left blank
+func formatTableCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
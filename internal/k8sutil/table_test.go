@@ -0,0 +1,92 @@
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const podTable = `{
+	"kind": "Table",
+	"apiVersion": "meta.k8s.io/v1",
+	"columnDefinitions": [
+		{"name": "Name", "type": "string", "priority": 0},
+		{"name": "Ready", "type": "string", "priority": 0},
+		{"name": "Status", "type": "string", "priority": 0},
+		{"name": "Restarts", "type": "string", "priority": 0},
+		{"name": "IP", "type": "string", "priority": 1},
+		{"name": "Nominated Node", "type": "string", "priority": 1}
+	],
+	"rows": [
+		{"cells": ["web-7d9c", "1/1", "Running", 0, "10.0.0.12", null], "object": {"metadata": {"name": "web-7d9c", "namespace": "default"}}},
+		{"cells": ["coredns-5d78", "0/1", "CrashLoopBackOff", 12, "10.0.0.3", ""], "object": {"metadata": {"name": "coredns-5d78", "namespace": "kube-system"}}}
+	]
+}`
+
+func TestFormatTable(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		wide          bool
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "default columns with namespaces",
+			data: podTable,
+			expected: "NAMESPACE     NAME           READY   STATUS             RESTARTS\n" +
+				"default       web-7d9c       1/1     Running            0\n" +
+				"kube-system   coredns-5d78   0/1     CrashLoopBackOff   12\n",
+		},
+		{
+			name: "wide columns",
+			data: podTable,
+			wide: true,
+			expected: "NAMESPACE     NAME           READY   STATUS             RESTARTS   IP          NOMINATED NODE\n" +
+				"default       web-7d9c       1/1     Running            0          10.0.0.12   \n" +
+				"kube-system   coredns-5d78   0/1     CrashLoopBackOff   12         10.0.0.3    \n",
+		},
+		{
+			name: "single namespace",
+			data: `{
+				"kind": "Table",
+				"columnDefinitions": [{"name": "Name", "priority": 0}, {"name": "Age", "priority": 0}],
+				"rows": [
+					{"cells": ["web", "2d"], "object": {"metadata": {"namespace": "default"}}},
+					{"cells": ["api", "5h"], "object": {"metadata": {"namespace": "default"}}}
+				]
+			}`,
+			expected: "NAME   AGE\n" +
+				"web    2d\n" +
+				"api    5h\n",
+		},
+		{
+			name:     "no rows",
+			data:     `{"kind": "Table", "columnDefinitions": [{"name": "Name", "priority": 0}], "rows": []}`,
+			expected: "No resources found.",
+		},
+		{
+			name:          "not a table",
+			data:          `{"kind": "PodList", "items": []}`,
+			expectedError: "the Kubernetes API did not return a Table for this resource (kind: PodList)",
+		},
+		{
+			name:          "invalid JSON",
+			data:          "invalid json",
+			expectedError: "failed to unmarshal Table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FormatTable([]byte(tt.data), tt.wide)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
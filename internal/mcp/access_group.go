@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -25,17 +24,24 @@ func (s *PortainerMCPServer) AddAccessGroupFeatures() {
 
 func (s *PortainerMCPServer) HandleGetAccessGroups() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
 		accessGroups, err := s.cli.GetAccessGroups()
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get access groups", err), nil
 		}
 
-		data, err := json.Marshal(accessGroups)
+		data, err := output.render(accessGroups)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to marshal access groups", err), nil
+			return mcp.NewToolResultErrorFromErr("failed to render access groups", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(data), nil
 	}
 }
 
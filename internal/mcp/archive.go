@@ -0,0 +1,305 @@
+package mcp
+
+import (
+	"archive/tar"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// defaultContainerFilesDepth is the number of directory levels listed by listContainerFiles when depth is not specified
+	defaultContainerFilesDepth = 1
+	// maxContainerFilesDepth is the maximum number of directory levels listed by listContainerFiles
+	maxContainerFilesDepth = 5
+	// maxContainerFilesEntries is the maximum number of entries returned by listContainerFiles
+	maxContainerFilesEntries = 1000
+	// maxContainerArchiveSize is the maximum number of bytes of a container archive read to list a directory,
+	// the archive includes the content of all the files below the directory
+	maxContainerArchiveSize = 64 * 1024 * 1024
+	// defaultContainerFileMaxBytes is the number of bytes returned by readContainerFile when maxBytes is not specified
+	defaultContainerFileMaxBytes = 64 * 1024
+	// maxContainerFileMaxBytes is the maximum number of bytes returned by readContainerFile
+	maxContainerFileMaxBytes = 1024 * 1024
+	// maxContainerFileSymlinks is the maximum number of symbolic links followed by readContainerFile
+	maxContainerFileSymlinks = 5
+	// binaryDetectionSize is the number of bytes inspected to detect binary files, as done by git
+	binaryDetectionSize = 8000
+)
+
+func (s *PortainerMCPServer) HandleListContainerFiles() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		filePath, err := parser.GetString("path", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid path parameter", err), nil
+		}
+		if !path.IsAbs(filePath) {
+			return mcp.NewToolResultError("path must be an absolute path"), nil
+		}
+
+		depth, err := parser.GetInt("depth", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid depth parameter", err), nil
+		}
+		if depth == 0 {
+			depth = defaultContainerFilesDepth
+		}
+		if depth < 1 || depth > maxContainerFilesDepth {
+			return mcp.NewToolResultError(fmt.Sprintf("depth must be between 1 and %d", maxContainerFilesDepth)), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		archive, result, err := s.openContainerArchive(ctx, environmentId, containerId, filePath)
+		if result != nil || err != nil {
+			return result, err
+		}
+		defer archive.Close()
+
+		files, truncated, err := listArchive(io.LimitReader(archive, maxContainerArchiveSize), path.Clean(filePath), depth)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to read container archive", err), nil
+		}
+
+		data, err := output.render(files)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render container files", err), nil
+		}
+
+		if truncated {
+			data += fmt.Sprintf("\n\nThe listing is incomplete, it is limited to %d entries and %s of archive. List a subdirectory or use a lower depth.",
+				maxContainerFilesEntries, formatSize(maxContainerArchiveSize))
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleReadContainerFile() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		filePath, err := parser.GetString("path", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid path parameter", err), nil
+		}
+		if !path.IsAbs(filePath) {
+			return mcp.NewToolResultError("path must be an absolute path"), nil
+		}
+
+		maxBytes, err := parser.GetInt("maxBytes", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid maxBytes parameter", err), nil
+		}
+		if maxBytes == 0 {
+			maxBytes = defaultContainerFileMaxBytes
+		}
+		if maxBytes < 1 || maxBytes > maxContainerFileMaxBytes {
+			return mcp.NewToolResultError(fmt.Sprintf("maxBytes must be between 1 and %d", maxContainerFileMaxBytes)), nil
+		}
+
+		filePath = path.Clean(filePath)
+		for range maxContainerFileSymlinks + 1 {
+			archive, result, err := s.openContainerArchive(ctx, environmentId, containerId, filePath)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			header, content, err := readArchiveFile(archive, int64(maxBytes))
+			archive.Close()
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to read container archive", err), nil
+			}
+
+			switch header.Typeflag {
+			case tar.TypeSymlink:
+				// The archive contains the link itself, the target is requested instead
+				target := header.Linkname
+				if !path.IsAbs(target) {
+					target = path.Join(path.Dir(filePath), target)
+				}
+				filePath = target
+				continue
+			case tar.TypeReg:
+				return mcp.NewToolResultText(formatContainerFile(filePath, header, content)), nil
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("%s is a %s, not a file", filePath, models.DockerContainerFileType(header))), nil
+			}
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("too many levels of symbolic links, the last target is %s", filePath)), nil
+	}
+}
+
+// openContainerArchive requests the tar archive of a path inside a container. A tool result is returned instead
+// when the request is not successful, to be returned as is by the handler. The caller must close the archive.
+func (s *PortainerMCPServer) openContainerArchive(ctx context.Context, environmentId int, containerId, filePath string) (io.ReadCloser, *mcp.CallToolResult, error) {
+	response, err := s.cli.ProxyDockerRequest(models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          fmt.Sprintf("/containers/%s/archive", url.PathEscape(containerId)),
+		Method:        "GET",
+		QueryParams:   map[string]string{"path": filePath},
+	})
+	if err != nil {
+		return nil, mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+	}
+
+	if !isSuccessStatusCode(response.StatusCode) {
+		defer response.Body.Close()
+
+		responseBody, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, mcp.NewToolResultErrorFromErr("failed to read Docker API response", err), nil
+		}
+		return nil, s.proxyResult(ctx, response, responseBody), nil
+	}
+
+	return response.Body, nil, nil
+}
+
+// listArchive lists the entries of a container archive up to depth levels below the requested path, without
+// the requested path itself unless it is not a directory. The archive is rooted at the base name of the requested
+// path. The listing is truncated when there are too many entries or when the archive ends unexpectedly, which
+// happens when it exceeds the size read.
+func listArchive(archive io.Reader, root string, depth int) ([]models.DockerContainerFile, bool, error) {
+	reader := tar.NewReader(archive)
+
+	files := []models.DockerContainerFile{}
+	rootName := ""
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return sortContainerFiles(files), true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		name := strings.TrimSuffix(header.Name, "/")
+		if rootName == "" {
+			rootName = name
+			if header.Typeflag != tar.TypeDir {
+				files = append(files, models.ConvertToDockerContainerFile(header, root))
+			}
+			continue
+		}
+
+		relativePath := strings.TrimPrefix(strings.TrimPrefix(name, rootName), "/")
+		if relativePath == "" || strings.Count(relativePath, "/") >= depth {
+			continue
+		}
+
+		if len(files) == maxContainerFilesEntries {
+			return sortContainerFiles(files), true, nil
+		}
+		files = append(files, models.ConvertToDockerContainerFile(header, path.Join(root, relativePath)))
+	}
+
+	return sortContainerFiles(files), false, nil
+}
+
+// sortContainerFiles sorts the entries of a listing by path, whether it is complete or truncated
+func sortContainerFiles(files []models.DockerContainerFile) []models.DockerContainerFile {
+	slices.SortFunc(files, func(a, b models.DockerContainerFile) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return files
+}
+
+// readArchiveFile reads the first entry of a container archive, which is the requested path, and up to maxBytes of
+// its content
+func readArchiveFile(archive io.Reader, maxBytes int64) (*tar.Header, []byte, error) {
+	reader := tar.NewReader(archive)
+
+	header, err := reader.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := io.ReadAll(io.LimitReader(reader, maxBytes))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return header, content, nil
+}
+
+// formatContainerFile renders the content of a file, or a description of the file when it is binary.
+// A note is added when the content is truncated.
+func formatContainerFile(filePath string, header *tar.Header, content []byte) string {
+	if len(content) == 0 {
+		return fmt.Sprintf("%s is an empty file.", filePath)
+	}
+
+	if isBinaryContent(content) {
+		return fmt.Sprintf("%s is a binary file (%s, %s), its content is not displayed.",
+			filePath, formatSize(header.Size), http.DetectContentType(content))
+	}
+
+	if int64(len(content)) < header.Size {
+		return fmt.Sprintf("%s\n\n[truncated: showing the first %s of %s, use maxBytes to read more]",
+			content, formatSize(int64(len(content))), formatSize(header.Size))
+	}
+
+	return string(content)
+}
+
+// isBinaryContent checks if the content of a file is binary: it contains a NUL byte or is not valid UTF-8
+// in the first bytes
+func isBinaryContent(content []byte) bool {
+	sample := content[:min(len(content), binaryDetectionSize)]
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+
+	// The sample can end in the middle of a multi-byte character
+	for range utf8.UTFMax - 1 {
+		if utf8.Valid(sample) || len(sample) == 0 {
+			break
+		}
+		sample = sample[:len(sample)-1]
+	}
+	return !utf8.Valid(sample)
+}
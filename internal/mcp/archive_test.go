@@ -0,0 +1,334 @@
+package mcp
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveEntry is an entry of a test container archive
+type archiveEntry struct {
+	name     string
+	typeflag byte
+	content  string
+	linkname string
+}
+
+// createArchive builds a container archive as returned by the Docker API
+func createArchive(t *testing.T, entries ...archiveEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Size:     int64(len(entry.content)),
+			Linkname: entry.linkname,
+			Mode:     0o644,
+			ModTime:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if entry.typeflag == tar.TypeDir {
+			header.Mode = 0o755
+		}
+		require.NoError(t, writer.WriteHeader(header))
+		_, err := writer.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	return buf.String()
+}
+
+// matchArchivePath matches the archive requests of a container path
+func matchArchivePath(containerId, path string) any {
+	return mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" &&
+			opts.Path == "/containers/"+containerId+"/archive" &&
+			opts.QueryParams["path"] == path
+	})
+}
+
+func TestHandleListContainerFiles(t *testing.T) {
+	nginxArchive := []archiveEntry{
+		{name: "nginx/", typeflag: tar.TypeDir},
+		{name: "nginx/conf.d/", typeflag: tar.TypeDir},
+		{name: "nginx/conf.d/default.conf", typeflag: tar.TypeReg, content: "server {}"},
+		{name: "nginx/nginx.conf", typeflag: tar.TypeReg, content: "worker_processes auto;"},
+		{name: "nginx/mime.types", typeflag: tar.TypeSymlink, linkname: "/usr/share/mime.types"},
+	}
+
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "directory content",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc/nginx/",
+				"outputFormat":  "csv",
+				"fields":        []any{"path", "type", "size", "link_target"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc/nginx/")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t, nginxArchive...)), nil)
+			},
+			expectedText: "path,type,size,link_target\n" +
+				"/etc/nginx/conf.d,directory,,\n" +
+				"/etc/nginx/mime.types,symlink,,/usr/share/mime.types\n" +
+				"/etc/nginx/nginx.conf,file,22B,\n",
+		},
+		{
+			name: "subdirectories content",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc/nginx",
+				"depth":         float64(2),
+				"outputFormat":  "csv",
+				"fields":        []any{"path"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc/nginx")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t, nginxArchive...)), nil)
+			},
+			expectedText: "path\n" +
+				"/etc/nginx/conf.d\n" +
+				"/etc/nginx/conf.d/default.conf\n" +
+				"/etc/nginx/mime.types\n" +
+				"/etc/nginx/nginx.conf\n",
+		},
+		{
+			name: "single file",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc/hostname",
+				"outputFormat":  "csv",
+				"fields":        []any{"path", "type", "mode"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc/hostname")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "hostname", typeflag: tar.TypeReg, content: "web\n"})), nil)
+			},
+			expectedText: "path,type,mode\n/etc/hostname,file,-rw-r--r--\n",
+		},
+		{
+			name: "path not found",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/missing",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/missing")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{"message":"Could not find the file /missing in container web"}`), nil)
+			},
+			expectedText:  "HTTP 404 Not Found\nError: Could not find the file /missing in container web",
+			expectedError: true,
+		},
+		{
+			name: "relative path",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "etc",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "path must be an absolute path",
+			expectedError: true,
+		},
+		{
+			name: "depth out of range",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/",
+				"depth":         float64(10),
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "depth must be between 1 and 5",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleListContainerFiles()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListArchive_Truncated(t *testing.T) {
+	archive := createArchive(t,
+		archiveEntry{name: "data/", typeflag: tar.TypeDir},
+		archiveEntry{name: "data/b.log", typeflag: tar.TypeReg, content: "b"},
+		archiveEntry{name: "data/a.log", typeflag: tar.TypeReg, content: string(make([]byte, 4096))},
+	)
+
+	// The archive is cut in the middle of the content of the last file, the entries are still sorted
+	files, truncated, err := listArchive(bytes.NewReader([]byte(archive[:3*512+1024])), "/data", 1)
+
+	assert.NoError(t, err)
+	assert.True(t, truncated)
+	require.Len(t, files, 2)
+	assert.Equal(t, "/data/a.log", files[0].Path)
+	assert.Equal(t, "/data/b.log", files[1].Path)
+}
+
+func TestHandleReadContainerFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "text file",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc/nginx/nginx.conf",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc/nginx/nginx.conf")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "nginx.conf", typeflag: tar.TypeReg, content: "worker_processes auto;\n"})), nil)
+			},
+			expectedText: "worker_processes auto;\n",
+		},
+		{
+			name: "truncated text file",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/var/log/app.log",
+				"maxBytes":      float64(10),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/var/log/app.log")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "app.log", typeflag: tar.TypeReg, content: "first line\nsecond line\n"})), nil)
+			},
+			expectedText: "first line\n\n[truncated: showing the first 10B of 23B, use maxBytes to read more]",
+		},
+		{
+			name: "binary file",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/usr/sbin/nginx",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/usr/sbin/nginx")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "nginx", typeflag: tar.TypeReg, content: "\x7fELF\x02\x01\x01\x00\x00\x00"})), nil)
+			},
+			expectedText: "/usr/sbin/nginx is a binary file (10B, application/octet-stream), its content is not displayed.",
+		},
+		{
+			name: "symbolic link is followed",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc/nginx/conf.d/site.conf",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc/nginx/conf.d/site.conf")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "site.conf", typeflag: tar.TypeSymlink, linkname: "../sites/site.conf"})), nil)
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc/nginx/sites/site.conf")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "site.conf", typeflag: tar.TypeReg, content: "server {}"})), nil)
+			},
+			expectedText: "server {}",
+		},
+		{
+			name: "directory",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", matchArchivePath("web", "/etc")).
+					Return(createMockHttpResponse(http.StatusOK, createArchive(t,
+						archiveEntry{name: "etc/", typeflag: tar.TypeDir},
+						archiveEntry{name: "etc/hostname", typeflag: tar.TypeReg, content: "web\n"})), nil)
+			},
+			expectedText:  "/etc is a directory, not a file",
+			expectedError: true,
+		},
+		{
+			name: "maxBytes out of range",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"path":          "/etc/hostname",
+				"maxBytes":      float64(2 * 1024 * 1024),
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "maxBytes must be between 1 and 1048576",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleReadContainerFile()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIsBinaryContent(t *testing.T) {
+	assert.False(t, isBinaryContent([]byte("plain text\n")))
+	assert.False(t, isBinaryContent([]byte("caf\xc3\xa9")))
+	// A multi-byte character cut by the size limit
+	assert.False(t, isBinaryContent([]byte("caf\xc3")))
+	assert.True(t, isBinaryContent([]byte("text\x00more")))
+	assert.True(t, isBinaryContent([]byte{0xff, 0xfe, 0x41, 0x42}))
+}
@@ -0,0 +1,828 @@
+package mcp
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/dockerutil"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// defaultContainerLogsTail is the number of log lines returned when tail is not specified
+	defaultContainerLogsTail = 100
+	// defaultContainerStatsLimit is the number of containers returned by getContainerStats when limit is not specified
+	defaultContainerStatsLimit = 10
+	// containerStatsConcurrency is the maximum number of stats requests sent in parallel to the Docker API
+	containerStatsConcurrency = 8
+)
+
+// containerStatsSortKeys maps the sortBy values of the getContainerStats tool to the value used to sort the containers
+var containerStatsSortKeys = map[string]func(models.DockerContainerStats) float64{
+	"cpu":     func(stats models.DockerContainerStats) float64 { return stats.CPUPercent },
+	"memory":  func(stats models.DockerContainerStats) float64 { return float64(stats.MemoryBytes) },
+	"network": func(stats models.DockerContainerStats) float64 { return float64(stats.NetworkBytes) },
+	"blockio": func(stats models.DockerContainerStats) float64 { return float64(stats.BlockIOBytes) },
+	"pids":    func(stats models.DockerContainerStats) float64 { return float64(stats.PIDs) },
+}
+
+// ansiEscapeSequencePattern matches the ANSI escape sequences (colors, cursor movements, terminal titles)
+var ansiEscapeSequencePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// Container states accepted by the status filter of the listContainers tool
+var containerStatuses = []string{"created", "restarting", "running", "removing", "paused", "exited", "dead"}
+
+func (s *PortainerMCPServer) AddContainerFeatures() {
+	s.addToolIfExists(ToolListContainers, s.HandleListContainers())
+	s.addToolIfExists(ToolInspectContainer, s.HandleInspectContainer())
+	s.addToolIfExists(ToolContainerTop, s.HandleContainerTop())
+	s.addToolIfExists(ToolGetContainerStats, s.HandleGetContainerStats())
+	s.addToolIfExists(ToolGetContainerLogs, s.HandleGetContainerLogs())
+	s.addToolIfExists(ToolListContainerFiles, s.HandleListContainerFiles())
+	s.addToolIfExists(ToolReadContainerFile, s.HandleReadContainerFile())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolStartContainer, s.HandleStartContainer())
+		s.addToolIfExists(ToolStopContainer, s.HandleStopContainer())
+		s.addToolIfExists(ToolRestartContainer, s.HandleRestartContainer())
+		s.addToolIfExists(ToolRemoveContainer, s.HandleRemoveContainer())
+		s.addToolIfExists(ToolPruneContainers, s.HandlePruneContainers())
+		s.addToolIfExists(ToolExecInContainer, s.HandleExecInContainer())
+	}
+}
+
+func (s *PortainerMCPServer) HandleListContainers() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		all, err := parser.GetBoolean("all", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid all parameter", err), nil
+		}
+
+		status, err := parser.GetString("status", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid status parameter", err), nil
+		}
+		if status != "" && !slices.Contains(containerStatuses, status) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid status: %s, valid statuses are: %s", status, strings.Join(containerStatuses, ", "))), nil
+		}
+
+		name, err := parser.GetString("name", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+		}
+
+		labels, err := parser.GetArrayOfStrings("labels", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid labels parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		filters := map[string][]string{}
+		if status != "" {
+			filters["status"] = []string{status}
+			all = true // The stopped containers are only returned with all=true
+		}
+		if name != "" {
+			filters["name"] = []string{name}
+		}
+		if len(labels) > 0 {
+			filters["label"] = labels
+		}
+
+		queryParams := map[string]string{
+			"all": strconv.FormatBool(all),
+		}
+		if len(filters) > 0 {
+			encodedFilters, err := json.Marshal(filters)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to encode filters", err), nil
+			}
+			queryParams["filters"] = string(encodedFilters)
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/containers/json",
+			Method:        "GET",
+			QueryParams:   queryParams,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var rawContainers []container.Summary
+		if err := json.Unmarshal(responseBody, &rawContainers); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode containers", err), nil
+		}
+
+		containers := make([]models.DockerContainer, len(rawContainers))
+		for i, rawContainer := range rawContainers {
+			containers[i] = models.ConvertToDockerContainer(rawContainer)
+		}
+
+		data, err := output.render(containers)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render containers", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleInspectContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s/json", url.PathEscape(containerId)),
+			Method:        "GET",
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		containerDetails, err := dockerutil.StripDockerResources(responseBody)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to process Docker API response", err), nil
+		}
+
+		result, err := s.responses.result(ctx, containerDetails)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to buffer Docker API response", err), nil
+		}
+
+		return result, nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleContainerTop() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		psArgs, err := parser.GetString("psArgs", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid psArgs parameter", err), nil
+		}
+
+		opts := models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s/top", url.PathEscape(containerId)),
+			Method:        "GET",
+		}
+		if psArgs != "" {
+			opts.QueryParams = map[string]string{"ps_args": psArgs}
+		}
+
+		response, responseBody, err := s.sendDockerRequest(opts)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var top container.TopResponse
+		if err := json.Unmarshal(responseBody, &top); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode container processes", err), nil
+		}
+
+		return mcp.NewToolResultText(formatContainerTop(top)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleGetContainerStats() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		sortBy, err := parser.GetString("sortBy", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid sortBy parameter", err), nil
+		}
+		if sortBy == "" {
+			sortBy = "memory"
+		}
+		sortKey, ok := containerStatsSortKeys[sortBy]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid sortBy: %s, valid values are: %s", sortBy, strings.Join(slices.Sorted(maps.Keys(containerStatsSortKeys)), ", "))), nil
+		}
+
+		limit, err := parser.GetInt("limit", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid limit parameter", err), nil
+		}
+		if limit <= 0 {
+			limit = defaultContainerStatsLimit
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+		if format, _ := parser.GetString("outputFormat", false); format == "" {
+			output.format = OutputFormatMarkdown // A table is easier to compare than a list of objects
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/containers/json",
+			Method:        "GET",
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var rawContainers []container.Summary
+		if err := json.Unmarshal(responseBody, &rawContainers); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode containers", err), nil
+		}
+
+		stats, failures := s.collectContainerStats(ctx, environmentId, rawContainers, s.newProgressReporter(ctx, request))
+		if err := ctx.Err(); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to collect container stats", err), nil
+		}
+
+		slices.SortStableFunc(stats, func(a, b models.DockerContainerStats) int {
+			return cmp.Compare(sortKey(b), sortKey(a))
+		})
+		if len(stats) > limit {
+			stats = stats[:limit]
+		}
+
+		data, err := output.render(stats)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render container stats", err), nil
+		}
+
+		if len(failures) > 0 {
+			data += "\n\nThe stats of some containers could not be collected:\n- " + strings.Join(failures, "\n- ")
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleStartContainer() server.ToolHandlerFunc {
+	return s.handleContainerAction("start", "started", false)
+}
+
+func (s *PortainerMCPServer) HandleStopContainer() server.ToolHandlerFunc {
+	return s.handleContainerAction("stop", "stopped", true)
+}
+
+func (s *PortainerMCPServer) HandleRestartContainer() server.ToolHandlerFunc {
+	return s.handleContainerAction("restart", "restarted", true)
+}
+
+// handleContainerAction returns a handler that sends a POST /containers/{id}/{action} request.
+// The Docker API returns a 304 status code when the container is already in the requested state.
+func (s *PortainerMCPServer) handleContainerAction(action, pastTense string, withTimeout bool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		opts := models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s/%s", url.PathEscape(containerId), action),
+			Method:        "POST",
+		}
+
+		if withTimeout {
+			timeout, err := parser.GetInt("timeout", false)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid timeout parameter", err), nil
+			}
+			if timeout > 0 {
+				opts.QueryParams = map[string]string{"t": strconv.Itoa(timeout)}
+			}
+		}
+
+		response, responseBody, err := s.sendDockerRequest(opts)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+
+		switch {
+		case response.StatusCode == http.StatusNotModified:
+			return mcp.NewToolResultText(fmt.Sprintf("Container %s is already %s", containerId, pastTense)), nil
+		case !isSuccessStatusCode(response.StatusCode):
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s %s successfully", containerId, pastTense)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleRemoveContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		force, err := parser.GetBoolean("force", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid force parameter", err), nil
+		}
+
+		volumes, err := parser.GetBoolean("volumes", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid volumes parameter", err), nil
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s", url.PathEscape(containerId)),
+			Method:        "DELETE",
+			QueryParams: map[string]string{
+				"force": strconv.FormatBool(force),
+				"v":     strconv.FormatBool(volumes),
+			},
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s removed successfully", containerId)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandlePruneContainers() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		confirm, err := parser.GetBoolean("confirm", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid confirm parameter", err), nil
+		}
+
+		if confirm {
+			var report container.PruneReport
+			if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          "/containers/prune",
+				Method:        "POST",
+			}, &report); result != nil || err != nil {
+				return result, err
+			}
+
+			deleted := make([]string, len(report.ContainersDeleted))
+			for i, id := range report.ContainersDeleted {
+				deleted[i] = models.ShortDockerID(id)
+			}
+
+			return mcp.NewToolResultText(formatPruneReport("container", deleted, report.SpaceReclaimed)), nil
+		}
+
+		// The writable layer size of the containers is only computed by the disk usage endpoint
+		var usage types.DiskUsage
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/system/df",
+			Method:        "GET",
+			QueryParams:   map[string]string{"type": "container"},
+		}, &usage); result != nil || err != nil {
+			return result, err
+		}
+
+		var candidates []pruneCandidate
+		for _, rawContainer := range usage.Containers {
+			// The running, paused and restarting containers are not removed
+			if !slices.Contains([]string{"created", "exited", "dead"}, rawContainer.State) {
+				continue
+			}
+			candidates = append(candidates, pruneCandidate{
+				name: cmp.Or(models.DockerContainerName(*rawContainer), models.ShortDockerID(rawContainer.ID)),
+				size: rawContainer.SizeRw,
+			})
+		}
+
+		return mcp.NewToolResultText(formatPrunePreview(ToolPruneContainers, "container", candidates)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleGetContainerLogs() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		tail, err := parser.GetInt("tail", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid tail parameter", err), nil
+		}
+
+		since, err := parser.GetString("since", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid since parameter", err), nil
+		}
+
+		until, err := parser.GetString("until", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid until parameter", err), nil
+		}
+
+		timestamps, err := parser.GetBoolean("timestamps", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid timestamps parameter", err), nil
+		}
+
+		queryParams := map[string]string{
+			"stdout":     "true",
+			"stderr":     "true",
+			"timestamps": strconv.FormatBool(timestamps),
+		}
+
+		switch {
+		case tail < 0:
+			queryParams["tail"] = "all"
+		case tail == 0:
+			queryParams["tail"] = strconv.Itoa(defaultContainerLogsTail)
+		default:
+			queryParams["tail"] = strconv.Itoa(tail)
+		}
+
+		now := time.Now()
+		if since != "" {
+			sinceTimestamp, err := parseDockerTimestamp(since, now)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid since parameter", err), nil
+			}
+			queryParams["since"] = sinceTimestamp
+		}
+		if until != "" {
+			untilTimestamp, err := parseDockerTimestamp(until, now)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid until parameter", err), nil
+			}
+			queryParams["until"] = untilTimestamp
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s/logs", url.PathEscape(containerId)),
+			Method:        "GET",
+			QueryParams:   queryParams,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		logs, err := formatContainerLogs(responseBody)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode container logs", err), nil
+		}
+
+		result, err := s.responses.result(ctx, []byte(logs))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to buffer container logs", err), nil
+		}
+
+		return result, nil
+	}
+}
+
+// sendDockerRequest sends a request to the Docker API through the Portainer proxy and reads the response body
+func (s *PortainerMCPServer) sendDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, []byte, error) {
+	response, err := s.cli.ProxyDockerRequest(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	s.limitResponseBody(response)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Docker API response: %w", err)
+	}
+
+	return response, body, nil
+}
+
+// decodeDockerResponse sends a request to the Docker API and decodes the JSON response into target.
+// A tool result is returned when the request is not successful, to be returned as is by the handler.
+func (s *PortainerMCPServer) decodeDockerResponse(ctx context.Context, opts models.DockerProxyRequestOptions, target any) (*mcp.CallToolResult, error) {
+	response, responseBody, err := s.sendDockerRequest(opts)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return s.proxyResult(ctx, response, responseBody), nil
+	}
+
+	if err := json.Unmarshal(responseBody, target); err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to decode Docker API response", err), nil
+	}
+
+	return nil, nil
+}
+
+// containerNamesBy indexes the container names by the keys returned for each container, e.g. the
+// volumes mounted by the container. The names are sorted for each key.
+func containerNamesBy(containers []container.Summary, keys func(container.Summary) []string) map[string][]string {
+	names := map[string][]string{}
+	for _, rawContainer := range containers {
+		name := models.DockerContainerName(rawContainer)
+		for _, key := range keys(rawContainer) {
+			if !slices.Contains(names[key], name) {
+				names[key] = append(names[key], name)
+			}
+		}
+	}
+
+	for _, values := range names {
+		slices.Sort(values)
+	}
+	return names
+}
+
+// collectContainerStats fetches a stats snapshot of each container, with at most containerStatsConcurrency
+// requests in flight. The containers whose stats cannot be fetched (e.g. stopped in the meantime) are
+// reported as failures instead of failing the whole collection. The collection stops as soon as the
+// context is done, the caller must check the context before using the results.
+func (s *PortainerMCPServer) collectContainerStats(ctx context.Context, environmentId int, containers []container.Summary, progress *progressReporter) ([]models.DockerContainerStats, []string) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		stats     = make([]models.DockerContainerStats, 0, len(containers))
+		failures  []string
+		completed int
+	)
+
+	semaphore := make(chan struct{}, containerStatsConcurrency)
+	for _, rawContainer := range containers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+
+			containerStats, err := s.getContainerStatsSnapshot(ctx, environmentId, rawContainer.ID)
+			if ctx.Err() != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			name := models.DockerContainerName(rawContainer)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+			} else {
+				containerStats.Name = name
+				stats = append(stats, containerStats)
+			}
+
+			completed++
+			progress.report(float64(completed), float64(len(containers)), fmt.Sprintf("collected the stats of %s (%d/%d containers)", name, completed, len(containers)))
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(failures)
+	return stats, failures
+}
+
+// getContainerStatsSnapshot fetches a single stats sample of a container. With stream=false, the Docker API
+// waits for a second sample so that the CPU usage can be computed. The Portainer client cannot cancel the
+// request, it is abandoned when the context is done and its response is discarded once received.
+func (s *PortainerMCPServer) getContainerStatsSnapshot(ctx context.Context, environmentId int, containerId string) (models.DockerContainerStats, error) {
+	if err := ctx.Err(); err != nil {
+		return models.DockerContainerStats{}, err
+	}
+
+	type statsResponse struct {
+		response *http.Response
+		body     []byte
+		err      error
+	}
+	done := make(chan statsResponse, 1)
+	go func() {
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s/stats", url.PathEscape(containerId)),
+			Method:        "GET",
+			QueryParams:   map[string]string{"stream": "false"},
+		})
+		done <- statsResponse{response, responseBody, err}
+	}()
+
+	var result statsResponse
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		return models.DockerContainerStats{}, ctx.Err()
+	}
+
+	response, responseBody, err := result.response, result.body, result.err
+	if err != nil {
+		return models.DockerContainerStats{}, err
+	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return models.DockerContainerStats{}, fmt.Errorf("HTTP %d %s: %s", response.StatusCode, http.StatusText(response.StatusCode), extractProxyErrorMessage(responseBody))
+	}
+
+	var rawStats container.StatsResponse
+	if err := json.Unmarshal(responseBody, &rawStats); err != nil {
+		return models.DockerContainerStats{}, fmt.Errorf("failed to decode stats: %w", err)
+	}
+
+	return models.ConvertToDockerContainerStats(rawStats), nil
+}
+
+// formatContainerTop renders the processes of a container as a text table, like docker top
+func formatContainerTop(top container.TopResponse) string {
+	if len(top.Processes) == 0 {
+		return "(no processes)"
+	}
+
+	var sb strings.Builder
+	writer := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(top.Titles, "\t"))
+	for _, process := range top.Processes {
+		fmt.Fprintln(writer, strings.Join(process, "\t"))
+	}
+	writer.Flush()
+
+	return sb.String()
+}
+
+// parseDockerTimestamp converts a timestamp parameter to the Unix timestamp expected by the Docker API.
+// The value can be a Unix timestamp, a RFC 3339 date or a duration relative to now (e.g. 10m, 2h).
+func parseDockerTimestamp(value string, now time.Time) (string, error) {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return strconv.FormatInt(t.Unix(), 10), nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return strconv.FormatInt(now.Add(-d).Unix(), 10), nil
+	}
+
+	return "", fmt.Errorf("%s is not a Unix timestamp, a RFC 3339 date or a duration", value)
+}
+
+// formatContainerLogs decodes the logs returned by the Docker API into stdout and stderr sections.
+// The logs of the containers started without a TTY are multiplexed with an 8-byte header per frame,
+// while the logs of the containers started with a TTY are a raw stream. ANSI escape sequences are
+// removed from the output.
+func formatContainerLogs(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "(no logs)", nil
+	}
+
+	if !isMultiplexedStream(data) {
+		return "=== output (tty) ===\n" + stripANSI(string(data)), nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, bytes.NewReader(data)); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if stdout.Len() > 0 {
+		sb.WriteString("=== stdout ===\n")
+		sb.WriteString(stripANSI(stdout.String()))
+	}
+	if stderr.Len() > 0 {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("=== stderr ===\n")
+		sb.WriteString(stripANSI(stderr.String()))
+	}
+	if sb.Len() == 0 {
+		return "(no logs)", nil
+	}
+
+	return sb.String(), nil
+}
+
+// isMultiplexedStream checks if the data starts with a stdcopy frame header:
+// one byte for the stream (stdin, stdout, stderr or systemerr), three zero bytes and the frame size
+func isMultiplexedStream(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+
+	stream := stdcopy.StdType(data[0])
+	if stream != stdcopy.Stdin && stream != stdcopy.Stdout && stream != stdcopy.Stderr && stream != stdcopy.Systemerr {
+		return false
+	}
+
+	return data[1] == 0 && data[2] == 0 && data[3] == 0
+}
+
+// stripANSI removes the ANSI escape sequences from a string
+func stripANSI(value string) string {
+	return ansiEscapeSequencePattern.ReplaceAllString(value, "")
+}
@@ -0,0 +1,636 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// logFrame is a frame of a multiplexed Docker log stream
+type logFrame struct {
+	stream stdcopy.StdType
+	data   string
+}
+
+// multiplexLogs builds a multiplexed Docker log stream from stdout and stderr frames
+func multiplexLogs(t *testing.T, frames ...logFrame) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, frame := range frames {
+		_, err := stdcopy.NewStdWriter(&buf, frame.stream).Write([]byte(frame.data))
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func TestFormatContainerLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "empty logs",
+			data:     []byte{},
+			expected: "(no logs)",
+		},
+		{
+			name: "multiplexed stdout and stderr",
+			data: multiplexLogs(t,
+				logFrame{stdcopy.Stdout, "starting\n"},
+				logFrame{stdcopy.Stderr, "warning: low memory\n"},
+				logFrame{stdcopy.Stdout, "ready\n"},
+			),
+			expected: "=== stdout ===\nstarting\nready\n=== stderr ===\nwarning: low memory\n",
+		},
+		{
+			name: "multiplexed stdout only with ANSI codes",
+			data: multiplexLogs(t,
+				logFrame{stdcopy.Stdout, "\x1b[32mINFO\x1b[0m server started\n"},
+			),
+			expected: "=== stdout ===\nINFO server started\n",
+		},
+		{
+			name:     "raw TTY stream",
+			data:     []byte("\x1b[1;31merror\x1b[0m: failed\r\n"),
+			expected: "=== output (tty) ===\nerror: failed\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, err := formatContainerLogs(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, logs)
+		})
+	}
+}
+
+func TestParseDockerTimestamp(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{name: "unix timestamp", value: "1735732800", expected: "1735732800"},
+		{name: "RFC 3339 date", value: "2025-01-01T11:00:00Z", expected: "1735729200"},
+		{name: "relative duration", value: "30m", expected: "1735731000"},
+		{name: "invalid value", value: "yesterday", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timestamp, err := parseDockerTimestamp(tt.value, now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, timestamp)
+		})
+	}
+}
+
+func TestHandleGetContainerLogs(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          map[string]any
+		expectedQuery  map[string]string
+		response       *http.Response
+		expectedText   string
+		expectedError  bool
+		skipClientCall bool
+	}{
+		{
+			name: "default tail",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+			},
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "timestamps": "false", "tail": "100"},
+			response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(multiplexLogs(t, logFrame{stdcopy.Stdout, "hello\n"}))),
+			},
+			expectedText: "=== stdout ===\nhello\n",
+		},
+		{
+			name: "all lines with timestamps",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"tail":          float64(-1),
+				"timestamps":    true,
+				"since":         "1735732800",
+			},
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "timestamps": "true", "tail": "all", "since": "1735732800"},
+			response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte("tty output\n"))),
+			},
+			expectedText: "=== output (tty) ===\ntty output\n",
+		},
+		{
+			name: "container not found",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "missing",
+			},
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "timestamps": "false", "tail": "100"},
+			response:      createMockHttpResponse(http.StatusNotFound, `{"message":"No such container: missing"}`),
+			expectedText:  "HTTP 404 Not Found\nError: No such container: missing",
+			expectedError: true,
+		},
+		{
+			name: "invalid since",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"since":         "yesterday",
+			},
+			expectedText:   "invalid since parameter",
+			expectedError:  true,
+			skipClientCall: true,
+		},
+		{
+			name: "missing containerId",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			expectedText:   "containerId is required",
+			expectedError:  true,
+			skipClientCall: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if !tt.skipClientCall {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "GET" &&
+						opts.Path == "/containers/"+tt.input["containerId"].(string)+"/logs" &&
+						assert.ObjectsAreEqual(tt.expectedQuery, opts.QueryParams)
+				})).Return(tt.response, nil)
+			}
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleGetContainerLogs()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			texts := resultTexts(t, result)
+			assert.Contains(t, texts[0], tt.expectedText)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleListContainers(t *testing.T) {
+	containers := `[{"Id":"4f66ad9a0b2e1c3d5e7f","Names":["/web"],"Image":"nginx","State":"running","Status":"Up 2 hours","Created":1735689600,"Ports":[{"IP":"0.0.0.0","PrivatePort":80,"PublicPort":8080,"Type":"tcp"}],"Labels":{"app":"shop"}}]`
+
+	tests := []struct {
+		name           string
+		input          map[string]any
+		expectedQuery  map[string]string
+		response       *http.Response
+		expectedText   string
+		expectedError  bool
+		skipClientCall bool
+	}{
+		{
+			name: "running containers",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			expectedQuery: map[string]string{"all": "false"},
+			response:      createMockHttpResponse(http.StatusOK, containers),
+			expectedText:  `[{"id":"4f66ad9a0b2e","name":"web","image":"nginx","state":"running","status":"Up 2 hours","created_at":"2025-01-01T00:00:00Z","ports":["0.0.0.0:8080->80/tcp"],"labels":{"app":"shop"}}]`,
+		},
+		{
+			name: "filters and fields",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"status":        "exited",
+				"name":          "web",
+				"labels":        []any{"app=shop", "tier"},
+				"outputFormat":  "csv",
+				"fields":        []any{"name", "state"},
+			},
+			expectedQuery: map[string]string{"all": "true", "filters": `{"label":["app=shop","tier"],"name":["web"],"status":["exited"]}`},
+			response:      createMockHttpResponse(http.StatusOK, containers),
+			expectedText:  "name,state\nweb,running\n",
+		},
+		{
+			name: "invalid status",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"status":        "stopped",
+			},
+			expectedText:   "invalid status: stopped",
+			expectedError:  true,
+			skipClientCall: true,
+		},
+		{
+			name: "Docker API error",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			expectedQuery: map[string]string{"all": "false"},
+			response:      createMockHttpResponse(http.StatusInternalServerError, `{"message":"daemon unavailable"}`),
+			expectedText:  "HTTP 500 Internal Server Error\nError: daemon unavailable",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if !tt.skipClientCall {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "GET" &&
+						opts.Path == "/containers/json" &&
+						assert.ObjectsAreEqual(tt.expectedQuery, opts.QueryParams)
+				})).Return(tt.response, nil)
+			}
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleListContainers()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			switch {
+			case tt.expectedError:
+				assert.Contains(t, resultTexts(t, result)[0], tt.expectedText)
+			case tt.input["outputFormat"] == nil:
+				assert.JSONEq(t, tt.expectedText, resultTexts(t, result)[0])
+			default:
+				assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleInspectContainer(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" && opts.Path == "/containers/web/json"
+	})).Return(createMockHttpResponse(http.StatusOK, `{"Id":"4f66ad9a0b2e","Name":"/web","Image":"sha256:9b1c","State":{"Status":"running"},"GraphDriver":{"Name":"overlay2"},"ExecIDs":null}`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleInspectContainer()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"containerId":   "web",
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.JSONEq(t, `{"Id":"4f66ad9a0b2e","Name":"/web","Image":"sha256:9b1c","State":{"Status":"running"}}`, resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleContainerTop(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" &&
+			opts.Path == "/containers/web/top" &&
+			assert.ObjectsAreEqual(map[string]string{"ps_args": "aux"}, opts.QueryParams)
+	})).Return(createMockHttpResponse(http.StatusOK, `{"Titles":["PID","USER","COMMAND"],"Processes":[["1","root","nginx: master process"],["29","nginx","nginx: worker process"]]}`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleContainerTop()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"containerId":   "web",
+		"psArgs":        "aux",
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "PID   USER    COMMAND\n1     root    nginx: master process\n29    nginx   nginx: worker process\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleContainerActions(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       func(s *PortainerMCPServer) server.ToolHandlerFunc
+		input         map[string]any
+		expectedPath  string
+		expectedQuery map[string]string
+		response      *http.Response
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name:         "start container",
+			handler:      func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleStartContainer() },
+			input:        map[string]any{"environmentId": float64(1), "containerId": "web"},
+			expectedPath: "/containers/web/start",
+			response:     createMockHttpResponse(http.StatusNoContent, ""),
+			expectedText: "Container web started successfully",
+		},
+		{
+			name:         "start already started container",
+			handler:      func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleStartContainer() },
+			input:        map[string]any{"environmentId": float64(1), "containerId": "web"},
+			expectedPath: "/containers/web/start",
+			response:     createMockHttpResponse(http.StatusNotModified, ""),
+			expectedText: "Container web is already started",
+		},
+		{
+			name:          "stop container with timeout",
+			handler:       func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleStopContainer() },
+			input:         map[string]any{"environmentId": float64(1), "containerId": "web", "timeout": float64(30)},
+			expectedPath:  "/containers/web/stop",
+			expectedQuery: map[string]string{"t": "30"},
+			response:      createMockHttpResponse(http.StatusNoContent, ""),
+			expectedText:  "Container web stopped successfully",
+		},
+		{
+			name:          "restart missing container",
+			handler:       func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleRestartContainer() },
+			input:         map[string]any{"environmentId": float64(1), "containerId": "missing"},
+			expectedPath:  "/containers/missing/restart",
+			response:      createMockHttpResponse(http.StatusNotFound, `{"message":"No such container: missing"}`),
+			expectedText:  "HTTP 404 Not Found\nError: No such container: missing",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+				return opts.Method == "POST" &&
+					opts.Path == tt.expectedPath &&
+					assert.ObjectsAreEqual(tt.expectedQuery, opts.QueryParams)
+			})).Return(tt.response, nil)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			result, err := tt.handler(server)(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Contains(t, resultTexts(t, result)[0], tt.expectedText)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleRemoveContainer(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "DELETE" &&
+			opts.Path == "/containers/web" &&
+			assert.ObjectsAreEqual(map[string]string{"force": "true", "v": "false"}, opts.QueryParams)
+	})).Return(createMockHttpResponse(http.StatusNoContent, ""), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleRemoveContainer()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"containerId":   "web",
+		"force":         true,
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Container web removed successfully", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleGetContainerStats(t *testing.T) {
+	containers := `[{"Id":"aaa","Names":["/web"]},{"Id":"bbb","Names":["/db"]},{"Id":"ccc","Names":["/worker"]}]`
+	statsResponses := map[string]struct {
+		statusCode int
+		body       string
+	}{
+		"/containers/aaa/stats": {http.StatusOK, `{"id":"aaa","cpu_stats":{"cpu_usage":{"total_usage":300},"system_cpu_usage":1000,"online_cpus":1},"precpu_stats":{"cpu_usage":{"total_usage":100},"system_cpu_usage":0},"memory_stats":{"usage":1048576,"limit":4194304},"pids_stats":{"current":3}}`},
+		"/containers/bbb/stats": {http.StatusOK, `{"id":"bbb","cpu_stats":{"cpu_usage":{"total_usage":150},"system_cpu_usage":1000,"online_cpus":1},"precpu_stats":{"cpu_usage":{"total_usage":100},"system_cpu_usage":0},"memory_stats":{"usage":2097152,"limit":4194304},"pids_stats":{"current":12}}`},
+		"/containers/ccc/stats": {http.StatusNotFound, `{"message":"No such container: ccc"}`},
+	}
+
+	tests := []struct {
+		name          string
+		input         map[string]any
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "sorted by memory",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"fields":        []any{"name", "memory_usage", "cpu_percent"},
+			},
+			expectedText: "| name | memory_usage | cpu_percent |\n" +
+				"| --- | --- | --- |\n" +
+				"| db | 2MiB | 5 |\n" +
+				"| web | 1MiB | 20 |\n" +
+				"\n\nThe stats of some containers could not be collected:\n" +
+				"- worker: HTTP 404 Not Found: No such container: ccc",
+		},
+		{
+			name: "top container by CPU",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"sortBy":        "cpu",
+				"limit":         float64(1),
+				"outputFormat":  "csv",
+				"fields":        []any{"name", "cpu_percent", "pids"},
+			},
+			expectedText: "name,cpu_percent,pids\n" +
+				"web,20,3\n" +
+				"\n\nThe stats of some containers could not be collected:\n" +
+				"- worker: HTTP 404 Not Found: No such container: ccc",
+		},
+		{
+			name: "invalid sortBy",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"sortBy":        "disk",
+			},
+			expectedText:  "invalid sortBy: disk, valid values are: blockio, cpu, memory, network, pids",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if !tt.expectedError {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/containers/json"
+				})).Return(createMockHttpResponse(http.StatusOK, containers), nil)
+				for path, response := range statsResponses {
+					mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+						return opts.Path == path && opts.QueryParams["stream"] == "false"
+					})).Return(createMockHttpResponse(response.statusCode, response.body), nil)
+				}
+			}
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleGetContainerStats()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCollectContainerStatsCancellation(t *testing.T) {
+	containers := make([]container.Summary, 3*containerStatsConcurrency)
+	for i := range containers {
+		containers[i] = container.Summary{ID: fmt.Sprintf("c%d", i), Names: []string{fmt.Sprintf("/c%d", i)}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+
+	var calls atomic.Int32
+	mockClient := new(MockPortainerClient)
+	// The first stats request cancels the collection, the requests never complete before the end of the test
+	mockClient.On("ProxyDockerRequest", mock.AnythingOfType("models.DockerProxyRequestOptions")).Run(func(args mock.Arguments) {
+		calls.Add(1)
+		cancel()
+		<-release
+	}).Return(nil, errors.New("released"))
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	done := make(chan struct{})
+	var stats []models.DockerContainerStats
+	var failures []string
+	go func() {
+		stats, failures = server.collectContainerStats(ctx, 1, containers, &progressReporter{})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the collection did not stop when the context was cancelled")
+	}
+
+	assert.Empty(t, stats)
+	assert.Empty(t, failures)
+	assert.LessOrEqual(t, int(calls.Load()), containerStatsConcurrency)
+}
+
+func TestHandlePruneContainers(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        map[string]any
+		setupMock    func(mockClient *MockPortainerClient)
+		expectedText string
+	}{
+		{
+			name: "dry run lists the stopped containers",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "GET" && opts.Path == "/system/df"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"Containers":[
+					{"Id":"c1","Names":["/web"],"State":"running","SizeRw":1000},
+					{"Id":"c2","Names":["/migrate"],"State":"exited","SizeRw":2500000},
+					{"Id":"c3","Names":["/init"],"State":"created","SizeRw":0}
+				]}`), nil)
+			},
+			expectedText: "Dry run: 2 containers would be removed, reclaiming 2.5MB. Nothing was removed, " +
+				"call pruneContainers again with confirm set to true to remove them.\n\n" +
+				"- migrate (2.5MB)\n" +
+				"- init\n",
+		},
+		{
+			name: "confirmed prune",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"confirm":       true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "POST" && opts.Path == "/containers/prune"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"ContainersDeleted":["4f66ad9a0b2e1c3d5e7f"],"SpaceReclaimed":2500000}`), nil)
+			},
+			expectedText: "Removed 1 container, reclaimed 2.5MB.\n\n- 4f66ad9a0b2e\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandlePruneContainers()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.False(t, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,577 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// maxOwnerChainDepth is the maximum number of owners followed by describeKubernetesResource
+	maxOwnerChainDepth = 5
+	// maxDescribedPods is the maximum number of related pods listed by describeKubernetesResource
+	maxDescribedPods = 20
+	// maxDescribedEvents is the maximum number of events listed by describeKubernetesResource, the most recent are kept
+	maxDescribedEvents = 20
+	// lastAppliedConfigurationAnnotation is the annotation where kubectl apply stores the applied object
+	lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// kubernetesCondition is a condition of the status of a Kubernetes object
+type kubernetesCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func (s *PortainerMCPServer) HandleDescribeKubernetesResource() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		kind, err := parser.GetString("kind", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid kind parameter", err), nil
+		}
+
+		name, err := parser.GetString("name", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+		}
+
+		namespace, err := parser.GetString("namespace", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+		}
+
+		apiVersion, err := parser.GetString("apiVersion", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid apiVersion parameter", err), nil
+		}
+
+		discovery := s.newKubernetesDiscovery(environmentId)
+		groupVersion, resource, err := discovery.lookup(kind, apiVersion)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve the kind", err), nil
+		}
+
+		if !resource.Namespaced {
+			namespace = ""
+		} else if namespace == "" {
+			namespace = defaultKubernetesNamespace
+		}
+
+		response, responseBody, err := s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          kubernetesResourcePath(groupVersion, resource.Name, namespace, name),
+			Method:        "GET",
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(responseBody); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode Kubernetes API response", err), nil
+		}
+
+		var sb strings.Builder
+		owners := s.describeOwnerChain(discovery, object)
+
+		if object.GetKind() == "Pod" && groupVersion.Group == "" {
+			var pod kubernetesPod
+			if err := json.Unmarshal(responseBody, &pod); err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to decode Kubernetes API response", err), nil
+			}
+			writeObjectHeader(&sb, object, owners, [][2]string{
+				{"Status", podStatus(pod)},
+				{"Node", cmp.Or(pod.Spec.NodeName, "<none>")},
+				{"IP", cmp.Or(pod.Status.PodIP, "<none>")},
+			})
+			writePodContainers(&sb, pod)
+		} else {
+			writeObjectHeader(&sb, object, owners, nil)
+			writeObjectDetails(&sb, object)
+		}
+
+		writeConditions(&sb, object)
+
+		if object.GetKind() == "Deployment" && groupVersion.Group == "apps" {
+			s.writeOwnedReplicaSets(&sb, environmentId, object)
+		}
+		s.writeSelectedPods(&sb, environmentId, object)
+		s.writeObjectEvents(&sb, environmentId, object)
+
+		result, err := s.responses.result(ctx, []byte(sb.String()))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to buffer Kubernetes resource description", err), nil
+		}
+
+		return result, nil
+	}
+}
+
+// describeOwnerChain follows the controllers of an object up to the top-level owner, e.g. Pod to ReplicaSet to
+// Deployment. The chain stops at the first owner that cannot be retrieved, with the error.
+func (s *PortainerMCPServer) describeOwnerChain(discovery *kubernetesDiscovery, object *unstructured.Unstructured) []string {
+	var chain []string
+
+	owners := object.GetOwnerReferences()
+	namespace := object.GetNamespace()
+	for range maxOwnerChainDepth {
+		owner, found := controllerOf(owners)
+		if !found {
+			break
+		}
+
+		ownerName := fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+		gvk := schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind)
+		resource, err := discovery.resource(gvk)
+		if err != nil {
+			chain = append(chain, fmt.Sprintf("%s (%s)", ownerName, err))
+			break
+		}
+
+		ownerNamespace := ""
+		if resource.Namespaced {
+			ownerNamespace = namespace
+		}
+
+		response, responseBody, err := s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+			EnvironmentID: discovery.environmentId,
+			Path:          kubernetesResourcePath(gvk.GroupVersion(), resource.Name, ownerNamespace, owner.Name),
+			Method:        "GET",
+		})
+		if err == nil && !isSuccessStatusCode(response.StatusCode) {
+			err = kubernetesResponseError(response, responseBody)
+		}
+		if err != nil {
+			chain = append(chain, fmt.Sprintf("%s (%s)", ownerName, err))
+			break
+		}
+
+		var next struct {
+			Metadata metav1.ObjectMeta `json:"metadata"`
+		}
+		if err := json.Unmarshal(responseBody, &next); err != nil {
+			chain = append(chain, fmt.Sprintf("%s (%s)", ownerName, err))
+			break
+		}
+
+		chain = append(chain, ownerName)
+		owners = next.Metadata.OwnerReferences
+	}
+
+	return chain
+}
+
+// writeSelectedPods lists the pods selected by the selector of an object, e.g. the pods of a workload or of a Service
+func (s *PortainerMCPServer) writeSelectedPods(sb *strings.Builder, environmentId int, object *unstructured.Unstructured) {
+	selector, found := objectPodSelector(object)
+	if !found || object.GetNamespace() == "" {
+		return
+	}
+
+	var pods kubernetesPodList
+	if err := s.getKubernetesList(environmentId, kubernetesResourcePath(schema.GroupVersion{Version: "v1"}, "pods", object.GetNamespace(), ""), selector, &pods); err != nil {
+		fmt.Fprintf(sb, "\nPods: could not be retrieved: %s\n", err)
+		return
+	}
+
+	sb.WriteString("\nPods:\n")
+	if len(pods.Items) == 0 {
+		fmt.Fprintf(sb, "  (no pods matching %s)\n", selector)
+		return
+	}
+
+	slices.SortFunc(pods.Items, func(a, b kubernetesPod) int {
+		return strings.Compare(a.Metadata.Name, b.Metadata.Name)
+	})
+
+	writer := tabwriter.NewWriter(sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "  NAME\tREADY\tSTATUS\tRESTARTS\tNODE")
+	for _, pod := range pods.Items[:min(len(pods.Items), maxDescribedPods)] {
+		ready := 0
+		var restarts int32
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Ready {
+				ready++
+			}
+			restarts += status.RestartCount
+		}
+		fmt.Fprintf(writer, "  %s\t%d/%d\t%s\t%d\t%s\n", pod.Metadata.Name, ready, len(pod.Spec.Containers), podStatus(pod),
+			restarts, cmp.Or(pod.Spec.NodeName, "<none>"))
+	}
+	writer.Flush()
+
+	if len(pods.Items) > maxDescribedPods {
+		fmt.Fprintf(sb, "  ... %d more pods\n", len(pods.Items)-maxDescribedPods)
+	}
+}
+
+// writeOwnedReplicaSets lists the ReplicaSets of a Deployment, the most recent revision first
+func (s *PortainerMCPServer) writeOwnedReplicaSets(sb *strings.Builder, environmentId int, deployment *unstructured.Unstructured) {
+	selector, found := objectPodSelector(deployment)
+	if !found {
+		return
+	}
+
+	owned, err := s.listDeploymentReplicaSets(environmentId, deployment.GetNamespace(), selector, deployment.GetUID())
+	if err != nil {
+		fmt.Fprintf(sb, "\nReplicaSets: could not be retrieved: %s\n", err)
+		return
+	}
+	if len(owned) == 0 {
+		return
+	}
+
+	sb.WriteString("\nReplicaSets:\n")
+	writer := tabwriter.NewWriter(sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "  NAME\tREVISION\tDESIRED\tCURRENT\tREADY")
+	for _, replicaSet := range owned {
+		desired := int32(1)
+		if replicaSet.Spec.Replicas != nil {
+			desired = *replicaSet.Spec.Replicas
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%d\t%d\t%d\n", replicaSet.Metadata.Name, cmp.Or(replicaSet.Metadata.Annotations[deploymentRevisionAnnotation], "<none>"),
+			desired, replicaSet.Status.Replicas, replicaSet.Status.ReadyReplicas)
+	}
+	writer.Flush()
+}
+
+// writeObjectEvents lists the most recent events of an object, oldest first like kubectl describe
+func (s *PortainerMCPServer) writeObjectEvents(sb *strings.Builder, environmentId int, object *unstructured.Unstructured) {
+	var events kubernetesEventList
+	response, responseBody, err := s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          kubernetesResourcePath(schema.GroupVersion{Version: "v1"}, "events", object.GetNamespace(), ""),
+		Method:        "GET",
+		QueryParams:   map[string]string{"fieldSelector": "involvedObject.uid=" + string(object.GetUID())},
+	})
+	if err == nil && !isSuccessStatusCode(response.StatusCode) {
+		err = kubernetesResponseError(response, responseBody)
+	}
+	if err == nil {
+		err = json.Unmarshal(responseBody, &events)
+	}
+	if err != nil {
+		fmt.Fprintf(sb, "\nEvents: could not be retrieved: %s\n", err)
+		return
+	}
+
+	if len(events.Items) == 0 {
+		sb.WriteString("\nEvents: <none>\n")
+		return
+	}
+
+	slices.SortStableFunc(events.Items, func(a, b kubernetesEvent) int {
+		return a.lastSeen().Compare(b.lastSeen())
+	})
+	omitted := max(len(events.Items)-maxDescribedEvents, 0)
+
+	sb.WriteString("\nEvents:\n")
+	if omitted > 0 {
+		fmt.Fprintf(sb, "  (%d older events omitted)\n", omitted)
+	}
+	writer := tabwriter.NewWriter(sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "  LAST SEEN\tTYPE\tREASON\tFROM\tMESSAGE")
+	for _, event := range events.Items[omitted:] {
+		message := strings.TrimSpace(event.Message)
+		if count := event.occurrences(); count > 1 {
+			message += fmt.Sprintf(" (x%d)", count)
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\t%s\n", formatKubernetesTime(event.lastSeen()), event.Type, event.Reason,
+			cmp.Or(event.source(), "<unknown>"), message)
+	}
+	writer.Flush()
+}
+
+// getKubernetesList lists the objects of a collection matching a label selector
+func (s *PortainerMCPServer) getKubernetesList(environmentId int, path, labelSelector string, target any) error {
+	response, responseBody, err := s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          path,
+		Method:        "GET",
+		QueryParams:   map[string]string{"labelSelector": labelSelector},
+	})
+	if err != nil {
+		return err
+	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return kubernetesResponseError(response, responseBody)
+	}
+
+	return json.Unmarshal(responseBody, target)
+}
+
+// writeObjectHeader renders the metadata of an object and its owner chain, followed by the fields specific to its kind
+func writeObjectHeader(sb *strings.Builder, object *unstructured.Unstructured, owners []string, fields [][2]string) {
+	writer := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Name:\t%s\n", object.GetName())
+	if object.GetNamespace() != "" {
+		fmt.Fprintf(writer, "Namespace:\t%s\n", object.GetNamespace())
+	}
+	fmt.Fprintf(writer, "Kind:\t%s (%s)\n", object.GetKind(), object.GetAPIVersion())
+	fmt.Fprintf(writer, "Created:\t%s\n", formatKubernetesTime(object.GetCreationTimestamp().Time))
+	if deletion := object.GetDeletionTimestamp(); deletion != nil {
+		fmt.Fprintf(writer, "Deleting:\t%s\n", formatKubernetesTime(deletion.Time))
+	}
+	fmt.Fprintf(writer, "Labels:\t%s\n", formatKeyValues(object.GetLabels()))
+
+	annotations := object.GetAnnotations()
+	delete(annotations, lastAppliedConfigurationAnnotation)
+	fmt.Fprintf(writer, "Annotations:\t%s\n", formatKeyValues(annotations))
+
+	if len(owners) > 0 {
+		fmt.Fprintf(writer, "Controlled By:\t%s\n", strings.Join(owners, " -> "))
+	}
+	for _, field := range fields {
+		fmt.Fprintf(writer, "%s:\t%s\n", field[0], field[1])
+	}
+	writer.Flush()
+}
+
+// writePodContainers renders the containers of a pod and their status
+func writePodContainers(sb *strings.Builder, pod kubernetesPod) {
+	statuses := make(map[string]kubernetesContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses[status.Name] = status
+	}
+
+	sb.WriteString("\nContainers:\n")
+	for _, container := range pod.Spec.Containers {
+		fmt.Fprintf(sb, "  %s:\n", container.Name)
+		writer := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "    Image:\t%s\n", container.Image)
+
+		status, found := statuses[container.Name]
+		if !found {
+			fmt.Fprintf(writer, "    State:\t<unknown>\n")
+			writer.Flush()
+			continue
+		}
+
+		fmt.Fprintf(writer, "    State:\t%s\n", formatContainerState(status.State))
+		if lastState := formatContainerState(status.LastTerminationState); lastState != "" {
+			fmt.Fprintf(writer, "    Last State:\t%s\n", lastState)
+		}
+		fmt.Fprintf(writer, "    Ready:\t%t\n", status.Ready)
+		fmt.Fprintf(writer, "    Restarts:\t%d\n", status.RestartCount)
+		writer.Flush()
+	}
+}
+
+// writeObjectDetails renders the content of an object as YAML, without its metadata and its conditions which are
+// rendered separately. The values of the Secrets are replaced by their size.
+func writeObjectDetails(sb *strings.Builder, object *unstructured.Unstructured) {
+	content := object.DeepCopy().Object
+	delete(content, "apiVersion")
+	delete(content, "kind")
+	delete(content, "metadata")
+	unstructured.RemoveNestedField(content, "status", "conditions")
+	if status, ok := content["status"].(map[string]any); ok && len(status) == 0 {
+		delete(content, "status")
+	}
+
+	if values, ok := content["data"].(map[string]any); ok && object.GetKind() == "Secret" && object.GetAPIVersion() == "v1" {
+		for key, value := range values {
+			encoded, _ := value.(string)
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				values[key] = "<hidden>"
+				continue
+			}
+			values[key] = fmt.Sprintf("<%d bytes>", len(decoded))
+		}
+	}
+
+	if len(content) == 0 {
+		return
+	}
+
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(content); err != nil {
+		fmt.Fprintf(sb, "\nfailed to render the object: %s\n", err)
+		return
+	}
+	encoder.Close()
+
+	sb.WriteString("\n")
+	sb.WriteString(buf.String())
+}
+
+// writeConditions renders the conditions of the status of an object
+func writeConditions(sb *strings.Builder, object *unstructured.Unstructured) {
+	rawConditions, found, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	if !found || len(rawConditions) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(rawConditions)
+	if err != nil {
+		return
+	}
+	var conditions []kubernetesCondition
+	if err := json.Unmarshal(data, &conditions); err != nil {
+		return
+	}
+
+	sb.WriteString("\nConditions:\n")
+	writer := tabwriter.NewWriter(sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+	for _, condition := range conditions {
+		fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, cmp.Or(condition.Reason, "-"), strings.TrimSpace(condition.Message))
+	}
+	writer.Flush()
+}
+
+// controllerOf returns the controller among the owners of an object, or the first owner when none of them is the
+// controller
+func controllerOf(owners []metav1.OwnerReference) (metav1.OwnerReference, bool) {
+	if len(owners) == 0 {
+		return metav1.OwnerReference{}, false
+	}
+
+	for _, owner := range owners {
+		if owner.Controller != nil && *owner.Controller {
+			return owner, true
+		}
+	}
+	return owners[0], true
+}
+
+// objectPodSelector returns the label selector of the pods of an object. The selector is either a label selector,
+// for the workloads, or a map of labels, for the Services and the ReplicationControllers.
+func objectPodSelector(object *unstructured.Unstructured) (string, bool) {
+	rawSelector, found, err := unstructured.NestedMap(object.Object, "spec", "selector")
+	if !found || err != nil || len(rawSelector) == 0 {
+		return "", false
+	}
+
+	data, err := json.Marshal(rawSelector)
+	if err != nil {
+		return "", false
+	}
+
+	_, hasMatchLabels := rawSelector["matchLabels"]
+	_, hasMatchExpressions := rawSelector["matchExpressions"]
+	if hasMatchLabels || hasMatchExpressions {
+		var labelSelector metav1.LabelSelector
+		if err := json.Unmarshal(data, &labelSelector); err != nil {
+			return "", false
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		if err != nil || selector.Empty() {
+			return "", false
+		}
+		return selector.String(), true
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return "", false
+	}
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	return selector, selector != "" && selector != "<none>"
+}
+
+// podStatus returns the status of a pod as displayed by kubectl get pods, e.g. Running or CrashLoopBackOff
+func podStatus(pod kubernetesPod) string {
+	if pod.Metadata.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+
+	status := cmp.Or(pod.Status.Reason, pod.Status.Phase, "Unknown")
+	for _, container := range pod.Status.ContainerStatuses {
+		switch {
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+			return container.State.Waiting.Reason
+		case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+			status = container.State.Terminated.Reason
+		}
+	}
+	return status
+}
+
+// formatContainerState renders the state of a container, an empty string is returned when no state is set
+func formatContainerState(state kubernetesContainerState) string {
+	switch {
+	case state.Waiting != nil:
+		return joinNonEmpty(": ", withReason("Waiting", state.Waiting.Reason), state.Waiting.Message)
+	case state.Running != nil:
+		return "Running since " + formatKubernetesTime(state.Running.StartedAt.Time)
+	case state.Terminated != nil:
+		terminated := fmt.Sprintf("%s, exit code %d", withReason("Terminated", state.Terminated.Reason), state.Terminated.ExitCode)
+		if !state.Terminated.FinishedAt.IsZero() {
+			terminated += " at " + formatKubernetesTime(state.Terminated.FinishedAt.Time)
+		}
+		return joinNonEmpty(": ", terminated, state.Terminated.Message)
+	}
+	return ""
+}
+
+// formatKeyValues renders labels or annotations sorted by key, <none> when there are none
+func formatKeyValues(values map[string]string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+
+	var pairs []string
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		pairs = append(pairs, key+"="+values[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// formatKubernetesTime renders a timestamp of the Kubernetes API, <unknown> when it is not set
+func formatKubernetesTime(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// withReason appends the reason of a state in parentheses, when there is one
+func withReason(state, reason string) string {
+	if reason == "" {
+		return state
+	}
+	return fmt.Sprintf("%s (%s)", state, reason)
+}
+
+// joinNonEmpty joins the values that are not empty, after trimming their spaces
+func joinNonEmpty(separator string, values ...string) string {
+	var nonEmpty []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return strings.Join(nonEmpty, separator)
+}
@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleDescribeKubernetesResource(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "crashing pod with its owner chain",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"kind":          "po",
+				"name":          "web-7d4b9c-x1",
+				"namespace":     "shop",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1")).
+					Return(createMockHttpResponse(http.StatusOK, appsV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/pods/web-7d4b9c-x1")).
+					Return(createMockHttpResponse(http.StatusOK, `{
+						"apiVersion":"v1","kind":"Pod",
+						"metadata":{"name":"web-7d4b9c-x1","namespace":"shop","uid":"pod-uid","creationTimestamp":"2025-01-01T12:00:00Z",
+							"labels":{"app":"web","pod-template-hash":"7d4b9c"},
+							"ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"web-7d4b9c","uid":"rs-uid","controller":true}]},
+						"spec":{"nodeName":"worker-1","containers":[{"name":"app","image":"shop/web:1.2"}]},
+						"status":{"phase":"Running","podIP":"10.0.0.12",
+							"conditions":[{"type":"Ready","status":"False","reason":"ContainersNotReady","message":"containers with unready status: [app]"}],
+							"containerStatuses":[{"name":"app","ready":false,"restartCount":5,
+								"state":{"waiting":{"reason":"CrashLoopBackOff","message":"back-off 2m40s restarting failed container"}},
+								"lastState":{"terminated":{"exitCode":1,"reason":"Error","finishedAt":"2025-01-01T12:10:00Z"}}}]}
+					}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/replicasets/web-7d4b9c")).
+					Return(createMockHttpResponse(http.StatusOK, `{"metadata":{"name":"web-7d4b9c",
+						"ownerReferences":[{"apiVersion":"apps/v1","kind":"Deployment","name":"web","uid":"deploy-uid","controller":true}]}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+					Return(createMockHttpResponse(http.StatusOK, `{"metadata":{"name":"web"}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/namespaces/shop/events" && opts.QueryParams["fieldSelector"] == "involvedObject.uid=pod-uid"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"items":[
+					{"type":"Warning","reason":"BackOff","message":"Back-off restarting failed container app","count":5,
+						"lastTimestamp":"2025-01-01T12:11:00Z","source":{"component":"kubelet"}},
+					{"type":"Normal","reason":"Scheduled","message":"Successfully assigned shop/web-7d4b9c-x1 to worker-1",
+						"eventTime":"2025-01-01T12:00:00.000000Z","reportingComponent":"default-scheduler"}
+				]}`), nil)
+			},
+			expectedText: "Name:           web-7d4b9c-x1\n" +
+				"Namespace:      shop\n" +
+				"Kind:           Pod (v1)\n" +
+				"Created:        2025-01-01T12:00:00Z\n" +
+				"Labels:         app=web, pod-template-hash=7d4b9c\n" +
+				"Annotations:    <none>\n" +
+				"Controlled By:  ReplicaSet/web-7d4b9c -> Deployment/web\n" +
+				"Status:         CrashLoopBackOff\n" +
+				"Node:           worker-1\n" +
+				"IP:             10.0.0.12\n" +
+				"\n" +
+				"Containers:\n" +
+				"  app:\n" +
+				"    Image:       shop/web:1.2\n" +
+				"    State:       Waiting (CrashLoopBackOff): back-off 2m40s restarting failed container\n" +
+				"    Last State:  Terminated (Error), exit code 1 at 2025-01-01T12:10:00Z\n" +
+				"    Ready:       false\n" +
+				"    Restarts:    5\n" +
+				"\n" +
+				"Conditions:\n" +
+				"  TYPE    STATUS   REASON               MESSAGE\n" +
+				"  Ready   False    ContainersNotReady   containers with unready status: [app]\n" +
+				"\n" +
+				"Events:\n" +
+				"  LAST SEEN              TYPE      REASON      FROM                MESSAGE\n" +
+				"  2025-01-01T12:00:00Z   Normal    Scheduled   default-scheduler   Successfully assigned shop/web-7d4b9c-x1 to worker-1\n" +
+				"  2025-01-01T12:11:00Z   Warning   BackOff     kubelet             Back-off restarting failed container app (x5)\n",
+		},
+		{
+			name: "deployment with its ReplicaSets and pods",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"kind":          "deploy",
+				"name":          "web",
+				"namespace":     "shop",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis")).
+					Return(createMockHttpResponse(http.StatusOK, apiGroups), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1")).
+					Return(createMockHttpResponse(http.StatusOK, appsV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+					Return(createMockHttpResponse(http.StatusOK, `{
+						"apiVersion":"apps/v1","kind":"Deployment",
+						"metadata":{"name":"web","namespace":"shop","uid":"deploy-uid","creationTimestamp":"2025-01-01T11:00:00Z",
+							"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}","team":"shop"},"managedFields":[{"manager":"kubectl"}]},
+						"spec":{"replicas":2,"selector":{"matchLabels":{"app":"web"}}},
+						"status":{"replicas":2,"readyReplicas":1,
+							"conditions":[{"type":"Available","status":"False","reason":"MinimumReplicasUnavailable","message":"Deployment does not have minimum availability."}]}
+					}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/apis/apps/v1/namespaces/shop/replicasets" && opts.QueryParams["labelSelector"] == "app=web"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"items":[
+					{"metadata":{"name":"web-5f6d8b","annotations":{"deployment.kubernetes.io/revision":"1"},"ownerReferences":[{"uid":"deploy-uid"}]},
+						"spec":{"replicas":0},"status":{"replicas":0}},
+					{"metadata":{"name":"web-7d4b9c","annotations":{"deployment.kubernetes.io/revision":"2"},"ownerReferences":[{"uid":"deploy-uid"}]},
+						"spec":{"replicas":2},"status":{"replicas":2,"readyReplicas":1}},
+					{"metadata":{"name":"web-legacy","ownerReferences":[{"uid":"other-uid"}]}}
+				]}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/namespaces/shop/pods" && opts.QueryParams["labelSelector"] == "app=web"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"items":[
+					{"metadata":{"name":"web-7d4b9c-x2"},"spec":{"nodeName":"worker-2","containers":[{"name":"app"}]},
+						"status":{"phase":"Running","containerStatuses":[{"name":"app","ready":true,"state":{"running":{}}}]}},
+					{"metadata":{"name":"web-7d4b9c-x1"},"spec":{"nodeName":"worker-1","containers":[{"name":"app"}]},
+						"status":{"phase":"Running","containerStatuses":[{"name":"app","restartCount":5,"state":{"waiting":{"reason":"CrashLoopBackOff"}}}]}}
+				]}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/events")).
+					Return(createMockHttpResponse(http.StatusOK, `{"items":[]}`), nil)
+			},
+			expectedText: "Name:         web\n" +
+				"Namespace:    shop\n" +
+				"Kind:         Deployment (apps/v1)\n" +
+				"Created:      2025-01-01T11:00:00Z\n" +
+				"Labels:       <none>\n" +
+				"Annotations:  team=shop\n" +
+				"\n" +
+				"spec:\n" +
+				"  replicas: 2\n" +
+				"  selector:\n" +
+				"    matchLabels:\n" +
+				"      app: web\n" +
+				"status:\n" +
+				"  readyReplicas: 1\n" +
+				"  replicas: 2\n" +
+				"\n" +
+				"Conditions:\n" +
+				"  TYPE        STATUS   REASON                       MESSAGE\n" +
+				"  Available   False    MinimumReplicasUnavailable   Deployment does not have minimum availability.\n" +
+				"\n" +
+				"ReplicaSets:\n" +
+				"  NAME         REVISION   DESIRED   CURRENT   READY\n" +
+				"  web-7d4b9c   2          2         2         1\n" +
+				"  web-5f6d8b   1          0         0         0\n" +
+				"\n" +
+				"Pods:\n" +
+				"  NAME            READY   STATUS             RESTARTS   NODE\n" +
+				"  web-7d4b9c-x1   0/1     CrashLoopBackOff   5          worker-1\n" +
+				"  web-7d4b9c-x2   1/1     Running            0          worker-2\n" +
+				"\n" +
+				"Events: <none>\n",
+		},
+		{
+			name: "secret values are hidden",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"kind":          "secret",
+				"name":          "db",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/default/secrets/db")).
+					Return(createMockHttpResponse(http.StatusOK, `{"apiVersion":"v1","kind":"Secret","type":"Opaque",
+						"metadata":{"name":"db","namespace":"default","uid":"secret-uid"},"data":{"password":"aHVudGVyMg=="}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/default/events")).
+					Return(createMockHttpResponse(http.StatusForbidden, `{"kind":"Status","message":"events is forbidden"}`), nil)
+			},
+			expectedText: "Name:         db\n" +
+				"Namespace:    default\n" +
+				"Kind:         Secret (v1)\n" +
+				"Created:      <unknown>\n" +
+				"Labels:       <none>\n" +
+				"Annotations:  <none>\n" +
+				"\n" +
+				"data:\n" +
+				"  password: <7 bytes>\n" +
+				"type: Opaque\n" +
+				"\n" +
+				"Events: could not be retrieved: HTTP 403 Forbidden: events is forbidden\n",
+		},
+		{
+			name: "resource not found",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"kind":          "svc",
+				"name":          "missing",
+				"namespace":     "shop",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/services/missing")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{"kind":"Status","message":"services \"missing\" not found"}`), nil)
+			},
+			expectedText:  "HTTP 404 Not Found\nError: services \"missing\" not found",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleDescribeKubernetesResource()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// kubernetesDiscovery resolves the API resources through the discovery endpoints of the Kubernetes API.
+// The resources of each group version are only requested once.
+type kubernetesDiscovery struct {
+	s             *PortainerMCPServer
+	environmentId int
+	resources     map[schema.GroupVersion][]metav1.APIResource
+	// groupVersions are the preferred versions of the API groups, without the core group
+	groupVersions []schema.GroupVersion
+}
+
+// newKubernetesDiscovery creates a discovery of the Kubernetes API of an environment
+func (s *PortainerMCPServer) newKubernetesDiscovery(environmentId int) *kubernetesDiscovery {
+	return &kubernetesDiscovery{
+		s:             s,
+		environmentId: environmentId,
+		resources:     map[schema.GroupVersion][]metav1.APIResource{},
+	}
+}
+
+// resource returns the API resource of a kind, ignoring the subresources
+func (d *kubernetesDiscovery) resource(gvk schema.GroupVersionKind) (metav1.APIResource, error) {
+	resources, err := d.groupVersionResources(gvk.GroupVersion())
+	if err != nil {
+		return metav1.APIResource{}, err
+	}
+
+	for _, resource := range resources {
+		if resource.Kind == gvk.Kind && !isSubresource(resource) {
+			return resource, nil
+		}
+	}
+
+	return metav1.APIResource{}, fmt.Errorf("the kind %s is not served by the cluster in %s", gvk.Kind, gvk.GroupVersion())
+}
+
+// lookup resolves a resource type the way kubectl does: a kind, a plural or singular resource name or a short name,
+// optionally qualified by its group, e.g. deployments.apps. Unless an API version is specified, the core group is
+// searched first, then the preferred versions of the other API groups.
+func (d *kubernetesDiscovery) lookup(resourceType, apiVersion string) (schema.GroupVersion, metav1.APIResource, error) {
+	name, group, qualified := strings.Cut(strings.ToLower(resourceType), ".")
+
+	if apiVersion != "" {
+		groupVersion, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return schema.GroupVersion{}, metav1.APIResource{}, err
+		}
+
+		resources, err := d.groupVersionResources(groupVersion)
+		if err != nil {
+			return schema.GroupVersion{}, metav1.APIResource{}, err
+		}
+		if resource, found := findResourceType(resources, name); found {
+			return groupVersion, resource, nil
+		}
+		return schema.GroupVersion{}, metav1.APIResource{}, fmt.Errorf("the resource type %s is not served by the cluster in %s", resourceType, groupVersion)
+	}
+
+	if !qualified {
+		coreGroupVersion := schema.GroupVersion{Version: "v1"}
+		resources, err := d.groupVersionResources(coreGroupVersion)
+		if err != nil {
+			return schema.GroupVersion{}, metav1.APIResource{}, err
+		}
+		if resource, found := findResourceType(resources, name); found {
+			return coreGroupVersion, resource, nil
+		}
+	}
+
+	groupVersions, err := d.preferredGroupVersions()
+	if err != nil {
+		return schema.GroupVersion{}, metav1.APIResource{}, err
+	}
+	for _, groupVersion := range groupVersions {
+		if qualified && groupVersion.Group != group {
+			continue
+		}
+
+		// The aggregated APIs can be unavailable, e.g. when the metrics server is down, the other groups are still searched
+		resources, err := d.groupVersionResources(groupVersion)
+		if err != nil {
+			continue
+		}
+		if resource, found := findResourceType(resources, name); found {
+			return groupVersion, resource, nil
+		}
+	}
+
+	return schema.GroupVersion{}, metav1.APIResource{}, fmt.Errorf("the resource type %s is not served by the cluster", resourceType)
+}
+
+// groupVersionResources returns the API resources of a group version
+func (d *kubernetesDiscovery) groupVersionResources(groupVersion schema.GroupVersion) ([]metav1.APIResource, error) {
+	if resources, cached := d.resources[groupVersion]; cached {
+		return resources, nil
+	}
+
+	path := "/apis/" + groupVersion.String()
+	if groupVersion.Group == "" {
+		path = "/api/" + groupVersion.Version
+	}
+
+	var resourceList metav1.APIResourceList
+	if err := d.get(path, &resourceList); err != nil {
+		var notFound notFoundError
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("the API version %s is not served by the cluster", groupVersion)
+		}
+		return nil, err
+	}
+
+	d.resources[groupVersion] = resourceList.APIResources
+	return resourceList.APIResources, nil
+}
+
+// preferredGroupVersions returns the preferred versions of the API groups, without the core group
+func (d *kubernetesDiscovery) preferredGroupVersions() ([]schema.GroupVersion, error) {
+	if d.groupVersions != nil {
+		return d.groupVersions, nil
+	}
+
+	var groupList metav1.APIGroupList
+	if err := d.get("/apis", &groupList); err != nil {
+		return nil, err
+	}
+
+	groupVersions := make([]schema.GroupVersion, 0, len(groupList.Groups))
+	for _, group := range groupList.Groups {
+		groupVersion, err := schema.ParseGroupVersion(group.PreferredVersion.GroupVersion)
+		if err != nil {
+			continue
+		}
+		groupVersions = append(groupVersions, groupVersion)
+	}
+
+	d.groupVersions = groupVersions
+	return groupVersions, nil
+}
+
+// get requests a discovery endpoint and decodes its response into target
+func (d *kubernetesDiscovery) get(path string, target any) error {
+	response, responseBody, err := d.s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+		EnvironmentID: d.environmentId,
+		Path:          path,
+		Method:        "GET",
+	})
+	if err != nil {
+		return err
+	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return kubernetesResponseError(response, responseBody)
+	}
+
+	if err := json.Unmarshal(responseBody, target); err != nil {
+		return fmt.Errorf("failed to decode the discovery response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// findResourceType returns the API resource designated by a lowercase resource type, ignoring the subresources
+func findResourceType(resources []metav1.APIResource, resourceType string) (metav1.APIResource, bool) {
+	for _, resource := range resources {
+		if isSubresource(resource) {
+			continue
+		}
+		if strings.ToLower(resource.Kind) == resourceType || resource.Name == resourceType ||
+			resource.SingularName == resourceType || slices.Contains(resource.ShortNames, resourceType) {
+			return resource, true
+		}
+	}
+	return metav1.APIResource{}, false
+}
+
+// isSubresource checks if an API resource is a subresource, e.g. pods/log
+func isSubresource(resource metav1.APIResource) bool {
+	return strings.Contains(resource.Name, "/")
+}
+
+// kubernetesResourcePath returns the path of an object in the Kubernetes API, or the path of the collection of the
+// objects when the name is empty
+func kubernetesResourcePath(groupVersion schema.GroupVersion, resource, namespace, name string) string {
+	path := "/apis/" + groupVersion.String()
+	if groupVersion.Group == "" {
+		path = "/api/" + groupVersion.Version
+	}
+	if namespace != "" {
+		path += "/namespaces/" + url.PathEscape(namespace)
+	}
+	path += "/" + resource
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	return path
+}
+
+// notFoundError is returned for the Kubernetes API responses with the 404 status code
+type notFoundError struct {
+	message string
+}
+
+func (e notFoundError) Error() string {
+	return e.message
+}
+
+// kubernetesResponseError builds an error from an unsuccessful Kubernetes API response
+func kubernetesResponseError(response *http.Response, body []byte) error {
+	message := fmt.Sprintf("HTTP %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+	if details := extractProxyErrorMessage(body); details != "" {
+		message += ": " + details
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return notFoundError{message: message}
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	coreV1Resources = `{"groupVersion":"v1","resources":[
+		{"name":"namespaces","namespaced":false,"kind":"Namespace","shortNames":["ns"]},
+		{"name":"namespaces/status","namespaced":false,"kind":"Namespace"},
+		{"name":"configmaps","namespaced":true,"kind":"ConfigMap","shortNames":["cm"]},
+		{"name":"pods","singularName":"pod","namespaced":true,"kind":"Pod","shortNames":["po"]},
+		{"name":"pods/log","namespaced":true,"kind":"Pod"},
+		{"name":"secrets","namespaced":true,"kind":"Secret"},
+		{"name":"services","namespaced":true,"kind":"Service","shortNames":["svc"]}
+	]}`
+	appsV1Resources = `{"groupVersion":"apps/v1","resources":[
+		{"name":"deployments","singularName":"deployment","namespaced":true,"kind":"Deployment","shortNames":["deploy"]},
+		{"name":"deployments/scale","namespaced":true,"kind":"Scale"},
+		{"name":"replicasets","singularName":"replicaset","namespaced":true,"kind":"ReplicaSet","shortNames":["rs"]}
+	]}`
+	apiGroups = `{"groups":[
+		{"name":"apps","preferredVersion":{"groupVersion":"apps/v1"}},
+		{"name":"metrics.k8s.io","preferredVersion":{"groupVersion":"metrics.k8s.io/v1beta1"}},
+		{"name":"networking.k8s.io","preferredVersion":{"groupVersion":"networking.k8s.io/v1"}}
+	]}`
+	networkingV1Resources = `{"groupVersion":"networking.k8s.io/v1","resources":[
+		{"name":"ingresses","singularName":"ingress","namespaced":true,"kind":"Ingress","shortNames":["ing"]}
+	]}`
+)
+
+func TestKubernetesDiscoveryLookup(t *testing.T) {
+	tests := []struct {
+		name                 string
+		resourceType         string
+		apiVersion           string
+		setupMock            func(mockClient *MockPortainerClient)
+		expectedGroupVersion schema.GroupVersion
+		expectedResource     string
+		expectedError        string
+	}{
+		{
+			name:         "core short name",
+			resourceType: "po",
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+			},
+			expectedGroupVersion: schema.GroupVersion{Version: "v1"},
+			expectedResource:     "pods",
+		},
+		{
+			name:         "kind of another group",
+			resourceType: "Deployment",
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis")).
+					Return(createMockHttpResponse(http.StatusOK, apiGroups), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1")).
+					Return(createMockHttpResponse(http.StatusOK, appsV1Resources), nil).Once()
+			},
+			expectedGroupVersion: schema.GroupVersion{Group: "apps", Version: "v1"},
+			expectedResource:     "deployments",
+		},
+		{
+			name:         "group qualified resource skipping an unavailable group",
+			resourceType: "ingresses.networking.k8s.io",
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis")).
+					Return(createMockHttpResponse(http.StatusOK, apiGroups), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/networking.k8s.io/v1")).
+					Return(createMockHttpResponse(http.StatusOK, networkingV1Resources), nil).Once()
+			},
+			expectedGroupVersion: schema.GroupVersion{Group: "networking.k8s.io", Version: "v1"},
+			expectedResource:     "ingresses",
+		},
+		{
+			name:         "unknown resource type",
+			resourceType: "widget",
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis")).
+					Return(createMockHttpResponse(http.StatusOK, apiGroups), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1")).
+					Return(createMockHttpResponse(http.StatusOK, appsV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/metrics.k8s.io/v1beta1")).
+					Return(createMockHttpResponse(http.StatusServiceUnavailable, `{"kind":"Status","message":"the server is currently unable to handle the request"}`), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/networking.k8s.io/v1")).
+					Return(createMockHttpResponse(http.StatusOK, networkingV1Resources), nil).Once()
+			},
+			expectedError: "the resource type widget is not served by the cluster",
+		},
+		{
+			name:         "resource type in an API version",
+			resourceType: "rs",
+			apiVersion:   "apps/v1",
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1")).
+					Return(createMockHttpResponse(http.StatusOK, appsV1Resources), nil).Once()
+			},
+			expectedGroupVersion: schema.GroupVersion{Group: "apps", Version: "v1"},
+			expectedResource:     "replicasets",
+		},
+		{
+			name:         "API version not served",
+			resourceType: "widget",
+			apiVersion:   "example.com/v1",
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/example.com/v1")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{"kind":"Status","message":"the server could not find the requested resource"}`), nil).Once()
+			},
+			expectedError: "the API version example.com/v1 is not served by the cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			groupVersion, resource, err := server.newKubernetesDiscovery(1).lookup(tt.resourceType, tt.apiVersion)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedGroupVersion, groupVersion)
+				assert.Equal(t, tt.expectedResource, resource.Name)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -8,11 +8,14 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/dockerutil"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddDockerProxyFeatures() {
+	s.addToolIfExists(ToolDockerProxyStripped, s.HandleDockerProxyStripped())
+
 	if !s.readOnly {
 		s.addToolIfExists(ToolDockerProxy, s.HandleDockerProxy())
 	}
@@ -66,6 +69,15 @@ func (s *PortainerMCPServer) HandleDockerProxy() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("invalid body parameter", err), nil
 		}
 
+		filterExpression, err := parser.GetString("filter", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+		}
+		filter, err := compileFilter(filterExpression)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+		}
+
 		opts := models.DockerProxyRequestOptions{
 			EnvironmentID: environmentId,
 			Path:          dockerAPIPath,
@@ -82,12 +94,92 @@ func (s *PortainerMCPServer) HandleDockerProxy() server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
 		}
+		defer response.Body.Close()
+		s.limitResponseBody(response)
 
 		responseBody, err := io.ReadAll(response.Body)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to read Docker API response", err), nil
 		}
 
-		return mcp.NewToolResultText(string(responseBody)), nil
+		if filter != nil && isSuccessStatusCode(response.StatusCode) {
+			responseBody, err = applyFilter(ctx, filter, responseBody)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to filter Docker API response", err), nil
+			}
+		}
+
+		return s.proxyResult(ctx, response, responseBody), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleDockerProxyStripped() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		dockerAPIPath, err := parser.GetString("dockerAPIPath", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid dockerAPIPath parameter", err), nil
+		}
+		if !strings.HasPrefix(dockerAPIPath, "/") {
+			return mcp.NewToolResultError("dockerAPIPath must start with a leading slash"), nil
+		}
+
+		queryParams, err := parser.GetArrayOfObjects("queryParams", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid queryParams parameter", err), nil
+		}
+		queryParamsMap, err := parseKeyValueMap(queryParams)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid query params", err), nil
+		}
+
+		filterExpression, err := parser.GetString("filter", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+		}
+		filter, err := compileFilter(filterExpression)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+		}
+
+		opts := models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          dockerAPIPath,
+			Method:        "GET",
+			QueryParams:   queryParamsMap,
+		}
+
+		response, err := s.cli.ProxyDockerRequest(opts)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		defer response.Body.Close()
+		s.limitResponseBody(response)
+
+		var responseBody []byte
+		if isSuccessStatusCode(response.StatusCode) {
+			responseBody, err = dockerutil.ProcessRawDockerAPIResponse(response)
+		} else {
+			// Error responses only contain a message, there is nothing to strip
+			responseBody, err = io.ReadAll(response.Body)
+		}
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to process Docker API response", err), nil
+		}
+
+		if filter != nil && isSuccessStatusCode(response.StatusCode) {
+			responseBody, err = applyFilter(ctx, filter, responseBody)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to filter Docker API response", err), nil
+			}
+		}
+
+		return s.proxyResult(ctx, response, responseBody), nil
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -291,13 +292,63 @@ func TestHandleDockerProxy_ClientInteraction(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				assert.Len(t, result.Content, 1)
-				textContent, ok := result.Content[0].(mcp.TextContent)
-				assert.True(t, ok)
-				assert.Equal(t, tc.expect.resultText, textContent.Text)
+				assert.False(t, result.IsError)
+				assert.Equal(t, tc.expect.resultText, proxyResultBody(t, result))
 			}
 
 			mockClient.AssertExpectations(t)
 		})
 	}
 }
+
+func TestHandleDockerProxyStripped(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		response      *http.Response
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "container inspect is stripped",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"dockerAPIPath": "/containers/web/json",
+			},
+			response:     createMockHttpResponse(http.StatusOK, `{"Id":"abc","Image":"sha256:abc","State":{"Status":"running"},"GraphDriver":{"Name":"overlay2"},"HostConfig":{"Privileged":false,"NetworkMode":"bridge"}}`),
+			expectedText: `{"HostConfig":{"NetworkMode":"bridge"},"Id":"abc","Image":"sha256:abc","State":{"Status":"running"}}`,
+		},
+		{
+			name: "error response is returned as is",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"dockerAPIPath": "/containers/missing/json",
+			},
+			response:      createMockHttpResponse(http.StatusNotFound, `{"message":"No such container: missing"}`),
+			expectedText:  `{"message":"No such container: missing"}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+				return opts.Method == "GET" && opts.Path == tt.input["dockerAPIPath"]
+			})).Return(tt.response, nil)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleDockerProxyStripped()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, proxyResultBody(t, result))
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
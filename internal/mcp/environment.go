@@ -3,9 +3,14 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
@@ -19,22 +24,195 @@ func (s *PortainerMCPServer) AddEnvironmentFeatures() {
 	}
 }
 
+const (
+	// defaultEnvironmentsPageSize is the number of environments returned by the listEnvironments tool when limit is not specified
+	defaultEnvironmentsPageSize = 50
+	// maxEnvironmentsPageSize is the maximum number of environments returned by the listEnvironments tool
+	maxEnvironmentsPageSize = 500
+)
+
+// environmentPage is the paginated result of the listEnvironments tool
+// when a JSON output format is used. Environments holds the rendered page.
+type environmentPage struct {
+	Environments json.RawMessage `json:"environments"`
+	TotalCount   int             `json:"total_count"`
+	NextCursor   string          `json:"next_cursor,omitempty"`
+}
+
 func (s *PortainerMCPServer) HandleGetEnvironments() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		environments, err := s.cli.GetEnvironments()
+		parser := toolgen.NewParameterParser(request)
+
+		name, err := parser.GetString("name", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+		}
+
+		status, err := parser.GetString("status", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid status parameter", err), nil
+		}
+
+		types, err := parser.GetArrayOfStrings("types", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid types parameter", err), nil
+		}
+
+		tagIds, err := parser.GetArrayOfIntegers("tagIds", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid tagIds parameter", err), nil
+		}
+
+		tagNames, err := parser.GetArrayOfStrings("tagNames", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid tagNames parameter", err), nil
+		}
+
+		groupId, err := parser.GetInt("groupId", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid groupId parameter", err), nil
+		}
+
+		sortBy, err := parser.GetString("sortBy", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid sortBy parameter", err), nil
+		}
+		if sortBy == "" {
+			sortBy = models.EnvironmentSortByID
+		}
+		if !slices.Contains(environmentSortFields, sortBy) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid sortBy: %s", sortBy)), nil
+		}
+
+		sortOrder, err := parser.GetString("sortOrder", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid sortOrder parameter", err), nil
+		}
+		if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid sortOrder: %s", sortOrder)), nil
+		}
+		// Portainer returns the environments in the ID order when no sort field is given, it cannot reverse it
+		if sortOrder == "desc" && sortBy == models.EnvironmentSortByID {
+			return mcp.NewToolResultError("sortOrder desc is not supported when sorting by id"), nil
+		}
+
+		limit, err := parser.GetInt("limit", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid limit parameter", err), nil
+		}
+		if limit == 0 {
+			limit = defaultEnvironmentsPageSize
+		}
+		if limit < 1 || limit > maxEnvironmentsPageSize {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxEnvironmentsPageSize)), nil
+		}
+
+		cursor, err := parser.GetString("cursor", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid cursor parameter", err), nil
+		}
+		start, err := parseCursor(cursor)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid cursor parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		filter := models.EnvironmentFilter{
+			Search:     name,
+			Types:      types,
+			TagIds:     tagIds,
+			SortBy:     sortBy,
+			Descending: sortOrder == "desc",
+			Start:      start,
+			Limit:      limit,
+		}
+
+		if status != "" {
+			filter.Statuses = []string{status}
+		}
+
+		if groupId != 0 {
+			filter.GroupIds = []int{groupId}
+		}
+
+		if len(tagNames) > 0 {
+			resolvedTagIds, err := s.resolveEnvironmentTagIds(tagNames)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid tagNames parameter", err), nil
+			}
+			filter.TagIds = append(filter.TagIds, resolvedTagIds...)
+		}
+
+		environments, totalCount, err := s.cli.GetEnvironmentsWithFilter(filter)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get environments", err), nil
 		}
 
-		data, err := json.Marshal(environments)
+		nextCursor := ""
+		if end := start + len(environments); len(environments) > 0 && end < totalCount {
+			nextCursor = strconv.Itoa(end)
+		}
+
+		data, err := output.render(environments)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render environments", err), nil
+		}
+
+		if !output.isJSON() {
+			footer := fmt.Sprintf("\ntotal_count: %d", totalCount)
+			if nextCursor != "" {
+				footer += fmt.Sprintf("\nnext_cursor: %s", nextCursor)
+			}
+			return mcp.NewToolResultText(data + footer), nil
+		}
+
+		pageData, err := json.Marshal(environmentPage{
+			Environments: json.RawMessage(data),
+			TotalCount:   totalCount,
+			NextCursor:   nextCursor,
+		})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal environments", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(string(pageData)), nil
 	}
 }
 
+// environmentSortFields lists the fields that can be used to sort environments
+var environmentSortFields = []string{
+	models.EnvironmentSortByID,
+	models.EnvironmentSortByName,
+	models.EnvironmentSortByGroup,
+	models.EnvironmentSortByStatus,
+	models.EnvironmentSortByLastCheckIn,
+}
+
+// resolveEnvironmentTagIds resolves environment tag names to their IDs
+func (s *PortainerMCPServer) resolveEnvironmentTagIds(tagNames []string) ([]int, error) {
+	tags, err := s.cli.GetEnvironmentTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment tags: %w", err)
+	}
+
+	tagIds := make([]int, 0, len(tagNames))
+	for _, tagName := range tagNames {
+		index := slices.IndexFunc(tags, func(tag models.EnvironmentTag) bool {
+			return strings.EqualFold(tag.Name, tagName)
+		})
+		if index == -1 {
+			return nil, fmt.Errorf("unknown tag: %s", tagName)
+		}
+		tagIds = append(tagIds, tags[index].ID)
+	}
+
+	return tagIds, nil
+}
+
 func (s *PortainerMCPServer) HandleUpdateEnvironmentTags() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		parser := toolgen.NewParameterParser(request)
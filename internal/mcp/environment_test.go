@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandleGetEnvironments(t *testing.T) {
@@ -39,7 +42,7 @@ func TestHandleGetEnvironments(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := &MockPortainerClient{}
-			mockClient.On("GetEnvironments").Return(tt.mockEnvironments, tt.mockError)
+			mockClient.On("GetEnvironmentsWithFilter", mock.Anything).Return(tt.mockEnvironments, len(tt.mockEnvironments), tt.mockError)
 
 			server := &PortainerMCPServer{
 				cli: mockClient,
@@ -66,8 +69,14 @@ func TestHandleGetEnvironments(t *testing.T) {
 				textContent, ok := result.Content[0].(mcp.TextContent)
 				assert.True(t, ok)
 
+				var page environmentPage
+				err = json.Unmarshal([]byte(textContent.Text), &page)
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.mockEnvironments), page.TotalCount)
+				assert.Empty(t, page.NextCursor)
+
 				var environments []models.Environment
-				err = json.Unmarshal([]byte(textContent.Text), &environments)
+				err = json.Unmarshal(page.Environments, &environments)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.mockEnvironments, environments)
 			}
@@ -77,6 +86,183 @@ func TestHandleGetEnvironments(t *testing.T) {
 	}
 }
 
+func TestHandleGetEnvironmentsFiltering(t *testing.T) {
+	environments := []models.Environment{
+		{ID: 1, Name: "prod-eu", Status: models.EnvironmentStatusActive},
+		{ID: 2, Name: "prod-us", Status: models.EnvironmentStatusActive},
+	}
+
+	tests := []struct {
+		name           string
+		input          map[string]any
+		expectedFilter models.EnvironmentFilter
+		mockTags       []models.EnvironmentTag
+		mockTotal      int
+		expectedNext   string
+		expectedError  string
+	}{
+		{
+			name:  "default page",
+			input: map[string]any{},
+			expectedFilter: models.EnvironmentFilter{
+				Types:  []string{},
+				TagIds: []int{},
+				SortBy: "id",
+				Limit:  defaultEnvironmentsPageSize,
+			},
+			mockTotal: 2,
+		},
+		{
+			name: "search and sort passed through",
+			input: map[string]any{
+				"name":      "prod-",
+				"sortBy":    "name",
+				"sortOrder": "desc",
+			},
+			expectedFilter: models.EnvironmentFilter{
+				Search:     "prod-",
+				Types:      []string{},
+				TagIds:     []int{},
+				SortBy:     "name",
+				Descending: true,
+				Limit:      defaultEnvironmentsPageSize,
+			},
+			mockTotal: 2,
+		},
+		{
+			name: "native filters and tag names",
+			input: map[string]any{
+				"status":   "active",
+				"types":    []any{"docker-agent"},
+				"tagIds":   []any{float64(1)},
+				"tagNames": []any{"Production"},
+				"groupId":  float64(5),
+			},
+			mockTags: []models.EnvironmentTag{{ID: 7, Name: "production"}},
+			expectedFilter: models.EnvironmentFilter{
+				Statuses: []string{"active"},
+				Types:    []string{"docker-agent"},
+				TagIds:   []int{1, 7},
+				GroupIds: []int{5},
+				SortBy:   "id",
+				Limit:    defaultEnvironmentsPageSize,
+			},
+			mockTotal: 2,
+		},
+		{
+			name: "first page",
+			input: map[string]any{
+				"limit": float64(2),
+			},
+			expectedFilter: models.EnvironmentFilter{
+				Types:  []string{},
+				TagIds: []int{},
+				SortBy: "id",
+				Limit:  2,
+			},
+			mockTotal:    5,
+			expectedNext: "2",
+		},
+		{
+			name: "last page",
+			input: map[string]any{
+				"limit":  float64(2),
+				"cursor": "3",
+			},
+			expectedFilter: models.EnvironmentFilter{
+				Types:  []string{},
+				TagIds: []int{},
+				SortBy: "id",
+				Start:  3,
+				Limit:  2,
+			},
+			mockTotal: 5,
+		},
+		{
+			name:          "invalid cursor",
+			input:         map[string]any{"cursor": "abc"},
+			expectedError: "invalid cursor: abc",
+		},
+		{
+			name:          "limit out of range",
+			input:         map[string]any{"limit": float64(1000)},
+			expectedError: "limit must be between 1 and 500",
+		},
+		{
+			name:          "invalid sortBy",
+			input:         map[string]any{"sortBy": "owner"},
+			expectedError: "invalid sortBy: owner",
+		},
+		{
+			name:          "descending ID order",
+			input:         map[string]any{"sortOrder": "desc"},
+			expectedError: "sortOrder desc is not supported when sorting by id",
+		},
+		{
+			name:          "unknown tag name",
+			input:         map[string]any{"tagNames": []any{"missing"}},
+			mockTags:      []models.EnvironmentTag{{ID: 7, Name: "production"}},
+			expectedError: "unknown tag: missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.mockTags != nil {
+				mockClient.On("GetEnvironmentTags").Return(tt.mockTags, nil)
+			}
+			if tt.expectedError == "" {
+				mockClient.On("GetEnvironmentsWithFilter", tt.expectedFilter).Return(slices.Clone(environments), tt.mockTotal, nil)
+			}
+
+			server := &PortainerMCPServer{cli: mockClient}
+
+			result, err := server.HandleGetEnvironments()(context.Background(), CreateMCPRequest(tt.input))
+			require.NoError(t, err)
+			textContent, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+
+			if tt.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tt.expectedError)
+				mockClient.AssertExpectations(t)
+				return
+			}
+
+			var page environmentPage
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &page))
+			assert.Equal(t, tt.mockTotal, page.TotalCount)
+			assert.Equal(t, tt.expectedNext, page.NextCursor)
+
+			var got []models.Environment
+			require.NoError(t, json.Unmarshal(page.Environments, &got))
+			assert.Equal(t, environments, got)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetEnvironments_TextOutput(t *testing.T) {
+	mockClient := &MockPortainerClient{}
+	mockClient.On("GetEnvironmentsWithFilter", mock.Anything).Return([]models.Environment{
+		{ID: 1, Name: "prod-eu", Status: models.EnvironmentStatusActive},
+	}, 3, nil)
+
+	server := &PortainerMCPServer{cli: mockClient}
+
+	result, err := server.HandleGetEnvironments()(context.Background(), CreateMCPRequest(map[string]any{
+		"limit":        float64(1),
+		"outputFormat": "csv",
+		"fields":       []any{"id", "name"},
+	}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "id,name\n1,prod-eu\n\ntotal_count: 3\nnext_cursor: 1", resultTexts(t, result)[0])
+	mockClient.AssertExpectations(t)
+}
+
 func TestHandleUpdateEnvironmentTags(t *testing.T) {
 	tests := []struct {
 		name        string
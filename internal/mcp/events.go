@@ -0,0 +1,389 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// defaultEventsLimit is the number of events returned by getKubernetesEvents when limit is not specified
+	defaultEventsLimit = 50
+	// maxEventsLimit is the maximum number of events returned by getKubernetesEvents
+	maxEventsLimit = 500
+)
+
+// kubernetesObjectReference is a reference to the object an event is about
+type kubernetesObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// kubernetesEventSeries is the series of the occurrences of an event
+type kubernetesEventSeries struct {
+	Count            int32            `json:"count"`
+	LastObservedTime metav1.MicroTime `json:"lastObservedTime"`
+}
+
+// kubernetesEvent is the subset of a core/v1 Event used by the event tools
+type kubernetesEvent struct {
+	Metadata       metav1.ObjectMeta         `json:"metadata"`
+	InvolvedObject kubernetesObjectReference `json:"involvedObject"`
+	Reason         string                    `json:"reason"`
+	Message        string                    `json:"message"`
+	Type           string                    `json:"type"`
+	Count          int32                     `json:"count"`
+	FirstTimestamp metav1.Time               `json:"firstTimestamp"`
+	LastTimestamp  metav1.Time               `json:"lastTimestamp"`
+	EventTime      metav1.MicroTime          `json:"eventTime"`
+	Series         *kubernetesEventSeries    `json:"series"`
+	Source         struct {
+		Component string `json:"component"`
+	} `json:"source"`
+	ReportingComponent string `json:"reportingComponent"`
+}
+
+// kubernetesEventList is a core/v1 EventList
+type kubernetesEventList struct {
+	Items []kubernetesEvent `json:"items"`
+}
+
+// kubernetesEventV1 is the subset of an events.k8s.io/v1 Event used by the event tools
+type kubernetesEventV1 struct {
+	Metadata            metav1.ObjectMeta         `json:"metadata"`
+	Regarding           kubernetesObjectReference `json:"regarding"`
+	Reason              string                    `json:"reason"`
+	Note                string                    `json:"note"`
+	Type                string                    `json:"type"`
+	EventTime           metav1.MicroTime          `json:"eventTime"`
+	Series              *kubernetesEventSeries    `json:"series"`
+	ReportingController string                    `json:"reportingController"`
+	DeprecatedSource    struct {
+		Component string `json:"component"`
+	} `json:"deprecatedSource"`
+	DeprecatedFirstTimestamp metav1.Time `json:"deprecatedFirstTimestamp"`
+	DeprecatedLastTimestamp  metav1.Time `json:"deprecatedLastTimestamp"`
+	DeprecatedCount          int32       `json:"deprecatedCount"`
+}
+
+// kubernetesEventV1List is an events.k8s.io/v1 EventList
+type kubernetesEventV1List struct {
+	Items []kubernetesEventV1 `json:"items"`
+}
+
+// toCoreEvent converts an events.k8s.io/v1 Event to the equivalent core/v1 Event, both APIs serving the same events
+func (e kubernetesEventV1) toCoreEvent() kubernetesEvent {
+	event := kubernetesEvent{
+		Metadata:           e.Metadata,
+		InvolvedObject:     e.Regarding,
+		Reason:             e.Reason,
+		Message:            e.Note,
+		Type:               e.Type,
+		Count:              e.DeprecatedCount,
+		FirstTimestamp:     e.DeprecatedFirstTimestamp,
+		LastTimestamp:      e.DeprecatedLastTimestamp,
+		EventTime:          e.EventTime,
+		Series:             e.Series,
+		ReportingComponent: e.ReportingController,
+	}
+	event.Source.Component = e.DeprecatedSource.Component
+	return event
+}
+
+// lastSeen returns the last time the event occurred. Depending on the component that recorded it, an event
+// can set any of its timestamps.
+func (e kubernetesEvent) lastSeen() time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	}
+	return e.Metadata.CreationTimestamp.Time
+}
+
+// firstSeen returns the first time the event occurred
+func (e kubernetesEvent) firstSeen() time.Time {
+	switch {
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.lastSeen()
+}
+
+// occurrences returns the number of times the event occurred
+func (e kubernetesEvent) occurrences() int32 {
+	if e.Series != nil && e.Series.Count > 0 {
+		return e.Series.Count
+	}
+	return max(e.Count, 1)
+}
+
+// source returns the component that recorded the event
+func (e kubernetesEvent) source() string {
+	return cmp.Or(e.Source.Component, e.ReportingComponent)
+}
+
+func (s *PortainerMCPServer) HandleGetKubernetesEvents() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		namespace, err := parser.GetString("namespace", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+		}
+
+		involvedObject, err := parser.GetString("involvedObject", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid involvedObject parameter", err), nil
+		}
+		objectKind, objectName, qualified := strings.Cut(involvedObject, "/")
+		if !qualified {
+			objectKind, objectName = "", involvedObject
+		}
+		if qualified && (objectKind == "" || objectName == "") {
+			return mcp.NewToolResultError(fmt.Sprintf("involvedObject must be a name or in the kind/name format, e.g. pod/web-1: %s", involvedObject)), nil
+		}
+
+		eventType, err := parser.GetString("type", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
+		}
+		if eventType != "" && eventType != "Normal" && eventType != "Warning" {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid type: %s, the event types are Normal and Warning", eventType)), nil
+		}
+
+		now := time.Now()
+		var since, until time.Time
+
+		sinceValue, err := parser.GetString("since", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid since parameter", err), nil
+		}
+		if sinceValue != "" {
+			since, err = parseEventTime(sinceValue, now)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid since parameter", err), nil
+			}
+		}
+
+		untilValue, err := parser.GetString("until", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid until parameter", err), nil
+		}
+		if untilValue != "" {
+			until, err = parseEventTime(untilValue, now)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid until parameter", err), nil
+			}
+		}
+
+		limit, err := parser.GetInt("limit", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid limit parameter", err), nil
+		}
+		if limit == 0 {
+			limit = defaultEventsLimit
+		}
+		if limit < 0 || limit > maxEventsLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxEventsLimit)), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		result, rawEvents, err := s.listKubernetesEvents(ctx, environmentId, namespace, eventType, objectName)
+		if result != nil || err != nil {
+			return result, err
+		}
+
+		rawEvents = slices.DeleteFunc(rawEvents, func(event kubernetesEvent) bool {
+			lastSeen := event.lastSeen()
+			return (objectKind != "" && !matchesEventKind(event.InvolvedObject.Kind, objectKind)) ||
+				(!since.IsZero() && lastSeen.Before(since)) ||
+				(!until.IsZero() && lastSeen.After(until))
+		})
+
+		events := mergeKubernetesEvents(rawEvents)
+		events = events[:min(len(events), limit)]
+
+		data, err := output.render(events)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render events", err), nil
+		}
+
+		result, err = s.responses.result(ctx, []byte(data))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to buffer Kubernetes events", err), nil
+		}
+
+		return result, nil
+	}
+}
+
+// listKubernetesEvents lists the events of a namespace, or of all the namespaces, filtered by type and by the name of
+// their object. The events.k8s.io/v1 API is used, the core/v1 API being only used on the clusters that do not serve it.
+// A tool result is returned when the request is not successful, to be returned as is by the handler.
+func (s *PortainerMCPServer) listKubernetesEvents(ctx context.Context, environmentId int, namespace, eventType, objectName string) (*mcp.CallToolResult, []kubernetesEvent, error) {
+	fieldSelector := func(objectField string) string {
+		var selectors []string
+		if eventType != "" {
+			selectors = append(selectors, "type="+eventType)
+		}
+		if objectName != "" {
+			selectors = append(selectors, objectField+".name="+objectName)
+		}
+		return strings.Join(selectors, ",")
+	}
+
+	opts := models.KubernetesProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          kubernetesResourcePath(schema.GroupVersion{Group: "events.k8s.io", Version: "v1"}, "events", namespace, ""),
+		Method:        "GET",
+		QueryParams:   map[string]string{"fieldSelector": fieldSelector("regarding")},
+	}
+	response, responseBody, err := s.sendKubernetesRequest(opts)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil, nil
+	}
+
+	if response.StatusCode != http.StatusNotFound {
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil, nil
+		}
+
+		var eventList kubernetesEventV1List
+		if err := json.Unmarshal(responseBody, &eventList); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode Kubernetes API response", err), nil, nil
+		}
+
+		events := make([]kubernetesEvent, 0, len(eventList.Items))
+		for _, event := range eventList.Items {
+			events = append(events, event.toCoreEvent())
+		}
+		return nil, events, nil
+	}
+
+	var eventList kubernetesEventList
+	if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          kubernetesResourcePath(schema.GroupVersion{Version: "v1"}, "events", namespace, ""),
+		Method:        "GET",
+		QueryParams:   map[string]string{"fieldSelector": fieldSelector("involvedObject")},
+	}, &eventList); result != nil || err != nil {
+		return result, nil, err
+	}
+
+	return nil, eventList.Items, nil
+}
+
+// mergeKubernetesEvents merges the events with the same type, reason and object, summing their occurrences and keeping
+// the message of the most recent one. The merged events are sorted from the most recent to the oldest.
+func mergeKubernetesEvents(rawEvents []kubernetesEvent) []models.KubernetesEvent {
+	type eventKey struct {
+		eventType, reason, namespace, kind, name string
+	}
+	type mergedEvent struct {
+		latest              kubernetesEvent
+		count               int32
+		firstSeen, lastSeen time.Time
+	}
+
+	merged := map[eventKey]*mergedEvent{}
+	var keys []eventKey
+	for _, event := range rawEvents {
+		key := eventKey{event.Type, event.Reason, cmp.Or(event.InvolvedObject.Namespace, event.Metadata.Namespace), event.InvolvedObject.Kind, event.InvolvedObject.Name}
+
+		current, exists := merged[key]
+		if !exists {
+			merged[key] = &mergedEvent{latest: event, count: event.occurrences(), firstSeen: event.firstSeen(), lastSeen: event.lastSeen()}
+			keys = append(keys, key)
+			continue
+		}
+
+		current.count += event.occurrences()
+		if firstSeen := event.firstSeen(); firstSeen.Before(current.firstSeen) {
+			current.firstSeen = firstSeen
+		}
+		if lastSeen := event.lastSeen(); !lastSeen.Before(current.lastSeen) {
+			current.lastSeen = lastSeen
+			current.latest = event
+		}
+	}
+
+	slices.SortStableFunc(keys, func(a, b eventKey) int {
+		return merged[b].lastSeen.Compare(merged[a].lastSeen)
+	})
+
+	events := make([]models.KubernetesEvent, 0, len(keys))
+	for _, key := range keys {
+		event := merged[key]
+		events = append(events, models.KubernetesEvent{
+			Namespace: key.namespace,
+			Object:    key.kind + "/" + key.name,
+			Type:      key.eventType,
+			Reason:    key.reason,
+			Message:   strings.TrimSpace(event.latest.Message),
+			Count:     event.count,
+			FirstSeen: formatKubernetesTime(event.firstSeen),
+			LastSeen:  formatKubernetesTime(event.lastSeen),
+			Source:    event.latest.source(),
+		})
+	}
+
+	return events
+}
+
+// matchesEventKind checks if the kind of the object of an event matches a kind parameter, which can be the kind,
+// its plural or one of the workload aliases, e.g. deploy
+func matchesEventKind(eventKind, kind string) bool {
+	kind = strings.ToLower(kind)
+	eventKind = strings.ToLower(eventKind)
+	return kind == eventKind || kind == eventKind+"s" || workloadResources[kind] == eventKind+"s"
+}
+
+// parseEventTime parses a time parameter of the event tools, which can be a Unix timestamp, a RFC 3339 date or a
+// duration relative to now (e.g. 10m, 2h)
+func parseEventTime(value string, now time.Time) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%s is not a Unix timestamp, a RFC 3339 date or a duration", value)
+}
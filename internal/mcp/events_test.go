@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetKubernetesEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "warning events merged by reason and object",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"type":          "Warning",
+				"outputFormat":  "csv",
+				"fields":        []any{"object", "reason", "count", "first_seen", "last_seen", "message", "source"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/apis/events.k8s.io/v1/namespaces/shop/events" &&
+						assert.ObjectsAreEqual(map[string]string{"fieldSelector": "type=Warning"}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK, `{"items":[
+					{"metadata":{"namespace":"shop"},"regarding":{"kind":"Pod","namespace":"shop","name":"web-1"},"type":"Warning","reason":"BackOff",
+						"note":"Back-off restarting failed container app","deprecatedCount":3,"reportingController":"kubelet",
+						"deprecatedFirstTimestamp":"2025-01-01T12:00:00Z","deprecatedLastTimestamp":"2025-01-01T12:05:00Z"},
+					{"metadata":{"namespace":"shop"},"regarding":{"kind":"Pod","namespace":"shop","name":"web-2"},"type":"Warning","reason":"FailedScheduling",
+						"note":"0/3 nodes are available: 3 Insufficient memory.","eventTime":"2025-01-01T12:07:00.000000Z","reportingController":"default-scheduler"},
+					{"metadata":{"namespace":"shop"},"regarding":{"kind":"Pod","namespace":"shop","name":"web-1"},"type":"Warning","reason":"BackOff",
+						"note":"Back-off restarting failed container app (exit code 1)","eventTime":"2025-01-01T12:06:00.000000Z","reportingController":"kubelet",
+						"series":{"count":4,"lastObservedTime":"2025-01-01T12:10:00.000000Z"}}
+				]}`), nil)
+			},
+			expectedText: "object,reason,count,first_seen,last_seen,message,source\n" +
+				"Pod/web-1,BackOff,7,2025-01-01T12:00:00Z,2025-01-01T12:10:00Z,Back-off restarting failed container app (exit code 1),kubelet\n" +
+				"Pod/web-2,FailedScheduling,1,2025-01-01T12:07:00Z,2025-01-01T12:07:00Z,0/3 nodes are available: 3 Insufficient memory.,default-scheduler\n",
+		},
+		{
+			name: "core events of an object in a time window",
+			input: map[string]any{
+				"environmentId":  float64(1),
+				"involvedObject": "deploy/web",
+				"since":          "2025-01-01T12:00:00Z",
+				"outputFormat":   "csv",
+				"fields":         []any{"namespace", "object", "type", "reason", "count"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/events.k8s.io/v1/events")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{"kind":"Status","message":"the server could not find the requested resource"}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/events" &&
+						assert.ObjectsAreEqual(map[string]string{"fieldSelector": "involvedObject.name=web"}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK, `{"items":[
+					{"involvedObject":{"kind":"Deployment","namespace":"shop","name":"web"},"type":"Normal","reason":"ScalingReplicaSet",
+						"message":"Scaled up replica set web-7d4b9c to 2","count":1,"lastTimestamp":"2025-01-01T12:01:00Z"},
+					{"involvedObject":{"kind":"Deployment","namespace":"shop","name":"web"},"type":"Normal","reason":"ScalingReplicaSet",
+						"message":"Scaled up replica set web-5f6d8b to 1","count":1,"lastTimestamp":"2025-01-01T11:00:00Z"},
+					{"involvedObject":{"kind":"Service","namespace":"shop","name":"web"},"type":"Warning","reason":"SyncLoadBalancerFailed",
+						"message":"no available nodes","count":2,"lastTimestamp":"2025-01-01T12:02:00Z"}
+				]}`), nil)
+			},
+			expectedText: "namespace,object,type,reason,count\n" +
+				"shop,Deployment/web,Normal,ScalingReplicaSet,1\n",
+		},
+		{
+			name: "events API error",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/events.k8s.io/v1/namespaces/shop/events")).
+					Return(createMockHttpResponse(http.StatusForbidden, `{"kind":"Status","message":"events.events.k8s.io is forbidden"}`), nil)
+			},
+			expectedText:  "HTTP 403 Forbidden\nError: events.events.k8s.io is forbidden",
+			expectedError: true,
+		},
+		{
+			name: "invalid type",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"type":          "Error",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "invalid type: Error, the event types are Normal and Warning",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleGetKubernetesEvents()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetKubernetesEventsTruncated(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/events.k8s.io/v1/namespaces/shop/events")).
+		Return(createMockHttpResponse(http.StatusOK, `{"items":[
+			{"metadata":{"namespace":"shop"},"regarding":{"kind":"Pod","namespace":"shop","name":"web-1"},"type":"Warning","reason":"BackOff",
+				"note":"Back-off restarting failed container app","eventTime":"2025-01-01T12:06:00.000000Z","reportingController":"kubelet"}
+		]}`), nil)
+
+	server := &PortainerMCPServer{
+		cli:       mockClient,
+		responses: newResponseBuffer(16),
+	}
+
+	handler := server.HandleGetKubernetesEvents()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"namespace":     "shop",
+		"outputFormat":  "csv",
+		"fields":        []any{"object", "reason", "message"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	texts := resultTexts(t, result)
+	require.Len(t, texts, 2)
+	assert.Equal(t, "object,reason,me", texts[0])
+	assert.Contains(t, texts[1], "response truncated")
+
+	result, err = server.responses.next(context.Background(), continuationToken(t, result))
+	require.NoError(t, err)
+	assert.Equal(t, "ssage\nPod/web-1,", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestParseEventTime(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{name: "unix timestamp", value: "1735732800", expected: now},
+		{name: "RFC 3339 date", value: "2025-01-01T11:00:00Z", expected: now.Add(-time.Hour)},
+		{name: "relative duration", value: "30m", expected: now.Add(-30 * time.Minute)},
+		{name: "invalid value", value: "yesterday", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseEventTime(tt.value, now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(parsed), "expected %s, got %s", tt.expected, parsed)
+		})
+	}
+}
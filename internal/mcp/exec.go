@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// defaultExecTimeout is the time the execInContainer tool waits for the command when timeout is not specified
+	defaultExecTimeout = 30 * time.Second
+	// maxExecTimeout is the maximum time the execInContainer tool waits for the command
+	maxExecTimeout = 300 * time.Second
+	// maxExecOutputSize is the maximum number of bytes of stdout and of stderr returned by the execInContainer tool
+	maxExecOutputSize = 64 * 1024
+	// execAllowAll is the allowlist entry allowing any command
+	execAllowAll = "*"
+)
+
+// DefaultExecAllowlist is the list of commands allowed by the execInContainer tool when no allowlist is configured.
+// It only contains commands that inspect the container without modifying it, and none of them can run another
+// command: env is left out for that reason, printenv reads the environment.
+var DefaultExecAllowlist = []string{
+	"cat", "date", "df", "du", "free", "hostname", "id", "ls", "netstat",
+	"nslookup", "printenv", "ps", "stat", "uname", "uptime", "whoami",
+}
+
+// limitedBuffer is a buffer keeping the first bytes written to it, the bytes over the limit are discarded
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buf.Len()
+	if len(p) > remaining {
+		b.truncated = true
+		b.buf.Write(p[:max(remaining, 0)])
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+func (s *PortainerMCPServer) HandleExecInContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		containerId, err := parser.GetString("containerId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid containerId parameter", err), nil
+		}
+
+		command, err := parser.GetArrayOfStrings("command", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid command parameter", err), nil
+		}
+		if len(command) == 0 || command[0] == "" {
+			return mcp.NewToolResultError("command must not be empty"), nil
+		}
+		if !isExecAllowed(s.execAllowlist, command[0]) {
+			return mcp.NewToolResultError(fmt.Sprintf("command %s is not allowed, the allowed commands are: %s", command[0], strings.Join(s.execAllowlist, ", "))), nil
+		}
+
+		env, err := parser.GetArrayOfStrings("env", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid env parameter", err), nil
+		}
+
+		workingDir, err := parser.GetString("workingDir", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid workingDir parameter", err), nil
+		}
+
+		user, err := parser.GetString("user", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid user parameter", err), nil
+		}
+
+		// With a restricted allowlist, PATH or LD_PRELOAD would run another binary than the allowed command
+		// and the user would run it as root, they are only accepted when any command can be run anyway
+		if !slices.Contains(s.execAllowlist, execAllowAll) && (len(env) > 0 || user != "") {
+			return mcp.NewToolResultError("env and user can only be set when the server allows all the commands"), nil
+		}
+
+		timeoutSeconds, err := parser.GetInt("timeout", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid timeout parameter", err), nil
+		}
+		timeout := defaultExecTimeout
+		if timeoutSeconds != 0 {
+			timeout = time.Duration(timeoutSeconds) * time.Second
+		}
+		if timeout < time.Second || timeout > maxExecTimeout {
+			return mcp.NewToolResultError(fmt.Sprintf("timeout must be between 1 and %d seconds", int(maxExecTimeout.Seconds()))), nil
+		}
+
+		createBody, err := json.Marshal(container.ExecOptions{
+			User:         user,
+			AttachStdout: true,
+			AttachStderr: true,
+			Env:          env,
+			WorkingDir:   workingDir,
+			Cmd:          command,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to encode exec configuration", err), nil
+		}
+
+		var exec container.ExecCreateResponse
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/containers/%s/exec", url.PathEscape(containerId)),
+			Method:        "POST",
+			Headers:       map[string]string{"Content-Type": "application/json"},
+			Body:          bytes.NewReader(createBody),
+		}, &exec); result != nil || err != nil {
+			return result, err
+		}
+
+		stdout := &limitedBuffer{limit: maxExecOutputSize}
+		stderr := &limitedBuffer{limit: maxExecOutputSize}
+		result, completed, err := s.startExec(ctx, environmentId, exec.ID, timeout, stdout, stderr)
+		if result != nil || err != nil {
+			return result, err
+		}
+
+		var inspect container.ExecInspect
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/exec/%s/json", url.PathEscape(exec.ID)),
+			Method:        "GET",
+		}, &inspect); result != nil || err != nil {
+			return result, err
+		}
+
+		return mcp.NewToolResultText(formatExecResult(inspect, completed || !inspect.Running, timeout, stdout, stderr)), nil
+	}
+}
+
+// startExec starts an exec instance and demultiplexes its output until the command exits or the timeout expires.
+// The command keeps running in the container after the timeout, only the output is no longer collected.
+// A tool result is returned when the request is not successful, to be returned as is by the handler.
+func (s *PortainerMCPServer) startExec(ctx context.Context, environmentId int, execId string, timeout time.Duration, stdout, stderr *limitedBuffer) (*mcp.CallToolResult, bool, error) {
+	startBody, err := json.Marshal(container.ExecStartOptions{})
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to encode exec start options", err), false, nil
+	}
+
+	response, err := s.cli.ProxyDockerRequest(models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          fmt.Sprintf("/exec/%s/start", url.PathEscape(execId)),
+		Method:        "POST",
+		Headers:       map[string]string{"Content-Type": "application/json"},
+		Body:          bytes.NewReader(startBody),
+	})
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), false, nil
+	}
+	defer response.Body.Close()
+
+	if !isSuccessStatusCode(response.StatusCode) {
+		responseBody, err := io.ReadAll(response.Body)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to read Docker API response", err), false, nil
+		}
+		return s.proxyResult(ctx, response, responseBody), false, nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderr, response.Body)
+		done <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to read command output", err), false, nil
+		}
+		return nil, true, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	// Closing the body stops the copy, the buffers can be read once it returned
+	response.Body.Close()
+	<-done
+	return nil, false, nil
+}
+
+// isExecAllowed checks if a command can be run by the execInContainer tool. A bare executable name is matched against
+// the names of the allowlist, an executable path must be in the allowlist as is: any binary named like an allowed
+// command could be dropped in another directory of the container.
+func isExecAllowed(allowlist []string, executable string) bool {
+	return slices.Contains(allowlist, execAllowAll) || slices.Contains(allowlist, executable)
+}
+
+// formatExecResult renders the exit code and the output of a command
+func formatExecResult(inspect container.ExecInspect, completed bool, timeout time.Duration, stdout, stderr *limitedBuffer) string {
+	var sb strings.Builder
+
+	if completed {
+		fmt.Fprintf(&sb, "Exit code: %d\n", inspect.ExitCode)
+	} else {
+		fmt.Fprintf(&sb, "Exit code: unknown, the command was still running after %s. It was not stopped, only the output up to that point is returned.\n", timeout)
+	}
+
+	for _, stream := range []struct {
+		name   string
+		buffer *limitedBuffer
+	}{{"stdout", stdout}, {"stderr", stderr}} {
+		if stream.buffer.buf.Len() == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "=== %s ===\n", stream.name)
+		sb.WriteString(stripANSI(stream.buffer.buf.String()))
+		if !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+		if stream.buffer.truncated {
+			fmt.Fprintf(&sb, "[%s truncated to the first %s]\n", stream.name, formatSize(maxExecOutputSize))
+		}
+	}
+
+	if stdout.buf.Len() == 0 && stderr.buf.Len() == 0 {
+		sb.WriteString("(no output)\n")
+	}
+
+	return sb.String()
+}
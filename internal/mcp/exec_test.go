@@ -0,0 +1,303 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectExec sets up the exec creation and inspection requests of a command run in the web container
+func expectExec(mockClient *MockPortainerClient, inspect string) {
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "POST" && opts.Path == "/containers/web/exec"
+	})).Return(createMockHttpResponse(http.StatusCreated, `{"Id":"e1"}`), nil)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" && opts.Path == "/exec/e1/json"
+	})).Return(createMockHttpResponse(http.StatusOK, inspect), nil)
+}
+
+// matchExecStart matches the start request of the exec instance
+func matchExecStart() any {
+	return mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "POST" && opts.Path == "/exec/e1/start"
+	})
+}
+
+func TestHandleExecInContainer(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "command with stdout and stderr",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls", "/etc/nginx", "/missing"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				expectExec(mockClient, `{"ID":"e1","Running":false,"ExitCode":2}`)
+				mockClient.On("ProxyDockerRequest", matchExecStart()).
+					Return(createMockHttpResponse(http.StatusOK, string(multiplexLogs(t,
+						logFrame{stdcopy.Stdout, "/etc/nginx:\nnginx.conf\n"},
+						logFrame{stdcopy.Stderr, "ls: /missing: No such file or directory\n"},
+					))), nil)
+			},
+			expectedText: "Exit code: 2\n" +
+				"=== stdout ===\n/etc/nginx:\nnginx.conf\n" +
+				"=== stderr ===\nls: /missing: No such file or directory\n",
+		},
+		{
+			name: "command without output",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"stat", "/tmp"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				expectExec(mockClient, `{"ID":"e1","Running":false,"ExitCode":0}`)
+				mockClient.On("ProxyDockerRequest", matchExecStart()).
+					Return(createMockHttpResponse(http.StatusOK, ""), nil)
+			},
+			expectedText: "Exit code: 0\n(no output)\n",
+		},
+		{
+			name: "command still running after the timeout",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ping", "db"},
+				"timeout":       float64(1),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				expectExec(mockClient, `{"ID":"e1","Running":true,"ExitCode":0}`)
+
+				// The stream is never closed by the command, only by the handler
+				reader, writer := io.Pipe()
+				go func() {
+					_, _ = stdcopy.NewStdWriter(writer, stdcopy.Stdout).Write([]byte("64 bytes from db: seq=0\n"))
+				}()
+				mockClient.On("ProxyDockerRequest", matchExecStart()).
+					Return(&http.Response{StatusCode: http.StatusOK, Body: reader}, nil)
+			},
+			expectedText: "Exit code: unknown, the command was still running after 1s. It was not stopped, only the output up to that point is returned.\n" +
+				"=== stdout ===\n64 bytes from db: seq=0\n",
+		},
+		{
+			name: "command not allowed",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"rm", "-rf", "/data"},
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "command rm is not allowed, the allowed commands are: ls, ping, stat",
+			expectedError: true,
+		},
+		{
+			name: "command run through env",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"env", "sh", "-c", "rm -rf /data"},
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "command env is not allowed, the allowed commands are: ls, ping, stat",
+			expectedError: true,
+		},
+		{
+			name: "allowed name in an arbitrary directory",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"/tmp/x/ls"},
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "command /tmp/x/ls is not allowed, the allowed commands are: ls, ping, stat",
+			expectedError: true,
+		},
+		{
+			name: "PATH set to run a planted binary",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls"},
+				"env":           []any{"PATH=/tmp/x"},
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "env and user can only be set when the server allows all the commands",
+			expectedError: true,
+		},
+		{
+			name: "LD_PRELOAD set to inject code",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls"},
+				"env":           []any{"LD_PRELOAD=/tmp/x/inject.so"},
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "env and user can only be set when the server allows all the commands",
+			expectedError: true,
+		},
+		{
+			name: "command run as root",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls"},
+				"user":          "root",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "env and user can only be set when the server allows all the commands",
+			expectedError: true,
+		},
+		{
+			name: "container ID escaped in the path",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "../images/x",
+				"command":       []any{"ls"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/containers/..%2Fimages%2Fx/exec"
+				})).Return(createMockHttpResponse(http.StatusNotFound, `{"message":"No such container: ../images/x"}`), nil)
+			},
+			expectedText:  "HTTP 404 Not Found\nError: No such container: ../images/x",
+			expectedError: true,
+		},
+		{
+			name: "container not running",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.Anything).
+					Return(createMockHttpResponse(http.StatusConflict, `{"message":"container web is not running"}`), nil)
+			},
+			expectedText:  "HTTP 409 Conflict\nError: container web is not running",
+			expectedError: true,
+		},
+		{
+			name: "timeout out of range",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls"},
+				"timeout":       float64(600),
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "timeout must be between 1 and 300 seconds",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli:           mockClient,
+				execAllowlist: []string{"ls", "ping", "stat"},
+			}
+
+			handler := server.HandleExecInContainer()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleExecInContainer_ExecConfiguration(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+
+	var execOptions container.ExecOptions
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/containers/web/exec"
+	})).Run(func(args mock.Arguments) {
+		opts := args.Get(0).(models.DockerProxyRequestOptions)
+		require.NoError(t, json.NewDecoder(opts.Body).Decode(&execOptions))
+	}).Return(createMockHttpResponse(http.StatusCreated, `{"Id":"e1"}`), nil)
+	mockClient.On("ProxyDockerRequest", matchExecStart()).Return(createMockHttpResponse(http.StatusOK, ""), nil)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/exec/e1/json"
+	})).Return(createMockHttpResponse(http.StatusOK, `{"ExitCode":0}`), nil)
+
+	server := &PortainerMCPServer{
+		cli:           mockClient,
+		execAllowlist: []string{execAllowAll},
+	}
+
+	handler := server.HandleExecInContainer()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"containerId":   "web",
+		"command":       []any{"psql", "-c", "select 1"},
+		"env":           []any{"PGUSER=postgres"},
+		"workingDir":    "/tmp",
+		"user":          "postgres",
+	}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, container.ExecOptions{
+		User:         "postgres",
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          []string{"PGUSER=postgres"},
+		WorkingDir:   "/tmp",
+		Cmd:          []string{"psql", "-c", "select 1"},
+	}, execOptions)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	buffer := &limitedBuffer{limit: 8}
+
+	n, err := buffer.Write([]byte("hello "))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	copied, err := io.Copy(buffer, strings.NewReader("world"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), copied)
+
+	assert.Equal(t, "hello wo", buffer.buf.String())
+	assert.True(t, buffer.truncated)
+}
+
+func TestIsExecAllowed(t *testing.T) {
+	assert.True(t, isExecAllowed(DefaultExecAllowlist, "ls"))
+	assert.False(t, isExecAllowed(DefaultExecAllowlist, "sh"))
+	assert.False(t, isExecAllowed(DefaultExecAllowlist, "env"), "env can run any other command")
+	assert.False(t, isExecAllowed(DefaultExecAllowlist, "/tmp/x/ls"))
+	assert.False(t, isExecAllowed(DefaultExecAllowlist, "/bin/cat"))
+	assert.False(t, isExecAllowed(DefaultExecAllowlist, "./ls"))
+	assert.True(t, isExecAllowed([]string{"ls", "/bin/cat"}, "/bin/cat"))
+	assert.False(t, isExecAllowed([]string{"ls", "/bin/cat"}, "cat"))
+	assert.False(t, isExecAllowed(nil, "ls"))
+	assert.True(t, isExecAllowed([]string{"*"}, "sh"))
+}
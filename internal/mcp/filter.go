@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itchyny/gojq"
+)
+
+// filterTimeout is the maximum duration of the evaluation of a filter expression
+const filterTimeout = 5 * time.Second
+
+// compileFilter compiles a jq expression. It returns nil if the expression is empty.
+// The expression is compiled before sending the request so that an invalid filter
+// does not prevent from reading the result of a write operation.
+func compileFilter(expression string) (*gojq.Code, error) {
+	if expression == "" {
+		return nil, nil
+	}
+
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	return code, nil
+}
+
+// applyFilter evaluates a compiled jq expression on a JSON document and returns the result as JSON.
+// The values produced by the expression are always returned as a JSON array, even when there is
+// only one, so that the shape of the result does not depend on the data.
+func applyFilter(ctx context.Context, code *gojq.Code, data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return data, nil // Nothing to filter
+	}
+
+	var input any
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, filterTimeout)
+	defer cancel()
+
+	results := []any{}
+	iter := code.RunWithContext(ctx, input)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			return nil, fmt.Errorf("failed to evaluate filter expression: %w", err)
+		}
+		results = append(results, value)
+	}
+
+	return json.Marshal(results)
+}
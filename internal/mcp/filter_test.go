@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFilter(t *testing.T) {
+	containers := `[{"Id":"1","Names":["/web"],"State":"running","Image":"nginx"},{"Id":"2","Names":["/db"],"State":"exited","Image":"postgres"}]`
+
+	tests := []struct {
+		name          string
+		data          string
+		expression    string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:       "object projection on each item",
+			data:       containers,
+			expression: ".[] | {Names, State}",
+			expected:   `[{"Names":["/web"],"State":"running"},{"Names":["/db"],"State":"exited"}]`,
+		},
+		{
+			name:       "single result",
+			data:       containers,
+			expression: "map(select(.State == \"running\")) | length",
+			expected:   `[1]`,
+		},
+		{
+			name:       "single object",
+			data:       containers,
+			expression: ".[0] | {Id}",
+			expected:   `[{"Id":"1"}]`,
+		},
+		{
+			name:       "no result",
+			data:       containers,
+			expression: ".[] | select(.State == \"paused\")",
+			expected:   `[]`,
+		},
+		{
+			name:       "empty body",
+			data:       "",
+			expression: ".",
+			expected:   "",
+		},
+		{
+			name:          "invalid JSON",
+			data:          "not json",
+			expression:    ".",
+			expectedError: "response is not valid JSON",
+		},
+		{
+			name:          "evaluation error",
+			data:          containers,
+			expression:    ".Id",
+			expectedError: "failed to evaluate filter expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := compileFilter(tt.expression)
+			require.NoError(t, err)
+
+			result, err := applyFilter(context.Background(), code, []byte(tt.data))
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	code, err := compileFilter("")
+	assert.NoError(t, err)
+	assert.Nil(t, code)
+
+	_, err = compileFilter(".[] | {")
+	assert.ErrorContains(t, err, "invalid filter expression")
+
+	_, err = compileFilter("undefined_function")
+	assert.ErrorContains(t, err, "invalid filter expression")
+}
+
+func TestHandleDockerProxy_Filter(t *testing.T) {
+	t.Run("filtered response", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+		mockClient.On("ProxyDockerRequest", mock.AnythingOfType("models.DockerProxyRequestOptions")).
+			Return(createMockHttpResponse(http.StatusOK, `[{"Id":"1","Names":["/web"],"State":"running"}]`), nil)
+
+		server := &PortainerMCPServer{
+			cli: mockClient,
+		}
+
+		handler := server.HandleDockerProxy()
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"dockerAPIPath": "/containers/json",
+			"method":        "GET",
+			"filter":        ".[] | {Names, State}",
+		}))
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, `[{"Names":["/web"],"State":"running"}]`, proxyResultBody(t, result))
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("invalid filter is rejected before sending the request", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+
+		server := &PortainerMCPServer{
+			cli: mockClient,
+		}
+
+		handler := server.HandleDockerProxy()
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"dockerAPIPath": "/containers/web/stop",
+			"method":        "POST",
+			"filter":        ".[",
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultTexts(t, result)[0], "invalid filter parameter")
+
+		mockClient.AssertNotCalled(t, "ProxyDockerRequest", mock.Anything)
+	})
+}
+
+func TestHandleKubernetesProxyStripped_Filter(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyKubernetesRequest", mock.AnythingOfType("models.KubernetesProxyRequestOptions")).
+		Return(createMockHttpResponse(http.StatusOK, `{"apiVersion":"v1","kind":"PodList","items":[{"metadata":{"name":"web","managedFields":[]},"status":{"phase":"Running"}}]}`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleKubernetesProxyStripped()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId":     float64(1),
+		"kubernetesAPIPath": "/api/v1/pods",
+		"filter":            ".items[] | {name: .metadata.name, phase: .status.phase}",
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, `[{"name":"web","phase":"Running"}]`, proxyResultBody(t, result))
+
+	mockClient.AssertExpectations(t)
+}
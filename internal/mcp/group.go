@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -23,17 +22,24 @@ func (s *PortainerMCPServer) AddEnvironmentGroupFeatures() {
 
 func (s *PortainerMCPServer) HandleGetEnvironmentGroups() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
 		edgeGroups, err := s.cli.GetEnvironmentGroups()
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get environment groups", err), nil
 		}
 
-		data, err := json.Marshal(edgeGroups)
+		data, err := output.render(edgeGroups)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to marshal environment groups", err), nil
+			return mcp.NewToolResultErrorFromErr("failed to render environment groups", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(data), nil
 	}
 }
 
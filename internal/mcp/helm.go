@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"gopkg.in/yaml.v3"
+)
+
+func (s *PortainerMCPServer) AddHelmFeatures() {
+	s.addToolIfExists(ToolListHelmReleases, s.HandleListHelmReleases())
+	s.addToolIfExists(ToolGetHelmReleaseValues, s.HandleGetHelmReleaseValues())
+	s.addToolIfExists(ToolGetHelmReleaseHistory, s.HandleGetHelmReleaseHistory())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolInstallHelmChart, s.HandleInstallHelmChart())
+		s.addToolIfExists(ToolUninstallHelmRelease, s.HandleUninstallHelmRelease())
+	}
+}
+
+func (s *PortainerMCPServer) HandleListHelmReleases() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		namespace, err := parser.GetString("namespace", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		releases, err := s.cli.GetHelmReleases(environmentId, namespace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm releases", err), nil
+		}
+
+		data, err := output.render(releases)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render helm releases", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleGetHelmReleaseValues() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, name, namespace, errResult := parseHelmReleaseParameters(parser)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		revision, err := parser.GetInt("revision", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid revision parameter", err), nil
+		}
+		if revision < 0 {
+			return mcp.NewToolResultError("revision must be greater than 0"), nil
+		}
+
+		all, err := parser.GetBoolean("all", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid all parameter", err), nil
+		}
+
+		values, err := s.cli.GetHelmReleaseValues(environmentId, name, namespace, revision)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm release values", err), nil
+		}
+
+		data := values.UserSupplied
+		if all {
+			data = values.Computed
+		}
+		if strings.TrimSpace(data) == "" || strings.TrimSpace(data) == "{}" {
+			if all {
+				return mcp.NewToolResultText(fmt.Sprintf("Helm release %s/%s has no values", namespace, name)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Helm release %s/%s has no user supplied values, set all to get the default values of the chart", namespace, name)), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleGetHelmReleaseHistory() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, name, namespace, errResult := parseHelmReleaseParameters(parser)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		history, err := s.cli.GetHelmReleaseHistory(environmentId, name, namespace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm release history", err), nil
+		}
+
+		data, err := output.render(history)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render helm release history", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleInstallHelmChart() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, name, namespace, errResult := parseHelmReleaseParameters(parser)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		chart, err := parser.GetString("chart", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid chart parameter", err), nil
+		}
+
+		repo, err := parser.GetString("repo", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid repo parameter", err), nil
+		}
+
+		version, err := parser.GetString("version", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid version parameter", err), nil
+		}
+
+		values, err := parser.GetString("values", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid values parameter", err), nil
+		}
+		// The values are checked before the request so that a YAML mistake is not reported as a Helm failure
+		var decoded any
+		if err := yaml.Unmarshal([]byte(values), &decoded); err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid values parameter", err), nil
+		}
+		if _, isMapping := decoded.(map[string]any); decoded != nil && !isMapping {
+			return mcp.NewToolResultError("invalid values parameter: the values must be a YAML mapping"), nil
+		}
+
+		atomic, err := parser.GetBoolean("atomic", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid atomic parameter", err), nil
+		}
+
+		release, err := s.cli.InstallHelmChart(environmentId, models.HelmInstallOptions{
+			Name:      name,
+			Namespace: namespace,
+			Chart:     chart,
+			Repo:      repo,
+			Version:   version,
+			Values:    values,
+			Atomic:    atomic,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to deploy helm release", err), nil
+		}
+
+		action := "installed"
+		if release.Revision > 1 {
+			action = "upgraded"
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Helm release %s/%s %s with chart %s, revision %d is %s",
+			namespace, name, action, release.Chart, release.Revision, release.Status)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleUninstallHelmRelease() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, name, namespace, errResult := parseHelmReleaseParameters(parser)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		if err := s.cli.UninstallHelmRelease(environmentId, name, namespace); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to uninstall helm release", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Helm release %s/%s uninstalled", namespace, name)), nil
+	}
+}
+
+// parseHelmReleaseParameters parses the environmentId, name and namespace parameters that designate a Helm release
+func parseHelmReleaseParameters(parser *toolgen.ParameterParser) (int, string, string, *mcp.CallToolResult) {
+	environmentId, err := parser.GetInt("environmentId", true)
+	if err != nil {
+		return 0, "", "", mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err)
+	}
+
+	name, err := parser.GetString("name", true)
+	if err != nil {
+		return 0, "", "", mcp.NewToolResultErrorFromErr("invalid name parameter", err)
+	}
+
+	namespace, err := parser.GetString("namespace", true)
+	if err != nil {
+		return 0, "", "", mcp.NewToolResultErrorFromErr("invalid namespace parameter", err)
+	}
+
+	return environmentId, name, namespace, nil
+}
@@ -0,0 +1,332 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleListHelmReleases(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "releases of a namespace",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"outputFormat":  "csv",
+				"fields":        []any{"name", "revision", "chart", "status"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("GetHelmReleases", 1, "shop").Return([]models.HelmRelease{
+					{Name: "shop-db", Namespace: "shop", Revision: 3, Chart: "postgresql-15.5.0", Status: "deployed"},
+					{Name: "shop-cache", Namespace: "shop", Revision: 1, Chart: "redis-19.6.0", Status: "failed"},
+				}, nil)
+			},
+			expectedText: "name,revision,chart,status\n" +
+				"shop-db,3,postgresql-15.5.0,deployed\n" +
+				"shop-cache,1,redis-19.6.0,failed\n",
+		},
+		{
+			name: "list error",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("GetHelmReleases", 1, "").Return(nil, errors.New("environment is not a Kubernetes environment"))
+			},
+			expectedText:  "failed to get helm releases: environment is not a Kubernetes environment",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleListHelmReleases()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetHelmReleaseValues(t *testing.T) {
+	values := models.HelmReleaseValues{
+		UserSupplied: "auth:\n  database: shop\n",
+		Computed:     "auth:\n  database: shop\nprimary:\n  persistence:\n    size: 8Gi\n",
+	}
+
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "user supplied values",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("GetHelmReleaseValues", 1, "shop-db", "shop", 0).Return(values, nil)
+			},
+			expectedText: "auth:\n  database: shop\n",
+		},
+		{
+			name: "all values of a revision",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+				"revision":      float64(2),
+				"all":           true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("GetHelmReleaseValues", 1, "shop-db", "shop", 2).Return(values, nil)
+			},
+			expectedText: "auth:\n  database: shop\nprimary:\n  persistence:\n    size: 8Gi\n",
+		},
+		{
+			name: "no user supplied values",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("GetHelmReleaseValues", 1, "shop-db", "shop", 0).Return(models.HelmReleaseValues{UserSupplied: "{}\n"}, nil)
+			},
+			expectedText: "Helm release shop/shop-db has no user supplied values, set all to get the default values of the chart",
+		},
+		{
+			name: "missing namespace",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "invalid namespace parameter: namespace is required",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleGetHelmReleaseValues()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetHelmReleaseHistory(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetHelmReleaseHistory", 1, "shop-db", "shop").Return([]models.HelmRelease{
+		{Name: "shop-db", Namespace: "shop", Revision: 1, Chart: "postgresql-15.4.0", Status: "superseded", Description: "Install complete"},
+		{Name: "shop-db", Namespace: "shop", Revision: 2, Chart: "postgresql-15.5.0", Status: "deployed", Description: "Upgrade complete"},
+	}, nil)
+
+	server := &PortainerMCPServer{cli: mockClient}
+	result, err := server.HandleGetHelmReleaseHistory()(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"name":          "shop-db",
+		"namespace":     "shop",
+		"outputFormat":  "csv",
+		"fields":        []any{"revision", "chart", "status", "description"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "revision,chart,status,description\n"+
+		"1,postgresql-15.4.0,superseded,Install complete\n"+
+		"2,postgresql-15.5.0,deployed,Upgrade complete\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleInstallHelmChart(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "install chart",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+				"chart":         "postgresql",
+				"repo":          "https://charts.bitnami.com/bitnami",
+				"version":       "15.5.0",
+				"values":        "auth:\n  database: shop\n",
+				"atomic":        true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("InstallHelmChart", 1, models.HelmInstallOptions{
+					Name:      "shop-db",
+					Namespace: "shop",
+					Chart:     "postgresql",
+					Repo:      "https://charts.bitnami.com/bitnami",
+					Version:   "15.5.0",
+					Values:    "auth:\n  database: shop\n",
+					Atomic:    true,
+				}).Return(models.HelmRelease{Name: "shop-db", Namespace: "shop", Revision: 1, Chart: "postgresql-15.5.0", Status: "deployed"}, nil)
+			},
+			expectedText: "Helm release shop/shop-db installed with chart postgresql-15.5.0, revision 1 is deployed",
+		},
+		{
+			name: "upgrade release",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+				"chart":         "postgresql",
+				"repo":          "https://charts.bitnami.com/bitnami",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("InstallHelmChart", 1, models.HelmInstallOptions{
+					Name:      "shop-db",
+					Namespace: "shop",
+					Chart:     "postgresql",
+					Repo:      "https://charts.bitnami.com/bitnami",
+				}).Return(models.HelmRelease{Name: "shop-db", Namespace: "shop", Revision: 4, Chart: "postgresql-16.0.1", Status: "deployed"}, nil)
+			},
+			expectedText: "Helm release shop/shop-db upgraded with chart postgresql-16.0.1, revision 4 is deployed",
+		},
+		{
+			name: "values that are not a mapping",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+				"chart":         "postgresql",
+				"repo":          "https://charts.bitnami.com/bitnami",
+				"values":        "- auth\n- database\n",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "invalid values parameter: the values must be a YAML mapping",
+			expectedError: true,
+		},
+		{
+			name: "install error",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+				"chart":         "postgresql",
+				"repo":          "https://charts.example.com",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("InstallHelmChart", 1, models.HelmInstallOptions{
+					Name:      "shop-db",
+					Namespace: "shop",
+					Chart:     "postgresql",
+					Repo:      "https://charts.example.com",
+				}).Return(models.HelmRelease{}, errors.New("repository is not allowed"))
+			},
+			expectedText:  "failed to deploy helm release: repository is not allowed",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleInstallHelmChart()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleUninstallHelmRelease(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockError     error
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name:         "uninstall release",
+			expectedText: "Helm release shop/shop-db uninstalled",
+		},
+		{
+			name:          "uninstall error",
+			mockError:     errors.New("release: not found"),
+			expectedText:  "failed to uninstall helm release: release: not found",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			mockClient.On("UninstallHelmRelease", 1, "shop-db", "shop").Return(tt.mockError)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleUninstallHelmRelease()
+			result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+				"environmentId": float64(1),
+				"name":          "shop-db",
+				"namespace":     "shop",
+			}))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
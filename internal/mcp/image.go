@@ -0,0 +1,431 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// registryAuthHeader is the header used by Portainer to select the registry credentials
+	// injected in the requests sent to the Docker API
+	registryAuthHeader = "X-Registry-Auth"
+)
+
+// pullMessage is a message of the JSON stream returned by the Docker API when pulling an image
+type pullMessage struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+	ErrorDetail *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+// pullSummary is the outcome of an image pull, built from the JSON message stream
+type pullSummary struct {
+	status      string
+	digest      string
+	err         string
+	layers      []string
+	layerStatus map[string]string
+}
+
+func (s *PortainerMCPServer) AddImageFeatures() {
+	s.addToolIfExists(ToolListImages, s.HandleListImages())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolPullImage, s.HandlePullImage())
+		s.addToolIfExists(ToolPruneImages, s.HandlePruneImages())
+	}
+}
+
+func (s *PortainerMCPServer) HandleListImages() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		dangling, err := parser.GetBoolean("dangling", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid dangling parameter", err), nil
+		}
+
+		unused, err := parser.GetBoolean("unused", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid unused parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		var rawImages []image.Summary
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/images/json",
+			Method:        "GET",
+		}, &rawImages); result != nil || err != nil {
+			return result, err
+		}
+
+		var rawContainers []container.Summary
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/containers/json",
+			Method:        "GET",
+			QueryParams:   map[string]string{"all": "true"},
+		}, &rawContainers); result != nil || err != nil {
+			return result, err
+		}
+
+		usedBy := containerNamesBy(rawContainers, func(rawContainer container.Summary) []string {
+			return []string{rawContainer.ImageID}
+		})
+
+		images := make([]models.DockerImage, 0, len(rawImages))
+		for _, rawImage := range rawImages {
+			dockerImage := models.ConvertToDockerImage(rawImage, usedBy[rawImage.ID])
+			if dangling && len(dockerImage.Tags) > 0 {
+				continue
+			}
+			if unused && len(dockerImage.UsedBy) > 0 {
+				continue
+			}
+			images = append(images, dockerImage)
+		}
+
+		data, err := output.render(images)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render images", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandlePullImage() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		image, err := parser.GetString("image", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid image parameter", err), nil
+		}
+
+		platform, err := parser.GetString("platform", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid platform parameter", err), nil
+		}
+
+		registryId, err := parser.GetInt("registryId", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid registryId parameter", err), nil
+		}
+
+		name, tag := parseImageReference(image)
+
+		queryParams := map[string]string{
+			"fromImage": name,
+			"tag":       tag,
+		}
+		if platform != "" {
+			queryParams["platform"] = platform
+		}
+
+		opts := models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/images/create",
+			Method:        "POST",
+			QueryParams:   queryParams,
+		}
+
+		// Without a registry ID, the credentials of the Portainer registry that hosts the image are used.
+		// The registries cannot be listed by every user, the image is then pulled anonymously.
+		if registryId == 0 {
+			registries, err := s.cli.GetRegistries()
+			if err != nil {
+				log.Warn().Err(err).Str("image", image).Msg("failed to get registries, the image is pulled anonymously")
+			}
+
+			host := imageRegistryHost(name)
+			for _, registry := range registries {
+				if registry.Host() == host {
+					registryId = registry.ID
+					break
+				}
+			}
+		}
+
+		if registryId > 0 {
+			registryAuth, err := encodeRegistryAuth(registryId)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to encode registry authentication", err), nil
+			}
+			opts.Headers = map[string]string{registryAuthHeader: registryAuth}
+		}
+
+		response, err := s.cli.ProxyDockerRequest(opts)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		defer response.Body.Close()
+
+		if !isSuccessStatusCode(response.StatusCode) {
+			responseBody, err := io.ReadAll(response.Body)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to read Docker API response", err), nil
+			}
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		// The image is pulled while the stream is read, the progress is reported as it goes
+		summary, err := readPullStream(response.Body, s.newProgressReporter(ctx, request))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to read image pull progress", err), nil
+		}
+
+		reference := name + ":" + tag
+		if strings.HasPrefix(tag, "sha256:") {
+			reference = name + "@" + tag
+		}
+
+		if summary.err != "" {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to pull image %s: %s", reference, summary.err)), nil
+		}
+
+		return mcp.NewToolResultText(summary.format(reference)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandlePruneImages() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		all, err := parser.GetBoolean("all", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid all parameter", err), nil
+		}
+
+		confirm, err := parser.GetBoolean("confirm", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid confirm parameter", err), nil
+		}
+
+		if confirm {
+			opts := models.DockerProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          "/images/prune",
+				Method:        "POST",
+			}
+			if all {
+				// Only the dangling images are removed unless the dangling filter is false
+				opts.QueryParams = map[string]string{"filters": `{"dangling":["false"]}`}
+			}
+
+			var report image.PruneReport
+			if result, err := s.decodeDockerResponse(ctx, opts, &report); result != nil || err != nil {
+				return result, err
+			}
+
+			var deleted []string
+			for _, item := range report.ImagesDeleted {
+				if item.Deleted != "" {
+					deleted = append(deleted, models.ShortDockerID(item.Deleted))
+				}
+			}
+
+			return mcp.NewToolResultText(formatPruneReport("image", deleted, report.SpaceReclaimed)), nil
+		}
+
+		// The shared size and the number of containers of the images are only computed by the disk usage endpoint
+		var usage types.DiskUsage
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/system/df",
+			Method:        "GET",
+			QueryParams:   map[string]string{"type": "image"},
+		}, &usage); result != nil || err != nil {
+			return result, err
+		}
+
+		var candidates []pruneCandidate
+		for _, rawImage := range usage.Images {
+			tags := models.DockerImageTags(*rawImage)
+			if rawImage.Containers != 0 || (!all && len(tags) > 0) {
+				continue
+			}
+
+			candidate := pruneCandidate{name: models.ShortDockerID(rawImage.ID), size: -1}
+			if len(tags) > 0 {
+				candidate.name = strings.Join(tags, ", ")
+			}
+			// The layers shared with other images are only reclaimed when all these images are removed
+			if rawImage.SharedSize >= 0 {
+				candidate.size = rawImage.Size - rawImage.SharedSize
+			}
+			candidates = append(candidates, candidate)
+		}
+
+		return mcp.NewToolResultText(formatPrunePreview(ToolPruneImages, "image", candidates)), nil
+	}
+}
+
+// parseImageReference splits an image reference into the image name and the tag or digest expected by
+// the fromImage and tag query parameters. The tag defaults to latest.
+func parseImageReference(image string) (string, string) {
+	if name, digest, found := strings.Cut(image, "@"); found {
+		return name, digest
+	}
+
+	// A colon after the last slash separates the tag, a colon before is the port of the registry
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+
+	return image, "latest"
+}
+
+// imageRegistryHost returns the host of the registry of an image name. Like the Docker CLI, the first
+// component of the name is a registry host when it contains a dot or a port, or is localhost.
+func imageRegistryHost(name string) string {
+	host, _, found := strings.Cut(name, "/")
+	if !found || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return models.DockerHubRegistryHost
+	}
+	return host
+}
+
+// encodeRegistryAuth encodes the registry authentication header understood by the Portainer Docker proxy,
+// which replaces the registry ID by the credentials of the registry configured in Portainer
+func encodeRegistryAuth(registryId int) (string, error) {
+	data, err := json.Marshal(map[string]int{"registryId": registryId})
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// readPullStream reads the JSON message stream of an image pull until the end. The number of pulled
+// layers is reported as progress, each time a layer is complete.
+func readPullStream(stream io.Reader, progress *progressReporter) (*pullSummary, error) {
+	summary := &pullSummary{layerStatus: map[string]string{}}
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var message pullMessage
+		if err := decoder.Decode(&message); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if message.Error != "" || message.ErrorDetail != nil {
+			summary.err = message.Error
+			if summary.err == "" {
+				summary.err = message.ErrorDetail.Message
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(message.Status, "Digest: "):
+			summary.digest = strings.TrimPrefix(message.Status, "Digest: ")
+		case strings.HasPrefix(message.Status, "Status: "):
+			summary.status = strings.TrimPrefix(message.Status, "Status: ")
+		case message.ID != "" && isLayerStatus(message.Status):
+			if _, exists := summary.layerStatus[message.ID]; !exists {
+				summary.layers = append(summary.layers, message.ID)
+			}
+			summary.layerStatus[message.ID] = message.Status
+
+			if isLayerComplete(message.Status) {
+				completed := summary.completedLayers()
+				progress.report(float64(completed), float64(len(summary.layers)),
+					fmt.Sprintf("%s: %s (%d/%d layers)", message.ID, message.Status, completed, len(summary.layers)))
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// isLayerStatus checks if a status of the pull stream is about a layer, as opposed to the
+// "Pulling from" messages where the ID is the tag of the image
+func isLayerStatus(status string) bool {
+	switch status {
+	case "Pulling fs layer", "Waiting", "Downloading", "Verifying Checksum", "Download complete",
+		"Extracting", "Pull complete", "Already exists":
+		return true
+	}
+	return strings.HasPrefix(status, "Retrying in")
+}
+
+// isLayerComplete checks if a layer status is final
+func isLayerComplete(status string) bool {
+	return status == "Pull complete" || status == "Already exists"
+}
+
+// completedLayers returns the number of layers that are pulled or already present
+func (p *pullSummary) completedLayers() int {
+	count := 0
+	for _, status := range p.layerStatus {
+		if isLayerComplete(status) {
+			count++
+		}
+	}
+	return count
+}
+
+// format renders the summary of the image pull
+func (p *pullSummary) format(reference string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Image: %s\n", reference)
+	if p.status != "" {
+		fmt.Fprintf(&sb, "Status: %s\n", p.status)
+	}
+	if p.digest != "" {
+		fmt.Fprintf(&sb, "Digest: %s\n", p.digest)
+	}
+
+	if len(p.layers) > 0 {
+		existing := 0
+		for _, status := range p.layerStatus {
+			if status == "Already exists" {
+				existing++
+			}
+		}
+		fmt.Fprintf(&sb, "Layers: %d (%d pulled, %d already present)\n", len(p.layers), p.completedLayers()-existing, existing)
+	}
+
+	return sb.String()
+}
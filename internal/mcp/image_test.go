@@ -0,0 +1,368 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pullStream is the JSON message stream of a pull with one existing layer and one pulled layer
+var pullStream = strings.Join([]string{
+	`{"status":"Pulling from library/nginx","id":"1.27"}`,
+	`{"status":"Already exists","progressDetail":{},"id":"a2abf6c4d29d"}`,
+	`{"status":"Pulling fs layer","progressDetail":{},"id":"e8e2e5c2c0f4"}`,
+	`{"status":"Downloading","progressDetail":{"current":1024,"total":2048},"progress":"[=====>     ]","id":"e8e2e5c2c0f4"}`,
+	`{"status":"Download complete","progressDetail":{},"id":"e8e2e5c2c0f4"}`,
+	`{"status":"Extracting","progressDetail":{"current":2048,"total":2048},"id":"e8e2e5c2c0f4"}`,
+	`{"status":"Pull complete","progressDetail":{},"id":"e8e2e5c2c0f4"}`,
+	`{"status":"Digest: sha256:0a399eb16751829e1af26fea27b20c3ec28d7ab1fb72182879dcae1cca21206a"}`,
+	`{"status":"Status: Downloaded newer image for nginx:1.27"}`,
+}, "\r\n")
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image        string
+		expectedName string
+		expectedTag  string
+	}{
+		{image: "nginx", expectedName: "nginx", expectedTag: "latest"},
+		{image: "nginx:1.27", expectedName: "nginx", expectedTag: "1.27"},
+		{image: "registry.local:5000/team/app", expectedName: "registry.local:5000/team/app", expectedTag: "latest"},
+		{image: "registry.local:5000/team/app:v2", expectedName: "registry.local:5000/team/app", expectedTag: "v2"},
+		{image: "ghcr.io/org/app@sha256:0a39", expectedName: "ghcr.io/org/app", expectedTag: "sha256:0a39"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := parseImageReference(tt.image)
+			assert.Equal(t, tt.expectedName, name)
+			assert.Equal(t, tt.expectedTag, tag)
+		})
+	}
+}
+
+func TestImageRegistryHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "nginx", expected: "docker.io"},
+		{name: "bitnami/redis", expected: "docker.io"},
+		{name: "docker.io/library/nginx", expected: "docker.io"},
+		{name: "ghcr.io/org/app", expected: "ghcr.io"},
+		{name: "registry.local:5000/team/app", expected: "registry.local:5000"},
+		{name: "localhost/app", expected: "localhost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, imageRegistryHost(tt.name))
+		})
+	}
+}
+
+func TestHandlePullImage(t *testing.T) {
+	registries := []models.Registry{
+		{ID: 1, Name: "ghcr", URL: "https://ghcr.io"},
+		{ID: 2, Name: "local", URL: "registry.local:5000"},
+	}
+
+	tests := []struct {
+		name            string
+		input           map[string]any
+		registries      []models.Registry
+		registriesError error
+		expectedQuery   map[string]string
+		expectedHeaders map[string]string
+		response        *http.Response
+		expectedText    string
+		expectedError   bool
+	}{
+		{
+			name: "successful pull",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"image":         "nginx:1.27",
+			},
+			registries:    registries,
+			expectedQuery: map[string]string{"fromImage": "nginx", "tag": "1.27"},
+			response:      createMockHttpResponse(http.StatusOK, pullStream),
+			expectedText: "Image: nginx:1.27\n" +
+				"Status: Downloaded newer image for nginx:1.27\n" +
+				"Digest: sha256:0a399eb16751829e1af26fea27b20c3ec28d7ab1fb72182879dcae1cca21206a\n" +
+				"Layers: 2 (1 pulled, 1 already present)\n",
+		},
+		{
+			name: "pull with registry credentials and platform",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"image":         "registry.local:5000/team/app",
+				"platform":      "linux/arm64",
+				"registryId":    float64(3),
+			},
+			expectedQuery:   map[string]string{"fromImage": "registry.local:5000/team/app", "tag": "latest", "platform": "linux/arm64"},
+			expectedHeaders: map[string]string{"X-Registry-Auth": "eyJyZWdpc3RyeUlkIjozfQ=="},
+			response:        createMockHttpResponse(http.StatusOK, `{"status":"Status: Image is up to date for registry.local:5000/team/app:latest"}`),
+			expectedText: "Image: registry.local:5000/team/app:latest\n" +
+				"Status: Image is up to date for registry.local:5000/team/app:latest\n",
+		},
+		{
+			name: "registry resolved from the image host",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"image":         "ghcr.io/org/app:v1",
+			},
+			registries:      registries,
+			expectedQuery:   map[string]string{"fromImage": "ghcr.io/org/app", "tag": "v1"},
+			expectedHeaders: map[string]string{"X-Registry-Auth": "eyJyZWdpc3RyeUlkIjoxfQ=="},
+			response:        createMockHttpResponse(http.StatusOK, `{"status":"Status: Image is up to date for ghcr.io/org/app:v1"}`),
+			expectedText: "Image: ghcr.io/org/app:v1\n" +
+				"Status: Image is up to date for ghcr.io/org/app:v1\n",
+		},
+		{
+			name: "public image pulled anonymously when the registries cannot be listed",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"image":         "nginx:1.27",
+			},
+			registriesError: errors.New("access denied"),
+			expectedQuery:   map[string]string{"fromImage": "nginx", "tag": "1.27"},
+			response:        createMockHttpResponse(http.StatusOK, pullStream),
+			expectedText: "Image: nginx:1.27\n" +
+				"Status: Downloaded newer image for nginx:1.27\n" +
+				"Digest: sha256:0a399eb16751829e1af26fea27b20c3ec28d7ab1fb72182879dcae1cca21206a\n" +
+				"Layers: 2 (1 pulled, 1 already present)\n",
+		},
+		{
+			name: "error in the stream",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"image":         "nginx:missing",
+			},
+			registries:    registries,
+			expectedQuery: map[string]string{"fromImage": "nginx", "tag": "missing"},
+			response:      createMockHttpResponse(http.StatusOK, `{"status":"Pulling from library/nginx","id":"missing"}`+"\n"+`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`),
+			expectedText:  "failed to pull image nginx:missing: manifest unknown",
+			expectedError: true,
+		},
+		{
+			name: "Docker API error",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"image":         "private/app",
+			},
+			registries:    registries,
+			expectedQuery: map[string]string{"fromImage": "private/app", "tag": "latest"},
+			response:      createMockHttpResponse(http.StatusNotFound, `{"message":"pull access denied for private/app"}`),
+			expectedText:  "HTTP 404 Not Found\nError: pull access denied for private/app",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if tt.registries != nil || tt.registriesError != nil {
+				mockClient.On("GetRegistries").Return(tt.registries, tt.registriesError)
+			}
+			mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+				return opts.Method == "POST" &&
+					opts.Path == "/images/create" &&
+					assert.ObjectsAreEqual(tt.expectedQuery, opts.QueryParams) &&
+					assert.ObjectsAreEqual(tt.expectedHeaders, opts.Headers)
+			})).Return(tt.response, nil)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandlePullImage()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			if tt.expectedError {
+				assert.Contains(t, resultTexts(t, result)[0], tt.expectedText)
+			} else {
+				assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlePullImage_Progress(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetRegistries").Return([]models.Registry{}, nil)
+	mockClient.On("ProxyDockerRequest", mock.AnythingOfType("models.DockerProxyRequestOptions")).
+		Return(createMockHttpResponse(http.StatusOK, pullStream), nil)
+
+	srv := server.NewMCPServer("test", "1.0.0")
+	session := newFakeLoggingSession("session", mcp.LoggingLevelInfo)
+	ctx := srv.WithContext(context.Background(), session)
+
+	s := &PortainerMCPServer{
+		srv: srv,
+		cli: mockClient,
+	}
+
+	request := CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"image":         "nginx:1.27",
+	})
+	request.Params.Meta = &mcp.Meta{ProgressToken: "pull-1"}
+
+	result, err := s.HandlePullImage()(ctx, request)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	notifications := session.received()
+	require.Len(t, notifications, 2)
+
+	assert.Equal(t, "notifications/progress", notifications[0].Method)
+	assert.Equal(t, map[string]any{
+		"progressToken": "pull-1",
+		"progress":      float64(1),
+		"total":         float64(1),
+		"message":       "a2abf6c4d29d: Already exists (1/1 layers)",
+	}, notifications[0].Params.AdditionalFields)
+	assert.Equal(t, map[string]any{
+		"progressToken": "pull-1",
+		"progress":      float64(2),
+		"total":         float64(2),
+		"message":       "e8e2e5c2c0f4: Pull complete (2/2 layers)",
+	}, notifications[1].Params.AdditionalFields)
+}
+
+func TestHandleListImages(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/images/json"
+	})).Return(createMockHttpResponse(http.StatusOK, `[
+		{"Id":"sha256:aaaaaaaaaaaaaaaa","RepoTags":["nginx:1.27"],"Size":192000000},
+		{"Id":"sha256:bbbbbbbbbbbbbbbb","RepoTags":["<none>:<none>"],"Size":5500000},
+		{"Id":"sha256:cccccccccccccccc","RepoTags":["postgres:17"],"Size":438000000}
+	]`), nil)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/containers/json" &&
+			assert.ObjectsAreEqual(map[string]string{"all": "true"}, opts.QueryParams)
+	})).Return(createMockHttpResponse(http.StatusOK, `[
+		{"Id":"c2","Names":["/web-2"],"ImageID":"sha256:aaaaaaaaaaaaaaaa"},
+		{"Id":"c1","Names":["/web-1"],"ImageID":"sha256:aaaaaaaaaaaaaaaa"}
+	]`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleListImages()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"outputFormat":  "csv",
+		"fields":        []any{"id", "tags", "size", "used_by"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "id,tags,size,used_by\n"+
+		`aaaaaaaaaaaa,"[""nginx:1.27""]",192MB,"[""web-1"",""web-2""]"`+"\n"+
+		"bbbbbbbbbbbb,[],5.5MB,[]\n"+
+		`cccccccccccc,"[""postgres:17""]",438MB,[]`+"\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandlePruneImages(t *testing.T) {
+	diskUsage := `{"Images":[
+		{"Id":"sha256:aaaaaaaaaaaaaaaa","RepoTags":["nginx:1.27"],"Size":192000000,"SharedSize":0,"Containers":1},
+		{"Id":"sha256:bbbbbbbbbbbbbbbb","RepoTags":["<none>:<none>"],"Size":5500000,"SharedSize":500000,"Containers":0},
+		{"Id":"sha256:cccccccccccccccc","RepoTags":["postgres:17"],"Size":438000000,"SharedSize":0,"Containers":0}
+	]}`
+
+	tests := []struct {
+		name         string
+		input        map[string]any
+		setupMock    func(mockClient *MockPortainerClient)
+		expectedText string
+	}{
+		{
+			name: "dry run of the dangling images",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/system/df"
+				})).Return(createMockHttpResponse(http.StatusOK, diskUsage), nil)
+			},
+			expectedText: "Dry run: 1 image would be removed, reclaiming 5MB. Nothing was removed, " +
+				"call pruneImages again with confirm set to true to remove them.\n\n" +
+				"- bbbbbbbbbbbb (5MB)\n",
+		},
+		{
+			name: "dry run of all the unused images",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"all":           true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/system/df"
+				})).Return(createMockHttpResponse(http.StatusOK, diskUsage), nil)
+			},
+			expectedText: "Dry run: 2 images would be removed, reclaiming 443MB. Nothing was removed, " +
+				"call pruneImages again with confirm set to true to remove them.\n\n" +
+				"- bbbbbbbbbbbb (5MB)\n" +
+				"- postgres:17 (438MB)\n",
+		},
+		{
+			name: "confirmed prune of all the unused images",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"all":           true,
+				"confirm":       true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "POST" &&
+						opts.Path == "/images/prune" &&
+						assert.ObjectsAreEqual(map[string]string{"filters": `{"dangling":["false"]}`}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK, `{"ImagesDeleted":[
+					{"Untagged":"postgres:17"},
+					{"Deleted":"sha256:cccccccccccccccc"}
+				],"SpaceReclaimed":438000000}`), nil)
+			},
+			expectedText: "Removed 1 image, reclaimed 438MB.\n\n- cccccccccccc\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandlePruneImages()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.False(t, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
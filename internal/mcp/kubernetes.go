@@ -2,8 +2,12 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -13,6 +17,43 @@ import (
 	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
+// Kubernetes patch types supported by the kubernetesProxy tool
+const (
+	// PatchTypeJSON is a JSON patch (RFC 6902)
+	PatchTypeJSON = "json"
+	// PatchTypeMerge is a JSON merge patch (RFC 7386)
+	PatchTypeMerge = "merge"
+	// PatchTypeStrategicMerge is a Kubernetes strategic merge patch, only supported by the built-in resources
+	PatchTypeStrategicMerge = "strategic-merge"
+	// PatchTypeApply is a server-side apply patch
+	PatchTypeApply = "apply"
+)
+
+// patchContentTypes maps the patch types to the Content-Type expected by the Kubernetes API
+var patchContentTypes = map[string]string{
+	PatchTypeJSON:           "application/json-patch+json",
+	PatchTypeMerge:          "application/merge-patch+json",
+	PatchTypeStrategicMerge: "application/strategic-merge-patch+json",
+	PatchTypeApply:          "application/apply-patch+yaml",
+}
+
+const (
+	// defaultFieldManager is the field manager used for server-side apply when none is specified
+	defaultFieldManager = "portainer-mcp"
+	// defaultKubernetesNamespace is the namespace used by the Kubernetes tools when no namespace is specified
+	defaultKubernetesNamespace = "default"
+)
+
+// Views supported by the getKubernetesResourceStripped tool
+const (
+	// ViewObject returns the resources as JSON objects
+	ViewObject = "object"
+	// ViewTable returns the resources as a text table with the same columns as kubectl get
+	ViewTable = "table"
+	// ViewWide returns the resources as a text table with the same columns as kubectl get -o wide
+	ViewWide = "wide"
+)
+
 func (s *PortainerMCPServer) AddKubernetesProxyFeatures() {
 	s.addToolIfExists(ToolKubernetesProxyStripped, s.HandleKubernetesProxyStripped())
 
@@ -21,6 +62,20 @@ func (s *PortainerMCPServer) AddKubernetesProxyFeatures() {
 	}
 }
 
+func (s *PortainerMCPServer) AddKubernetesFeatures() {
+	s.addToolIfExists(ToolGetPodLogs, s.HandleGetPodLogs())
+	s.addToolIfExists(ToolDescribeKubernetesResource, s.HandleDescribeKubernetesResource())
+	s.addToolIfExists(ToolGetKubernetesEvents, s.HandleGetKubernetesEvents())
+	s.addToolIfExists(ToolGetRolloutStatus, s.HandleGetRolloutStatus())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolApplyKubernetesManifest, s.HandleApplyKubernetesManifest())
+		s.addToolIfExists(ToolScaleWorkload, s.HandleScaleWorkload())
+		s.addToolIfExists(ToolRestartWorkload, s.HandleRestartWorkload())
+		s.addToolIfExists(ToolUndoRollout, s.HandleUndoRollout())
+	}
+}
+
 func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		parser := toolgen.NewParameterParser(request)
@@ -56,6 +111,48 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 			return mcp.NewToolResultErrorFromErr("invalid headers", err), nil
 		}
 
+		profileName, err := parser.GetString("profile", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid profile parameter", err), nil
+		}
+		profile, err := s.strippingProfile(profileName)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid profile parameter", err), nil
+		}
+
+		filterExpression, err := parser.GetString("filter", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+		}
+		filter, err := compileFilter(filterExpression)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+		}
+
+		view, err := parser.GetString("view", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid view parameter", err), nil
+		}
+		switch view {
+		case "", ViewObject:
+			view = ViewObject
+		case ViewTable, ViewWide:
+			if profileName != "" || filter != nil {
+				return mcp.NewToolResultError("profile and filter can only be used with the object view"), nil
+			}
+
+			// The Table format is requested through the Accept header, any Accept header provided by the user is replaced
+			for key := range headersMap {
+				if strings.EqualFold(key, "Accept") {
+					delete(headersMap, key)
+				}
+			}
+			headersMap["Accept"] = k8sutil.TableAcceptHeader
+			queryParamsMap["includeObject"] = "Metadata"
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("invalid view: %s", view)), nil
+		}
+
 		opts := models.KubernetesProxyRequestOptions{
 			EnvironmentID: environmentId,
 			Path:          kubernetesAPIPath,
@@ -68,13 +165,30 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
 		}
+		defer response.Body.Close()
+		s.limitResponseBody(response)
 
-		responseBody, err := k8sutil.ProcessRawKubernetesAPIResponse(response)
+		var responseBody []byte
+		if isSuccessStatusCode(response.StatusCode) && view != ViewObject {
+			responseBody, err = readKubernetesTable(response, view == ViewWide)
+		} else if isSuccessStatusCode(response.StatusCode) {
+			responseBody, err = k8sutil.ProcessRawKubernetesAPIResponseWithProfile(response, profile)
+		} else {
+			// Error responses are Status objects, there is nothing to strip
+			responseBody, err = io.ReadAll(response.Body)
+		}
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to process Kubernetes API response", err), nil
 		}
 
-		return mcp.NewToolResultText(string(responseBody)), nil
+		if filter != nil && isSuccessStatusCode(response.StatusCode) {
+			responseBody, err = applyFilter(ctx, filter, responseBody)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to filter Kubernetes API response", err), nil
+			}
+		}
+
+		return s.proxyResult(ctx, response, responseBody), nil
 	}
 }
 
@@ -91,7 +205,7 @@ func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid method parameter", err), nil
 		}
-		if !isValidHTTPMethod(method) {
+		if !isValidKubernetesHTTPMethod(method) {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid method: %s", method)), nil
 		}
 
@@ -126,6 +240,56 @@ func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("invalid body parameter", err), nil
 		}
 
+		patchType, err := parser.GetString("patchType", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid patchType parameter", err), nil
+		}
+
+		fieldManager, err := parser.GetString("fieldManager", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid fieldManager parameter", err), nil
+		}
+
+		force, err := parser.GetBoolean("force", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid force parameter", err), nil
+		}
+
+		if method == "PATCH" {
+			if patchType == "" {
+				patchType = PatchTypeMerge
+			}
+
+			contentType, ok := patchContentTypes[patchType]
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid patchType: %s", patchType)), nil
+			}
+			// The patch type takes precedence over any Content-Type header provided by the user
+			for key := range headersMap {
+				if strings.EqualFold(key, "Content-Type") {
+					delete(headersMap, key)
+				}
+			}
+			headersMap["Content-Type"] = contentType
+
+			if patchType == PatchTypeApply && fieldManager == "" {
+				fieldManager = defaultFieldManager
+			}
+		} else if patchType != "" {
+			return mcp.NewToolResultError("patchType can only be used with the PATCH method"), nil
+		}
+
+		if force && patchType != PatchTypeApply {
+			return mcp.NewToolResultError("force can only be used with the apply patchType"), nil
+		}
+
+		if fieldManager != "" {
+			queryParamsMap["fieldManager"] = fieldManager
+		}
+		if force {
+			queryParamsMap["force"] = "true"
+		}
+
 		opts := models.KubernetesProxyRequestOptions{
 			EnvironmentID: environmentId,
 			Path:          kubernetesAPIPath,
@@ -142,12 +306,85 @@ func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
 		}
+		defer response.Body.Close()
+		s.limitResponseBody(response)
 
 		responseBody, err := io.ReadAll(response.Body)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to read Kubernetes API response", err), nil
 		}
 
-		return mcp.NewToolResultText(string(responseBody)), nil
+		return s.proxyResult(ctx, response, responseBody), nil
+	}
+}
+
+// sendKubernetesRequest sends a request to the Kubernetes API through the Portainer proxy and reads the response body
+func (s *PortainerMCPServer) sendKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, []byte, error) {
+	response, err := s.cli.ProxyKubernetesRequest(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	s.limitResponseBody(response)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Kubernetes API response: %w", err)
+	}
+
+	return response, body, nil
+}
+
+// decodeKubernetesResponse sends a request to the Kubernetes API and decodes the JSON response into target.
+// A tool result is returned when the request is not successful, to be returned as is by the handler.
+func (s *PortainerMCPServer) decodeKubernetesResponse(ctx context.Context, opts models.KubernetesProxyRequestOptions, target any) (*mcp.CallToolResult, error) {
+	response, responseBody, err := s.sendKubernetesRequest(opts)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
 	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return s.proxyResult(ctx, response, responseBody), nil
+	}
+
+	if err := json.Unmarshal(responseBody, target); err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to decode Kubernetes API response", err), nil
+	}
+
+	return nil, nil
+}
+
+// strippingProfile returns the stripping profile with the given name, or the minimal profile if the name is empty
+func (s *PortainerMCPServer) strippingProfile(name string) (k8sutil.StrippingProfile, error) {
+	if name == "" {
+		name = k8sutil.ProfileMinimal
+	}
+
+	profiles := s.profiles
+	if profiles == nil {
+		profiles = k8sutil.BuiltinStrippingProfiles()
+	}
+
+	profile, exists := profiles[name]
+	if !exists {
+		return k8sutil.StrippingProfile{}, fmt.Errorf("unknown stripping profile: %s, available profiles are: %s", name, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+	}
+
+	return profile, nil
+}
+
+// readKubernetesTable reads a Table response and renders it as a text table
+func readKubernetesTable(response *http.Response, wide bool) ([]byte, error) {
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	table, err := k8sutil.FormatTable(data, wide)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(table), nil
 }
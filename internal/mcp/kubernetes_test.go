@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/internal/k8sutil"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -35,6 +37,37 @@ func TestHandleKubernetesProxy_ParameterValidation(t *testing.T) {
 			},
 			expectedErrorMsg: "environmentId is required",
 		},
+		{
+			name: "invalid patchType",
+			inputParams: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PATCH",
+				"patchType":         "replace",
+			},
+			expectedErrorMsg: "invalid patchType: replace",
+		},
+		{
+			name: "patchType with non-PATCH method",
+			inputParams: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PUT",
+				"patchType":         "merge",
+			},
+			expectedErrorMsg: "patchType can only be used with the PATCH method",
+		},
+		{
+			name: "force without apply patchType",
+			inputParams: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PATCH",
+				"patchType":         "strategic-merge",
+				"force":             true,
+			},
+			expectedErrorMsg: "force can only be used with the apply patchType",
+		},
 		{
 			name: "missing kubernetesAPIPath",
 			inputParams: map[string]any{
@@ -269,10 +302,8 @@ func TestHandleKubernetesProxy_ClientInteraction(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				assert.Len(t, result.Content, 1)
-				textContent, ok := result.Content[0].(mcp.TextContent)
-				assert.True(t, ok)
-				assert.Equal(t, tc.expect.resultText, textContent.Text)
+				assert.False(t, result.IsError)
+				assert.Equal(t, tc.expect.resultText, proxyResultBody(t, result))
 			}
 
 			mockClient.AssertExpectations(t)
@@ -592,13 +623,12 @@ func TestHandleKubernetesProxyStripped_ClientInteraction(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				assert.Len(t, result.Content, 1)
-				textContent, ok := result.Content[0].(mcp.TextContent)
-				assert.True(t, ok)
+				assert.False(t, result.IsError)
+				body := proxyResultBody(t, result)
 				if tc.expect.resultText == "" {
-					assert.Equal(t, tc.expect.resultText, textContent.Text)
+					assert.Equal(t, tc.expect.resultText, body)
 				} else {
-					assert.JSONEq(t, tc.expect.resultText, textContent.Text)
+					assert.JSONEq(t, tc.expect.resultText, body)
 				}
 			}
 
@@ -606,3 +636,274 @@ func TestHandleKubernetesProxyStripped_ClientInteraction(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleKubernetesProxyStripped_ErrorStatus(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyKubernetesRequest", mock.AnythingOfType("models.KubernetesProxyRequestOptions")).
+		Return(createMockHttpResponse(http.StatusNotFound, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"pods \"web\" not found","reason":"NotFound","code":404}`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	request := CreateMCPRequest(map[string]any{
+		"environmentId":     float64(1),
+		"kubernetesAPIPath": "/api/v1/namespaces/default/pods/web",
+	})
+	handler := server.HandleKubernetesProxyStripped()
+	result, err := handler(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsError, "result.IsError should be true for non-2xx responses")
+	texts := resultTexts(t, result)
+	assert.Equal(t, "HTTP 404 Not Found\nError: pods \"web\" not found", texts[0])
+	assert.Contains(t, proxyResultBody(t, result), `"reason":"NotFound"`)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleKubernetesProxy_Patch(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           map[string]any
+		expectedHeaders map[string]string
+		expectedQuery   map[string]string
+	}{
+		{
+			name: "default merge patch",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PATCH",
+				"body":              `{"spec":{"replicas":3}}`,
+			},
+			expectedHeaders: map[string]string{"Content-Type": "application/merge-patch+json"},
+			expectedQuery:   map[string]string{},
+		},
+		{
+			name: "json patch overrides the Content-Type header",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PATCH",
+				"patchType":         "json",
+				"body":              `[{"op":"replace","path":"/spec/replicas","value":3}]`,
+				"headers": []any{
+					map[string]any{"key": "content-type", "value": "application/json"},
+				},
+			},
+			expectedHeaders: map[string]string{"Content-Type": "application/json-patch+json"},
+			expectedQuery:   map[string]string{},
+		},
+		{
+			name: "server-side apply with default field manager and force",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PATCH",
+				"patchType":         "apply",
+				"force":             true,
+				"body":              `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":3}}`,
+			},
+			expectedHeaders: map[string]string{"Content-Type": "application/apply-patch+yaml"},
+			expectedQuery:   map[string]string{"fieldManager": "portainer-mcp", "force": "true"},
+		},
+		{
+			name: "strategic merge patch with field manager",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/apis/apps/v1/namespaces/default/deployments/web",
+				"method":            "PATCH",
+				"patchType":         "strategic-merge",
+				"fieldManager":      "assistant",
+				"body":              `{"spec":{"replicas":3}}`,
+			},
+			expectedHeaders: map[string]string{"Content-Type": "application/strategic-merge-patch+json"},
+			expectedQuery:   map[string]string{"fieldManager": "assistant"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+				return opts.Method == "PATCH" &&
+					assert.ObjectsAreEqual(tt.expectedHeaders, opts.Headers) &&
+					assert.ObjectsAreEqual(tt.expectedQuery, opts.QueryParams)
+			})).Return(createMockHttpResponse(http.StatusOK, `{"kind":"Deployment"}`), nil)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleKubernetesProxy()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.False(t, result.IsError)
+			assert.Equal(t, `{"kind":"Deployment"}`, proxyResultBody(t, result))
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleKubernetesProxyStripped_Profile(t *testing.T) {
+	body := `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","uid":"1","resourceVersion":"2","managedFields":[]},"spec":{"nodeName":"node-1","restartPolicy":"Always"},"status":{"phase":"Running"}}`
+
+	tests := []struct {
+		name          string
+		profile       any
+		profiles      map[string]k8sutil.StrippingProfile
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name:         "default profile",
+			expectedText: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","uid":"1","resourceVersion":"2"},"spec":{"nodeName":"node-1","restartPolicy":"Always"},"status":{"phase":"Running"}}`,
+		},
+		{
+			name: "default profile redefined by the custom profiles",
+			profiles: map[string]k8sutil.StrippingProfile{
+				k8sutil.ProfileMinimal: {Name: k8sutil.ProfileMinimal, RemoveMetadataFields: []string{"uid"}},
+			},
+			expectedText: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","resourceVersion":"2"},"spec":{"nodeName":"node-1","restartPolicy":"Always"},"status":{"phase":"Running"}}`,
+		},
+		{
+			name:         "standard profile",
+			profile:      "standard",
+			expectedText: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web"},"spec":{"nodeName":"node-1","restartPolicy":"Always"},"status":{"phase":"Running"}}`,
+		},
+		{
+			name:         "summary profile",
+			profile:      "summary",
+			expectedText: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web"},"spec":{"nodeName":"node-1"},"status":{"phase":"Running"}}`,
+		},
+		{
+			name:          "unknown profile",
+			profile:       "verbose",
+			expectedText:  "unknown stripping profile: verbose, available profiles are: minimal, standard, summary",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if !tt.expectedError {
+				mockClient.On("ProxyKubernetesRequest", mock.AnythingOfType("models.KubernetesProxyRequestOptions")).
+					Return(createMockHttpResponse(http.StatusOK, body), nil)
+			}
+
+			server := &PortainerMCPServer{
+				cli:      mockClient,
+				profiles: tt.profiles,
+			}
+
+			input := map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/namespaces/default/pods/web",
+			}
+			if tt.profile != nil {
+				input["profile"] = tt.profile
+			}
+
+			handler := server.HandleKubernetesProxyStripped()
+			result, err := handler(context.Background(), CreateMCPRequest(input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			if tt.expectedError {
+				assert.Contains(t, resultTexts(t, result)[0], tt.expectedText)
+			} else {
+				assert.JSONEq(t, tt.expectedText, proxyResultBody(t, result))
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleKubernetesProxyStripped_TableView(t *testing.T) {
+	table := `{"kind":"Table","columnDefinitions":[{"name":"Name","priority":0},{"name":"Status","priority":0},{"name":"IP","priority":1}],"rows":[{"cells":["web","Running","10.0.0.12"],"object":{"metadata":{"namespace":"default"}}}]}`
+
+	tests := []struct {
+		name           string
+		input          map[string]any
+		expectedText   string
+		expectedError  bool
+		skipClientCall bool
+	}{
+		{
+			name: "table view",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/namespaces/default/pods",
+				"view":              "table",
+				"headers":           []any{map[string]any{"key": "accept", "value": "application/yaml"}},
+			},
+			expectedText: "NAME   STATUS\nweb    Running\n",
+		},
+		{
+			name: "wide view",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/namespaces/default/pods",
+				"view":              "wide",
+			},
+			expectedText: "NAME   STATUS    IP\nweb    Running   10.0.0.12\n",
+		},
+		{
+			name: "table view with profile",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/namespaces/default/pods",
+				"view":              "table",
+				"profile":           "summary",
+			},
+			expectedText:   "profile and filter can only be used with the object view",
+			expectedError:  true,
+			skipClientCall: true,
+		},
+		{
+			name: "invalid view",
+			input: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/namespaces/default/pods",
+				"view":              "yaml",
+			},
+			expectedText:   "invalid view: yaml",
+			expectedError:  true,
+			skipClientCall: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if !tt.skipClientCall {
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return assert.ObjectsAreEqual(map[string]string{"Accept": k8sutil.TableAcceptHeader}, opts.Headers) &&
+						opts.QueryParams["includeObject"] == "Metadata"
+				})).Return(createMockHttpResponse(http.StatusOK, table), nil)
+			}
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleKubernetesProxyStripped()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			if tt.expectedError {
+				assert.Contains(t, resultTexts(t, result)[0], tt.expectedText)
+			} else {
+				assert.Equal(t, tt.expectedText, proxyResultBody(t, result))
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
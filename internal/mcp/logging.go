@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// LoggerName is the logger name attached to the log notifications sent to MCP clients
+	LoggerName = "portainer-mcp"
+	// defaultLogBacklogSize is the number of log entries kept to be replayed to clients
+	// when they set their log level
+	defaultLogBacklogSize = 100
+	// LogSessionField is the log field scoping an entry to the MCP session that produced it
+	LogSessionField = "session"
+)
+
+// mcpLogLevels lists the MCP logging levels by increasing severity
+var mcpLogLevels = []mcp.LoggingLevel{
+	mcp.LoggingLevelDebug,
+	mcp.LoggingLevelInfo,
+	mcp.LoggingLevelNotice,
+	mcp.LoggingLevelWarning,
+	mcp.LoggingLevelError,
+	mcp.LoggingLevelCritical,
+	mcp.LoggingLevelAlert,
+	mcp.LoggingLevelEmergency,
+}
+
+// logEntry is a log entry waiting to be replayed to MCP clients
+type logEntry struct {
+	level mcp.LoggingLevel
+	data  any
+	// seq is the position of the entry in the sequence of the entries written to the forwarder
+	seq uint64
+	// sessionID is the session the entry is scoped to, the entries of the server itself have none
+	sessionID string
+}
+
+// visibleTo checks if an entry can be sent to a session
+func (e logEntry) visibleTo(sessionID string) bool {
+	return e.sessionID == "" || e.sessionID == sessionID
+}
+
+// LogForwarder is a zerolog writer that forwards log entries to the connected MCP clients
+// as notifications/message. Each client only receives the entries at or above the level
+// it selected via logging/setLevel. The entries with a LogSessionField field are only sent to
+// that session, the other entries are sent to every session.
+//
+// Entries written before a client is connected (e.g. while the server starts) are kept in
+// a bounded backlog and replayed to a client the first time it sets its log level. Entries
+// written after a client is connected but before it is initialized are queued and sent once
+// it is initialized.
+type LogForwarder struct {
+	mu       sync.Mutex
+	sessions map[string]server.SessionWithLogging
+	// pendingReplays holds the sequence number of the last entry written before each session
+	// registered, until the backlog is replayed to the session
+	pendingReplays map[string]uint64
+	// queued holds the entries written while each session was not initialized yet
+	queued      map[string][]logEntry
+	seq         uint64
+	backlog     []logEntry
+	backlogSize int
+}
+
+// NewLogForwarder creates a new LogForwarder.
+// It must be registered on the server with WithLogForwarder and used as a zerolog output.
+func NewLogForwarder() *LogForwarder {
+	return &LogForwarder{
+		sessions:       map[string]server.SessionWithLogging{},
+		pendingReplays: map[string]uint64{},
+		queued:         map[string][]logEntry{},
+		backlogSize:    defaultLogBacklogSize,
+	}
+}
+
+// Write implements io.Writer. Entries without level information are forwarded as info.
+func (f *LogForwarder) Write(p []byte) (int, error) {
+	return f.WriteLevel(zerolog.InfoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter
+func (f *LogForwarder) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level == zerolog.NoLevel || level == zerolog.Disabled {
+		return len(p), nil
+	}
+
+	entry := logEntry{level: convertLogLevel(level)}
+	fields := map[string]any{}
+	if err := json.Unmarshal(p, &fields); err == nil {
+		entry.sessionID, _ = fields[LogSessionField].(string)
+		delete(fields, zerolog.LevelFieldName)
+		delete(fields, LogSessionField)
+		entry.data = fields
+	} else {
+		entry.data = string(p)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	entry.seq = f.seq
+	f.backlog = append(f.backlog, entry)
+	if len(f.backlog) > f.backlogSize {
+		f.backlog = f.backlog[len(f.backlog)-f.backlogSize:]
+	}
+
+	for sessionID, session := range f.sessions {
+		if !entry.visibleTo(sessionID) {
+			continue
+		}
+		if !session.Initialized() {
+			f.queue(sessionID, entry)
+			continue
+		}
+		f.flushQueued(sessionID, session)
+		sendLogNotification(session, entry)
+	}
+
+	return len(p), nil
+}
+
+// queue keeps an entry to be sent to a session once it is initialized. Only the last
+// entries are kept, like in the backlog.
+// It must be called with the lock held.
+func (f *LogForwarder) queue(sessionID string, entry logEntry) {
+	queued := append(f.queued[sessionID], entry)
+	if len(queued) > f.backlogSize {
+		queued = queued[len(queued)-f.backlogSize:]
+	}
+	f.queued[sessionID] = queued
+}
+
+// flushQueued sends the entries queued for a session, if the session is initialized.
+// It must be called with the lock held.
+func (f *LogForwarder) flushQueued(sessionID string, session server.SessionWithLogging) {
+	if !session.Initialized() {
+		return
+	}
+	for _, entry := range f.queued[sessionID] {
+		sendLogNotification(session, entry)
+	}
+	delete(f.queued, sessionID)
+}
+
+// registerHooks registers the session tracking and backlog replay hooks
+func (f *LogForwarder) registerHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		if sessionWithLogging, ok := session.(server.SessionWithLogging); ok {
+			f.mu.Lock()
+			f.sessions[session.SessionID()] = sessionWithLogging
+			f.pendingReplays[session.SessionID()] = f.seq
+			f.mu.Unlock()
+		}
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		f.mu.Lock()
+		delete(f.sessions, session.SessionID())
+		delete(f.pendingReplays, session.SessionID())
+		delete(f.queued, session.SessionID())
+		f.mu.Unlock()
+	})
+
+	// The requests following the initialization deliver the entries queued until then,
+	// without waiting for a new entry to be written
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		sessionWithLogging, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+		if !ok {
+			return
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if _, registered := f.sessions[sessionWithLogging.SessionID()]; registered {
+			f.flushQueued(sessionWithLogging.SessionID(), sessionWithLogging)
+		}
+	})
+
+	hooks.AddAfterSetLevel(func(ctx context.Context, id any, message *mcp.SetLevelRequest, result *mcp.EmptyResult) {
+		sessionWithLogging, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+		if !ok {
+			return
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		// Only the entries written before the session registered are replayed, the later ones
+		// were sent to the session as they were written or are queued until it is initialized
+		sessionID := sessionWithLogging.SessionID()
+		if lastSeq, pending := f.pendingReplays[sessionID]; pending {
+			delete(f.pendingReplays, sessionID)
+
+			for _, entry := range f.backlog {
+				if entry.seq <= lastSeq && entry.visibleTo(sessionID) {
+					sendLogNotification(sessionWithLogging, entry)
+				}
+			}
+		}
+
+		f.flushQueued(sessionID, sessionWithLogging)
+	})
+}
+
+// sendLogNotification sends a log entry to a session if the entry level is at or above the
+// session level. The notification is dropped if the session channel is full.
+func sendLogNotification(session server.SessionWithLogging, entry logEntry) {
+	if !isLogLevelEnabled(entry.level, session.GetLogLevel()) {
+		return
+	}
+
+	notification := mcp.NewLoggingMessageNotification(entry.level, LoggerName, entry.data)
+
+	select {
+	case session.NotificationChannel() <- mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: notification.Method,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{
+					"level":  notification.Params.Level,
+					"logger": notification.Params.Logger,
+					"data":   notification.Params.Data,
+				},
+			},
+		},
+	}:
+	default:
+	}
+}
+
+// isLogLevelEnabled checks if a log level is at or above the minimum level
+func isLogLevelEnabled(level, minimum mcp.LoggingLevel) bool {
+	return slices.Index(mcpLogLevels, level) >= slices.Index(mcpLogLevels, minimum)
+}
+
+// convertLogLevel converts a zerolog level to the corresponding MCP logging level
+func convertLogLevel(level zerolog.Level) mcp.LoggingLevel {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return mcp.LoggingLevelDebug
+	case zerolog.InfoLevel:
+		return mcp.LoggingLevelInfo
+	case zerolog.WarnLevel:
+		return mcp.LoggingLevelWarning
+	case zerolog.ErrorLevel:
+		return mcp.LoggingLevelError
+	case zerolog.FatalLevel:
+		return mcp.LoggingLevelCritical
+	case zerolog.PanicLevel:
+		return mcp.LoggingLevelEmergency
+	default:
+		return mcp.LoggingLevelInfo
+	}
+}
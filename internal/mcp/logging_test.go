@@ -0,0 +1,211 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLoggingSession is a minimal server.SessionWithLogging implementation
+type fakeLoggingSession struct {
+	id            string
+	initialized   bool
+	level         mcp.LoggingLevel
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newFakeLoggingSession(id string, level mcp.LoggingLevel) *fakeLoggingSession {
+	return &fakeLoggingSession{
+		id:            id,
+		initialized:   true,
+		level:         level,
+		notifications: make(chan mcp.JSONRPCNotification, 10),
+	}
+}
+
+func (s *fakeLoggingSession) Initialize()                        { s.initialized = true }
+func (s *fakeLoggingSession) Initialized() bool                  { return s.initialized }
+func (s *fakeLoggingSession) SessionID() string                  { return s.id }
+func (s *fakeLoggingSession) SetLogLevel(level mcp.LoggingLevel) { s.level = level }
+func (s *fakeLoggingSession) GetLogLevel() mcp.LoggingLevel      { return s.level }
+func (s *fakeLoggingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *fakeLoggingSession) received() []mcp.JSONRPCNotification {
+	var result []mcp.JSONRPCNotification
+	for {
+		select {
+		case n := <-s.notifications:
+			result = append(result, n)
+		default:
+			return result
+		}
+	}
+}
+
+func TestLogForwarderWriteLevel(t *testing.T) {
+	forwarder := NewLogForwarder()
+	hooks := &server.Hooks{}
+	forwarder.registerHooks(hooks)
+
+	warningSession := newFakeLoggingSession("warning", mcp.LoggingLevelWarning)
+	debugSession := newFakeLoggingSession("debug", mcp.LoggingLevelDebug)
+	for _, session := range []server.ClientSession{warningSession, debugSession} {
+		for _, hook := range hooks.OnRegisterSession {
+			hook(context.Background(), session)
+		}
+	}
+
+	logger := zerolog.New(forwarder)
+	logger.Info().Str("tool", "listStacks").Msg("info message")
+	logger.Error().Msg("error message")
+
+	debugNotifications := debugSession.received()
+	require.Len(t, debugNotifications, 2)
+	assert.Equal(t, "notifications/message", debugNotifications[0].Method)
+	assert.Equal(t, mcp.LoggingLevelInfo, debugNotifications[0].Params.AdditionalFields["level"])
+	assert.Equal(t, LoggerName, debugNotifications[0].Params.AdditionalFields["logger"])
+	data, ok := debugNotifications[0].Params.AdditionalFields["data"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "info message", data["message"])
+	assert.Equal(t, "listStacks", data["tool"])
+	assert.NotContains(t, data, zerolog.LevelFieldName)
+
+	warningNotifications := warningSession.received()
+	require.Len(t, warningNotifications, 1)
+	assert.Equal(t, mcp.LoggingLevelError, warningNotifications[0].Params.AdditionalFields["level"])
+
+	for _, hook := range hooks.OnUnregisterSession {
+		hook(context.Background(), debugSession)
+	}
+	logger.Error().Msg("after unregister")
+	assert.Empty(t, debugSession.received())
+	assert.Len(t, warningSession.received(), 1)
+}
+
+// registerSession calls the session registration hooks
+func registerSession(hooks *server.Hooks, session server.ClientSession) {
+	for _, hook := range hooks.OnRegisterSession {
+		hook(context.Background(), session)
+	}
+}
+
+// setLogLevel calls the logging/setLevel hooks for a session
+func setLogLevel(hooks *server.Hooks, session server.ClientSession) {
+	ctx := (&server.MCPServer{}).WithContext(context.Background(), session)
+	for _, hook := range hooks.OnAfterSetLevel {
+		hook(ctx, 1, &mcp.SetLevelRequest{}, &mcp.EmptyResult{})
+	}
+}
+
+func TestLogForwarderSessionScope(t *testing.T) {
+	forwarder := NewLogForwarder()
+	hooks := &server.Hooks{}
+	forwarder.registerHooks(hooks)
+
+	first := newFakeLoggingSession("first", mcp.LoggingLevelDebug)
+	second := newFakeLoggingSession("second", mcp.LoggingLevelDebug)
+	registerSession(hooks, first)
+	registerSession(hooks, second)
+
+	logger := zerolog.New(forwarder)
+	logger.Error().Str(LogSessionField, "first").Str("error", "invalid name parameter").Msg("tool call failed")
+	logger.Warn().Msg("server message")
+
+	firstNotifications := first.received()
+	require.Len(t, firstNotifications, 2)
+	data := firstNotifications[0].Params.AdditionalFields["data"].(map[string]any)
+	assert.Equal(t, "invalid name parameter", data["error"])
+	assert.NotContains(t, data, LogSessionField)
+
+	secondNotifications := second.received()
+	require.Len(t, secondNotifications, 1, "the entries of a session are not sent to the other sessions")
+	data = secondNotifications[0].Params.AdditionalFields["data"].(map[string]any)
+	assert.Equal(t, "server message", data["message"])
+}
+
+func TestLogForwarderBacklogReplay(t *testing.T) {
+	forwarder := NewLogForwarder()
+	forwarder.backlogSize = 4
+	hooks := &server.Hooks{}
+	forwarder.registerHooks(hooks)
+
+	logger := zerolog.New(forwarder)
+	logger.Warn().Msg("first")
+	logger.Warn().Msg("second")
+	logger.Debug().Msg("third")
+	logger.Warn().Str(LogSessionField, "other").Msg("other session")
+
+	session := newFakeLoggingSession("late", mcp.LoggingLevelWarning)
+	session.initialized = false
+	registerSession(hooks, session)
+	logger.Warn().Msg("after registration")
+
+	setLogLevel(hooks, session)
+
+	notifications := session.received()
+	require.Len(t, notifications, 1, "only the last entries written before the registration, at or above the session level and visible to the session are replayed")
+	data := notifications[0].Params.AdditionalFields["data"].(map[string]any)
+	assert.Equal(t, "second", data["message"])
+
+	setLogLevel(hooks, session)
+	assert.Empty(t, session.received(), "the backlog is only replayed once")
+}
+
+func TestLogForwarderQueueUntilInitialized(t *testing.T) {
+	forwarder := NewLogForwarder()
+	hooks := &server.Hooks{}
+	forwarder.registerHooks(hooks)
+
+	session := newFakeLoggingSession("starting", mcp.LoggingLevelDebug)
+	session.initialized = false
+	registerSession(hooks, session)
+
+	logger := zerolog.New(forwarder)
+	logger.Info().Msg("before initialization")
+	assert.Empty(t, session.received(), "the entries are not sent before the session is initialized")
+
+	session.Initialize()
+	ctx := (&server.MCPServer{}).WithContext(context.Background(), session)
+	for _, hook := range hooks.OnBeforeAny {
+		hook(ctx, 1, mcp.MethodToolsList, &mcp.ListToolsRequest{})
+	}
+
+	notifications := session.received()
+	require.Len(t, notifications, 1, "the queued entries are sent once the session is initialized")
+	data := notifications[0].Params.AdditionalFields["data"].(map[string]any)
+	assert.Equal(t, "before initialization", data["message"])
+
+	logger.Info().Msg("after initialization")
+	notifications = session.received()
+	require.Len(t, notifications, 1, "the queued entries are only sent once")
+	data = notifications[0].Params.AdditionalFields["data"].(map[string]any)
+	assert.Equal(t, "after initialization", data["message"])
+}
+
+func TestConvertLogLevel(t *testing.T) {
+	tests := []struct {
+		level    zerolog.Level
+		expected mcp.LoggingLevel
+	}{
+		{zerolog.TraceLevel, mcp.LoggingLevelDebug},
+		{zerolog.DebugLevel, mcp.LoggingLevelDebug},
+		{zerolog.InfoLevel, mcp.LoggingLevelInfo},
+		{zerolog.WarnLevel, mcp.LoggingLevelWarning},
+		{zerolog.ErrorLevel, mcp.LoggingLevelError},
+		{zerolog.FatalLevel, mcp.LoggingLevelCritical},
+		{zerolog.PanicLevel, mcp.LoggingLevelEmergency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, convertLogLevel(tt.level))
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// dryRunServer is the dryRun value validating the objects on the server without persisting them
+	dryRunServer = "server"
+)
+
+// manifestKindOrder is the order in which the kinds of a manifest are applied, so that the objects are created
+// after the objects they depend on. The other kinds, including the custom resources, are applied last.
+var manifestKindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"PriorityClass",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+}
+
+func (s *PortainerMCPServer) HandleApplyKubernetesManifest() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		manifest, err := parser.GetString("manifest", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid manifest parameter", err), nil
+		}
+
+		namespace, err := parser.GetString("namespace", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+		}
+		if namespace == "" {
+			namespace = defaultKubernetesNamespace
+		}
+
+		dryRun, err := parser.GetString("dryRun", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid dryRun parameter", err), nil
+		}
+		if dryRun != "" && dryRun != dryRunServer {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid dryRun: %s, only server is supported", dryRun)), nil
+		}
+
+		fieldManager, err := parser.GetString("fieldManager", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid fieldManager parameter", err), nil
+		}
+		if fieldManager == "" {
+			fieldManager = defaultFieldManager
+		}
+
+		force, err := parser.GetBoolean("force", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid force parameter", err), nil
+		}
+
+		objects, err := parseManifest(manifest)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid manifest parameter", err), nil
+		}
+		if len(objects) == 0 {
+			return mcp.NewToolResultError("the manifest does not contain any object"), nil
+		}
+		sortManifestObjects(objects)
+
+		queryParams := map[string]string{"fieldManager": fieldManager}
+		if force {
+			queryParams["force"] = "true"
+		}
+		if dryRun == dryRunServer {
+			queryParams["dryRun"] = "All"
+		}
+
+		discovery := s.newKubernetesDiscovery(environmentId)
+
+		var sb strings.Builder
+		counts := map[string]int{}
+		for _, object := range objects {
+			status, err := s.applyManifestObject(discovery, object, namespace, queryParams)
+			if err != nil {
+				counts["failed"]++
+				fmt.Fprintf(&sb, "%s failed: %s\n", manifestObjectName(object), err)
+				continue
+			}
+
+			counts[status]++
+			fmt.Fprintf(&sb, "%s %s", manifestObjectName(object), status)
+			if dryRun == dryRunServer {
+				sb.WriteString(" (server dry run)")
+			}
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "\n%d %s: %d created, %d configured, %d unchanged, %d failed\n", len(objects), pluralize(len(objects), "object"),
+			counts["created"], counts["configured"], counts["unchanged"], counts["failed"])
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.NewTextContent(sb.String())},
+			IsError: counts["failed"] > 0,
+		}, nil
+	}
+}
+
+// applyManifestObject applies an object with a server-side apply patch and returns whether it was created,
+// configured or left unchanged. The object is compared with its current state to tell them apart.
+func (s *PortainerMCPServer) applyManifestObject(discovery *kubernetesDiscovery, object *unstructured.Unstructured, namespace string, queryParams map[string]string) (string, error) {
+	gvk := object.GroupVersionKind()
+	resource, err := discovery.resource(gvk)
+	if err != nil {
+		return "", err
+	}
+
+	if resource.Namespaced && object.GetNamespace() == "" {
+		object.SetNamespace(namespace)
+	}
+	if !resource.Namespaced {
+		object.SetNamespace("")
+	}
+
+	path := kubernetesResourcePath(gvk.GroupVersion(), resource.Name, object.GetNamespace(), object.GetName())
+
+	response, responseBody, err := s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+		EnvironmentID: discovery.environmentId,
+		Path:          path,
+		Method:        "GET",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var existing *unstructured.Unstructured
+	switch {
+	case isSuccessStatusCode(response.StatusCode):
+		existing = &unstructured.Unstructured{}
+		if err := existing.UnmarshalJSON(responseBody); err != nil {
+			return "", fmt.Errorf("failed to decode the current object: %w", err)
+		}
+	case response.StatusCode != http.StatusNotFound:
+		return "", kubernetesResponseError(response, responseBody)
+	}
+
+	body, err := object.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode object: %w", err)
+	}
+
+	response, responseBody, err = s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+		EnvironmentID: discovery.environmentId,
+		Path:          path,
+		Method:        "PATCH",
+		QueryParams:   queryParams,
+		Headers:       map[string]string{"Content-Type": patchContentTypes[PatchTypeApply]},
+		Body:          bytes.NewReader(body),
+	})
+	if err != nil {
+		return "", err
+	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return "", kubernetesResponseError(response, responseBody)
+	}
+
+	if existing == nil {
+		return "created", nil
+	}
+
+	applied := &unstructured.Unstructured{}
+	if err := applied.UnmarshalJSON(responseBody); err != nil {
+		return "", fmt.Errorf("failed to decode the applied object: %w", err)
+	}
+	if reflect.DeepEqual(withoutVolatileFields(existing), withoutVolatileFields(applied)) {
+		return "unchanged", nil
+	}
+	return "configured", nil
+}
+
+// parseManifest splits a multi-document YAML or JSON manifest into objects. The items of the List objects are
+// returned as separate objects.
+func parseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+
+	var objects []*unstructured.Unstructured
+	for document := 1; ; document++ {
+		var content map[string]any
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("document %d: %w", document, err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: content}
+		if object.IsList() {
+			list, err := object.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", document, err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+		objects = append(objects, object)
+	}
+
+	for i, object := range objects {
+		if object.GetAPIVersion() == "" || object.GetKind() == "" || object.GetName() == "" {
+			return nil, fmt.Errorf("object %d: apiVersion, kind and metadata.name are required", i+1)
+		}
+	}
+
+	return objects, nil
+}
+
+// sortManifestObjects sorts the objects in the order they must be applied, keeping the order of the manifest
+// for the objects of the same kind
+func sortManifestObjects(objects []*unstructured.Unstructured) {
+	rank := func(object *unstructured.Unstructured) int {
+		if i := slices.Index(manifestKindOrder, object.GetKind()); i >= 0 {
+			return i
+		}
+		return len(manifestKindOrder)
+	}
+
+	slices.SortStableFunc(objects, func(a, b *unstructured.Unstructured) int {
+		return rank(a) - rank(b)
+	})
+}
+
+// manifestObjectName returns the name of an object as displayed by kubectl, e.g. deployment.apps/web
+func manifestObjectName(object *unstructured.Unstructured) string {
+	gvk := object.GroupVersionKind()
+	kind := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		kind += "." + gvk.Group
+	}
+	return kind + "/" + object.GetName()
+}
+
+// withoutVolatileFields returns a copy of an object without the fields updated by every apply
+func withoutVolatileFields(object *unstructured.Unstructured) map[string]any {
+	stripped := object.DeepCopy()
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "generation")
+	return stripped.Object
+}
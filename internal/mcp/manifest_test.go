@@ -0,0 +1,244 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// matchApplyPatch matches the server-side apply patches of an object
+func matchApplyPatch(path string, queryParams map[string]string) any {
+	return mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+		return opts.Method == "PATCH" && opts.Path == path &&
+			opts.Headers["Content-Type"] == "application/apply-patch+yaml" &&
+			assert.ObjectsAreEqual(queryParams, opts.QueryParams)
+	})
+}
+
+func TestHandleApplyKubernetesManifest(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           map[string]any
+		setupMock       func(mockClient *MockPortainerClient)
+		expectedText    string
+		expectedError   bool
+		expectedApplies []string
+	}{
+		{
+			name: "objects applied in dependency order",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"manifest": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 2
+---
+# the namespace of the application
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: production
+`,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1")).
+					Return(createMockHttpResponse(http.StatusOK, appsV1Resources), nil).Once()
+
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop")).
+					Return(createMockHttpResponse(http.StatusOK, `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"shop","resourceVersion":"10"}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchApplyPatch("/api/v1/namespaces/shop", map[string]string{"fieldManager": "portainer-mcp"})).
+					Return(createMockHttpResponse(http.StatusOK, `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"shop","resourceVersion":"11","managedFields":[{"manager":"portainer-mcp"}]}}`), nil)
+
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/default/configmaps/settings")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{"kind":"Status","message":"configmaps \"settings\" not found"}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchApplyPatch("/api/v1/namespaces/default/configmaps/settings", map[string]string{"fieldManager": "portainer-mcp"})).
+					Return(createMockHttpResponse(http.StatusCreated, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"}}`), nil)
+
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+					Return(createMockHttpResponse(http.StatusOK, `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","generation":1},"spec":{"replicas":1}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchApplyPatch("/apis/apps/v1/namespaces/shop/deployments/web", map[string]string{"fieldManager": "portainer-mcp"})).
+					Return(createMockHttpResponse(http.StatusOK, `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","generation":2},"spec":{"replicas":2}}`), nil)
+			},
+			expectedText: "namespace/shop unchanged\n" +
+				"configmap/settings created\n" +
+				"deployment.apps/web configured\n" +
+				"\n3 objects: 1 created, 1 configured, 1 unchanged, 0 failed\n",
+			expectedApplies: []string{
+				`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"shop"}}`,
+				`{"apiVersion":"v1","data":{"mode":"production"},"kind":"ConfigMap","metadata":{"name":"settings","namespace":"default"}}`,
+				`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"shop"},"spec":{"replicas":2}}`,
+			},
+		},
+		{
+			name: "server dry run of a list with a failing object",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"dryRun":        "server",
+				"fieldManager":  "ci",
+				"force":         true,
+				"manifest": `{"apiVersion":"v1","kind":"List","items":[
+					{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"}},
+					{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"gadget"}}
+				]}`,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/example.com/v1")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{"kind":"Status","message":"the server could not find the requested resource"}`), nil).Once()
+
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/configmaps/settings")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchApplyPatch("/api/v1/namespaces/shop/configmaps/settings",
+					map[string]string{"fieldManager": "ci", "force": "true", "dryRun": "All"})).
+					Return(createMockHttpResponse(http.StatusCreated, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"}}`), nil)
+			},
+			expectedText: "configmap/settings created (server dry run)\n" +
+				"widget.example.com/gadget failed: the API version example.com/v1 is not served by the cluster\n" +
+				"\n2 objects: 1 created, 0 configured, 0 unchanged, 1 failed\n",
+			expectedError: true,
+			expectedApplies: []string{
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"}}`,
+			},
+		},
+		{
+			name: "object rejected by the API server",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"manifest":      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  count: 1\n",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1")).
+					Return(createMockHttpResponse(http.StatusOK, coreV1Resources), nil).Once()
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/default/configmaps/settings")).
+					Return(createMockHttpResponse(http.StatusNotFound, `{}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchApplyPatch("/api/v1/namespaces/default/configmaps/settings", map[string]string{"fieldManager": "portainer-mcp"})).
+					Return(createMockHttpResponse(http.StatusBadRequest, `{"kind":"Status","message":"ConfigMap in version \"v1\" cannot be handled as a ConfigMap"}`), nil)
+			},
+			expectedText: "configmap/settings failed: HTTP 400 Bad Request: ConfigMap in version \"v1\" cannot be handled as a ConfigMap\n" +
+				"\n1 object: 0 created, 0 configured, 0 unchanged, 1 failed\n",
+			expectedError: true,
+			expectedApplies: []string{
+				`{"apiVersion":"v1","data":{"count":1},"kind":"ConfigMap","metadata":{"name":"settings","namespace":"default"}}`,
+			},
+		},
+		{
+			name: "object without a name",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"manifest":      "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: shop\n---\napiVersion: v1\nkind: ConfigMap\n",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "invalid manifest parameter: object 2: apiVersion, kind and metadata.name are required",
+			expectedError: true,
+		},
+		{
+			name: "empty manifest",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"manifest":      "---\n# nothing to apply\n---\n",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "the manifest does not contain any object",
+			expectedError: true,
+		},
+		{
+			name: "unsupported dry run",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"manifest":      "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: shop\n",
+				"dryRun":        "client",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "invalid dryRun: client, only server is supported",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			var applies []string
+			for _, call := range mockClient.ExpectedCalls {
+				call.Run(func(args mock.Arguments) {
+					opts := args.Get(0).(models.KubernetesProxyRequestOptions)
+					if opts.Method == "PATCH" {
+						body, err := io.ReadAll(opts.Body)
+						require.NoError(t, err)
+						applies = append(applies, strings.TrimSpace(string(body)))
+					}
+				})
+			}
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleApplyKubernetesManifest()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+			assert.Equal(t, tt.expectedApplies, applies)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSortManifestObjects(t *testing.T) {
+	object := func(kind, name string) *unstructured.Unstructured {
+		o := &unstructured.Unstructured{}
+		o.SetKind(kind)
+		o.SetName(name)
+		return o
+	}
+
+	objects := []*unstructured.Unstructured{
+		object("Widget", "gadget"),
+		object("Deployment", "web"),
+		object("Service", "web"),
+		object("CustomResourceDefinition", "widgets.example.com"),
+		object("Deployment", "api"),
+		object("Namespace", "shop"),
+	}
+
+	sortManifestObjects(objects)
+
+	var names []string
+	for _, o := range objects {
+		names = append(names, manifestObjectName(o))
+	}
+	assert.Equal(t, []string{
+		"namespace/shop",
+		"customresourcedefinition/widgets.example.com",
+		"service/web",
+		"deployment/web",
+		"deployment/api",
+		"widget/gadget",
+	}, names)
+}
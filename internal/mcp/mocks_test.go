@@ -69,6 +69,14 @@ func (m *MockPortainerClient) GetEnvironments() ([]models.Environment, error) {
 	return args.Get(0).([]models.Environment), args.Error(1)
 }
 
+func (m *MockPortainerClient) GetEnvironmentsWithFilter(filter models.EnvironmentFilter) ([]models.Environment, int, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.Environment), args.Int(1), args.Error(2)
+}
+
 func (m *MockPortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
 	args := m.Called(id, tagIds)
 	return args.Error(0)
@@ -254,3 +262,44 @@ func (m *MockPortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxy
 	}
 	return args.Get(0).(*http.Response), args.Error(1)
 }
+
+// Helm methods
+func (m *MockPortainerClient) GetHelmReleases(environmentId int, namespace string) ([]models.HelmRelease, error) {
+	args := m.Called(environmentId, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HelmRelease), args.Error(1)
+}
+
+func (m *MockPortainerClient) GetHelmReleaseValues(environmentId int, name string, namespace string, revision int) (models.HelmReleaseValues, error) {
+	args := m.Called(environmentId, name, namespace, revision)
+	return args.Get(0).(models.HelmReleaseValues), args.Error(1)
+}
+
+func (m *MockPortainerClient) GetHelmReleaseHistory(environmentId int, name string, namespace string) ([]models.HelmRelease, error) {
+	args := m.Called(environmentId, name, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HelmRelease), args.Error(1)
+}
+
+func (m *MockPortainerClient) InstallHelmChart(environmentId int, opts models.HelmInstallOptions) (models.HelmRelease, error) {
+	args := m.Called(environmentId, opts)
+	return args.Get(0).(models.HelmRelease), args.Error(1)
+}
+
+func (m *MockPortainerClient) UninstallHelmRelease(environmentId int, name string, namespace string) error {
+	args := m.Called(environmentId, name, namespace)
+	return args.Error(0)
+}
+
+// Registry methods
+func (m *MockPortainerClient) GetRegistries() ([]models.Registry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Registry), args.Error(1)
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// predefinedNetworks are the networks created by Docker, which are never pruned
+var predefinedNetworks = []string{"bridge", "host", "none"}
+
+func (s *PortainerMCPServer) AddNetworkFeatures() {
+	s.addToolIfExists(ToolListNetworks, s.HandleListNetworks())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolPruneNetworks, s.HandlePruneNetworks())
+	}
+}
+
+func (s *PortainerMCPServer) HandleListNetworks() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		unused, err := parser.GetBoolean("unused", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid unused parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		rawNetworks, usedBy, result, err := s.getNetworkUsage(ctx, environmentId, true)
+		if result != nil || err != nil {
+			return result, err
+		}
+
+		networks := make([]models.DockerNetwork, 0, len(rawNetworks))
+		for _, rawNetwork := range rawNetworks {
+			dockerNetwork := models.ConvertToDockerNetwork(rawNetwork, usedBy[rawNetwork.Name])
+			if unused && len(dockerNetwork.UsedBy) > 0 {
+				continue
+			}
+			networks = append(networks, dockerNetwork)
+		}
+		slices.SortFunc(networks, func(a, b models.DockerNetwork) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		data, err := output.render(networks)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render networks", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandlePruneNetworks() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		confirm, err := parser.GetBoolean("confirm", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid confirm parameter", err), nil
+		}
+
+		if confirm {
+			var report network.PruneReport
+			if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          "/networks/prune",
+				Method:        "POST",
+			}, &report); result != nil || err != nil {
+				return result, err
+			}
+
+			return mcp.NewToolResultText(formatPruneReport("network", report.NetworksDeleted, 0)), nil
+		}
+
+		// The networks of the stopped containers are pruned, only the running containers keep their networks
+		rawNetworks, usedBy, result, err := s.getNetworkUsage(ctx, environmentId, false)
+		if result != nil || err != nil {
+			return result, err
+		}
+
+		var candidates []pruneCandidate
+		for _, rawNetwork := range rawNetworks {
+			// The Swarm networks are pruned by the managers when no service uses them, which is not previewed
+			if rawNetwork.Scope == "swarm" || rawNetwork.Ingress || slices.Contains(predefinedNetworks, rawNetwork.Name) {
+				continue
+			}
+			if len(usedBy[rawNetwork.Name]) > 0 {
+				continue
+			}
+			candidates = append(candidates, pruneCandidate{name: rawNetwork.Name})
+		}
+		slices.SortFunc(candidates, func(a, b pruneCandidate) int {
+			return cmp.Compare(a.name, b.name)
+		})
+
+		return mcp.NewToolResultText(formatPrunePreview(ToolPruneNetworks, "network", candidates)), nil
+	}
+}
+
+// getNetworkUsage returns the networks of an environment and the names of the containers connected to each
+// network, by network name. The stopped containers are only included when all is true.
+func (s *PortainerMCPServer) getNetworkUsage(ctx context.Context, environmentId int, all bool) ([]network.Summary, map[string][]string, *mcp.CallToolResult, error) {
+	var rawNetworks []network.Summary
+	if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          "/networks",
+		Method:        "GET",
+	}, &rawNetworks); result != nil || err != nil {
+		return nil, nil, result, err
+	}
+
+	opts := models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          "/containers/json",
+		Method:        "GET",
+	}
+	if all {
+		opts.QueryParams = map[string]string{"all": "true"}
+	}
+
+	var rawContainers []container.Summary
+	if result, err := s.decodeDockerResponse(ctx, opts, &rawContainers); result != nil || err != nil {
+		return nil, nil, result, err
+	}
+
+	usedBy := containerNamesBy(rawContainers, func(rawContainer container.Summary) []string {
+		if rawContainer.NetworkSettings == nil {
+			return nil
+		}
+		names := make([]string, 0, len(rawContainer.NetworkSettings.Networks))
+		for name := range rawContainer.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		return names
+	})
+
+	return rawNetworks, usedBy, nil, nil
+}
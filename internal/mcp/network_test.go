@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// networks is the network list of a Swarm manager with a Compose project
+const networks = `[
+	{"Name":"bridge","Id":"n1","Driver":"bridge","Scope":"local"},
+	{"Name":"host","Id":"n2","Driver":"host","Scope":"local"},
+	{"Name":"ingress","Id":"n3","Driver":"overlay","Scope":"swarm","Ingress":true},
+	{"Name":"shop_default","Id":"n4","Driver":"bridge","Scope":"local","IPAM":{"Config":[{"Subnet":"172.18.0.0/16"}]}},
+	{"Name":"jobs_default","Id":"n5","Driver":"bridge","Scope":"local"},
+	{"Name":"legacy","Id":"n6","Driver":"bridge","Scope":"local"}
+]`
+
+func TestHandleListNetworks(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/networks"
+	})).Return(createMockHttpResponse(http.StatusOK, networks), nil)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/containers/json" &&
+			assert.ObjectsAreEqual(map[string]string{"all": "true"}, opts.QueryParams)
+	})).Return(createMockHttpResponse(http.StatusOK, `[
+		{"Id":"c1","Names":["/web"],"State":"running","NetworkSettings":{"Networks":{"shop_default":{"NetworkID":"n4"},"bridge":{"NetworkID":"n1"}}}},
+		{"Id":"c2","Names":["/migrate"],"State":"exited","NetworkSettings":{"Networks":{"jobs_default":{"NetworkID":"n5"}}}}
+	]`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleListNetworks()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"outputFormat":  "csv",
+		"fields":        []any{"name", "scope", "used_by"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "name,scope,used_by\n"+
+		`bridge,local,"[""web""]"`+"\n"+
+		"host,local,[]\n"+
+		"ingress,swarm,[]\n"+
+		`jobs_default,local,"[""migrate""]"`+"\n"+
+		"legacy,local,[]\n"+
+		`shop_default,local,"[""web""]"`+"\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandlePruneNetworks(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        map[string]any
+		setupMock    func(mockClient *MockPortainerClient)
+		expectedText string
+	}{
+		{
+			name: "dry run ignores the predefined networks and the stopped containers",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/networks"
+				})).Return(createMockHttpResponse(http.StatusOK, networks), nil)
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/containers/json" && opts.QueryParams == nil
+				})).Return(createMockHttpResponse(http.StatusOK, `[
+					{"Id":"c1","Names":["/web"],"State":"running","NetworkSettings":{"Networks":{"shop_default":{"NetworkID":"n4"}}}}
+				]`), nil)
+			},
+			expectedText: "Dry run: 2 networks would be removed. Nothing was removed, " +
+				"call pruneNetworks again with confirm set to true to remove them.\n\n" +
+				"- jobs_default\n" +
+				"- legacy\n",
+		},
+		{
+			name: "confirmed prune",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"confirm":       true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "POST" && opts.Path == "/networks/prune"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"NetworksDeleted":["jobs_default","legacy"]}`), nil)
+			},
+			expectedText: "Removed 2 networks.\n\n- jobs_default\n- legacy\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandlePruneNetworks()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.False(t, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
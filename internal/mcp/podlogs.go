@@ -0,0 +1,344 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultPodLogsTail is the number of log lines returned per pod when tailLines is not specified
+	defaultPodLogsTail = 100
+	// maxPodLogsPods is the maximum number of pods whose logs are aggregated by getPodLogs
+	maxPodLogsPods = 10
+	// defaultContainerAnnotation is the annotation selecting the container used by kubectl logs when none is specified
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+)
+
+// kubernetesPod is the subset of a core/v1 Pod used by the pod tools
+type kubernetesPod struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		NodeName   string `json:"nodeName"`
+		Containers []struct {
+			Name  string `json:"name"`
+			Image string `json:"image"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase             string                      `json:"phase"`
+		Reason            string                      `json:"reason"`
+		PodIP             string                      `json:"podIP"`
+		ContainerStatuses []kubernetesContainerStatus `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// kubernetesContainerStatus is the status of a container of a pod
+type kubernetesContainerStatus struct {
+	Name                 string                   `json:"name"`
+	Ready                bool                     `json:"ready"`
+	RestartCount         int32                    `json:"restartCount"`
+	State                kubernetesContainerState `json:"state"`
+	LastTerminationState kubernetesContainerState `json:"lastState"`
+}
+
+// kubernetesContainerState is the state of a container, only one of its members is set
+type kubernetesContainerState struct {
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting"`
+	Running *struct {
+		StartedAt metav1.Time `json:"startedAt"`
+	} `json:"running"`
+	Terminated *struct {
+		ExitCode   int32       `json:"exitCode"`
+		Reason     string      `json:"reason"`
+		Message    string      `json:"message"`
+		FinishedAt metav1.Time `json:"finishedAt"`
+	} `json:"terminated"`
+}
+
+// kubernetesPodList is a core/v1 PodList
+type kubernetesPodList struct {
+	Items []kubernetesPod `json:"items"`
+}
+
+// podLogLine is a log line of a pod, with the timestamp added by the Kubernetes API
+type podLogLine struct {
+	pod       string
+	timestamp time.Time
+	raw       string
+	message   string
+}
+
+func (s *PortainerMCPServer) HandleGetPodLogs() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		namespace, err := parser.GetString("namespace", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+		}
+
+		pod, err := parser.GetString("pod", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pod parameter", err), nil
+		}
+
+		labelSelector, err := parser.GetString("labelSelector", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid labelSelector parameter", err), nil
+		}
+
+		workload, err := parser.GetString("workload", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid workload parameter", err), nil
+		}
+
+		selectors := 0
+		for _, value := range []string{pod, labelSelector, workload} {
+			if value != "" {
+				selectors++
+			}
+		}
+		if selectors != 1 {
+			return mcp.NewToolResultError("exactly one of pod, labelSelector or workload must be specified"), nil
+		}
+
+		container, err := parser.GetString("container", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid container parameter", err), nil
+		}
+
+		tailLines, err := parser.GetInt("tailLines", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid tailLines parameter", err), nil
+		}
+		if tailLines == 0 {
+			tailLines = defaultPodLogsTail
+		}
+
+		sinceSeconds, err := parser.GetInt("sinceSeconds", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid sinceSeconds parameter", err), nil
+		}
+		if sinceSeconds < 0 {
+			return mcp.NewToolResultError("sinceSeconds must be a positive number"), nil
+		}
+
+		previous, err := parser.GetBoolean("previous", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid previous parameter", err), nil
+		}
+
+		timestamps, err := parser.GetBoolean("timestamps", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid timestamps parameter", err), nil
+		}
+
+		if workload != "" {
+			ref, err := parseWorkloadRef(namespace, workload)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid workload parameter", err), nil
+			}
+
+			var rawWorkload kubernetesWorkload
+			if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          ref.path(),
+				Method:        "GET",
+			}, &rawWorkload); result != nil || err != nil {
+				return result, err
+			}
+
+			if rawWorkload.Spec.Selector == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("%s has no pod selector", ref)), nil
+			}
+			selector, err := metav1.LabelSelectorAsSelector(rawWorkload.Spec.Selector)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid workload selector", err), nil
+			}
+			labelSelector = selector.String()
+		}
+
+		var pods []kubernetesPod
+		if pod != "" {
+			var rawPod kubernetesPod
+			if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          fmt.Sprintf("/api/v1/namespaces/%s/pods/%s", url.PathEscape(namespace), url.PathEscape(pod)),
+				Method:        "GET",
+			}, &rawPod); result != nil || err != nil {
+				return result, err
+			}
+			pods = append(pods, rawPod)
+		} else {
+			var rawPods kubernetesPodList
+			if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(namespace)),
+				Method:        "GET",
+				QueryParams:   map[string]string{"labelSelector": labelSelector},
+			}, &rawPods); result != nil || err != nil {
+				return result, err
+			}
+			pods = rawPods.Items
+		}
+
+		if len(pods) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No pods found in namespace %s matching %s", namespace, labelSelector)), nil
+		}
+
+		slices.SortFunc(pods, func(a, b kubernetesPod) int {
+			return strings.Compare(a.Metadata.Name, b.Metadata.Name)
+		})
+		omittedPods := max(len(pods)-maxPodLogsPods, 0)
+		pods = pods[:min(len(pods), maxPodLogsPods)]
+
+		queryParams := map[string]string{"timestamps": "true"}
+		if tailLines > 0 {
+			queryParams["tailLines"] = strconv.Itoa(tailLines)
+		}
+		if sinceSeconds > 0 {
+			queryParams["sinceSeconds"] = strconv.Itoa(sinceSeconds)
+		}
+		if previous {
+			queryParams["previous"] = "true"
+		}
+
+		var lines []podLogLine
+		var failures []string
+		for _, rawPod := range pods {
+			podQueryParams := maps.Clone(queryParams)
+			if podContainer := podLogsContainer(rawPod, container); podContainer != "" {
+				podQueryParams["container"] = podContainer
+			}
+
+			response, responseBody, err := s.sendKubernetesRequest(models.KubernetesProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log", url.PathEscape(namespace), url.PathEscape(rawPod.Metadata.Name)),
+				Method:        "GET",
+				QueryParams:   podQueryParams,
+			})
+			if err != nil {
+				if len(pods) == 1 {
+					return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
+				}
+				failures = append(failures, fmt.Sprintf("- %s: %s", rawPod.Metadata.Name, err))
+				continue
+			}
+			if !isSuccessStatusCode(response.StatusCode) {
+				if len(pods) == 1 {
+					return s.proxyResult(ctx, response, responseBody), nil
+				}
+				failures = append(failures, fmt.Sprintf("- %s: HTTP %d %s: %s", rawPod.Metadata.Name, response.StatusCode,
+					http.StatusText(response.StatusCode), extractProxyErrorMessage(responseBody)))
+				continue
+			}
+
+			lines = append(lines, parsePodLogLines(rawPod.Metadata.Name, string(responseBody))...)
+		}
+
+		logs := formatPodLogs(lines, len(pods) > 1, timestamps)
+		if omittedPods > 0 || len(failures) > 0 {
+			logs = strings.TrimSuffix(logs, "\n")
+		}
+		if omittedPods > 0 {
+			logs += fmt.Sprintf("\n\nOnly the logs of the first %d pods are returned, %d more pods match. Use a more specific label selector or the pod parameter.", maxPodLogsPods, omittedPods)
+		}
+		if len(failures) > 0 {
+			logs += "\n\nThe logs of some pods could not be retrieved:\n" + strings.Join(failures, "\n")
+		}
+
+		result, err := s.responses.result(ctx, []byte(logs))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to buffer pod logs", err), nil
+		}
+
+		return result, nil
+	}
+}
+
+// podLogsContainer returns the container whose logs are requested for a pod. Like kubectl logs, the default container
+// annotation is used when no container is specified, and the first container when the pod has several containers.
+// An empty container is returned to let the Kubernetes API select the only container of the pod.
+func podLogsContainer(pod kubernetesPod, container string) string {
+	if container != "" {
+		return container
+	}
+	if defaultContainer := pod.Metadata.Annotations[defaultContainerAnnotation]; defaultContainer != "" {
+		return defaultContainer
+	}
+	if len(pod.Spec.Containers) > 1 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// parsePodLogLines splits the logs of a pod requested with timestamps into lines. A line without a valid timestamp
+// keeps the timestamp of the previous line, so that it stays in place once the lines of several pods are merged.
+func parsePodLogLines(pod, logs string) []podLogLine {
+	var lines []podLogLine
+	var last time.Time
+	for _, raw := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+		if raw == "" {
+			continue
+		}
+
+		line := podLogLine{pod: pod, timestamp: last, raw: raw, message: raw}
+		if value, message, found := strings.Cut(raw, " "); found {
+			if timestamp, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				line.timestamp = timestamp
+				line.message = message
+				last = timestamp
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// formatPodLogs orders the log lines by timestamp and renders them, prefixed by the pod name when the logs of several
+// pods are merged. The timestamps are only kept when requested.
+func formatPodLogs(lines []podLogLine, withPod, timestamps bool) string {
+	if len(lines) == 0 {
+		return "(no logs)"
+	}
+
+	slices.SortStableFunc(lines, func(a, b podLogLine) int {
+		return a.timestamp.Compare(b.timestamp)
+	})
+
+	var sb strings.Builder
+	for _, line := range lines {
+		if withPod {
+			fmt.Fprintf(&sb, "[%s] ", line.pod)
+		}
+		if timestamps {
+			sb.WriteString(stripANSI(line.raw))
+		} else {
+			sb.WriteString(stripANSI(line.message))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// matchKubernetesRequest matches the Kubernetes API requests by method and path
+func matchKubernetesRequest(method, path string) any {
+	return mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+		return opts.Method == method && opts.Path == path
+	})
+}
+
+func TestHandleGetPodLogs(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "single pod",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"pod":           "web-1",
+				"previous":      true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/pods/web-1")).
+					Return(createMockHttpResponse(http.StatusOK, `{"metadata":{"name":"web-1"},"spec":{"containers":[{"name":"web"}]}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/namespaces/shop/pods/web-1/log" &&
+						assert.ObjectsAreEqual(map[string]string{"timestamps": "true", "tailLines": "100", "previous": "true"}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK,
+					"2025-01-01T12:00:00.000000001Z starting\n2025-01-01T12:00:01.5Z panic: nil map\n"), nil)
+			},
+			expectedText: "starting\npanic: nil map\n",
+		},
+		{
+			name: "workload pods merged by timestamp",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "deploy/web",
+				"tailLines":     float64(-1),
+				"timestamps":    true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+					Return(createMockHttpResponse(http.StatusOK, `{"spec":{"selector":{"matchLabels":{"app":"web"}}}}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/namespaces/shop/pods" &&
+						assert.ObjectsAreEqual(map[string]string{"labelSelector": "app=web"}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK, `{"items":[
+					{"metadata":{"name":"web-b","annotations":{"kubectl.kubernetes.io/default-container":"app"}},"spec":{"containers":[{"name":"proxy"},{"name":"app"}]}},
+					{"metadata":{"name":"web-a"},"spec":{"containers":[{"name":"app"},{"name":"proxy"}]}}
+				]}`), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/namespaces/shop/pods/web-a/log" &&
+						assert.ObjectsAreEqual(map[string]string{"timestamps": "true", "container": "app"}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK,
+					"2025-01-01T12:00:00Z GET /\n2025-01-01T12:00:02Z GET /cart\n"), nil)
+				mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+					return opts.Path == "/api/v1/namespaces/shop/pods/web-b/log" && opts.QueryParams["container"] == "app"
+				})).Return(createMockHttpResponse(http.StatusOK, "2025-01-01T12:00:01Z GET /login\n"), nil)
+			},
+			expectedText: "[web-a] 2025-01-01T12:00:00Z GET /\n" +
+				"[web-b] 2025-01-01T12:00:01Z GET /login\n" +
+				"[web-a] 2025-01-01T12:00:02Z GET /cart\n",
+		},
+		{
+			name: "pod whose logs cannot be retrieved",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"labelSelector": "app=web",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/pods")).
+					Return(createMockHttpResponse(http.StatusOK, `{"items":[{"metadata":{"name":"web-a"}},{"metadata":{"name":"web-b"}}]}`), nil)
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/pods/web-a/log")).
+					Return(createMockHttpResponse(http.StatusOK, "2025-01-01T12:00:00Z ready\n"), nil)
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/pods/web-b/log")).
+					Return(createMockHttpResponse(http.StatusBadRequest, `{"kind":"Status","message":"container \"app\" in pod \"web-b\" is waiting to start: ContainerCreating"}`), nil)
+			},
+			expectedText: "[web-a] ready\n\n" +
+				"The logs of some pods could not be retrieved:\n" +
+				"- web-b: HTTP 400 Bad Request: container \"app\" in pod \"web-b\" is waiting to start: ContainerCreating",
+		},
+		{
+			name: "no matching pods",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"labelSelector": "app=missing",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/api/v1/namespaces/shop/pods")).
+					Return(createMockHttpResponse(http.StatusOK, `{"items":[]}`), nil)
+			},
+			expectedText: "No pods found in namespace shop matching app=missing",
+		},
+		{
+			name: "several pod selections",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"pod":           "web-1",
+				"labelSelector": "app=web",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "exactly one of pod, labelSelector or workload must be specified",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleGetPodLogs()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
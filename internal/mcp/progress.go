@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// progressReporter sends progress notifications for a long-running tool call.
+// Nothing is sent when the client did not provide a progress token with the request.
+type progressReporter struct {
+	s     *PortainerMCPServer
+	ctx   context.Context
+	token mcp.ProgressToken
+	last  float64
+}
+
+// newProgressReporter creates a progress reporter for a tool call request
+func (s *PortainerMCPServer) newProgressReporter(ctx context.Context, request mcp.CallToolRequest) *progressReporter {
+	reporter := &progressReporter{s: s, ctx: ctx}
+	if request.Params.Meta != nil {
+		reporter.token = request.Params.Meta.ProgressToken
+	}
+	return reporter
+}
+
+// report sends a progress notification. The progress must increase with each notification,
+// the notifications that do not make progress are dropped. A total of 0 means that the total is unknown.
+func (r *progressReporter) report(progress, total float64, message string) {
+	if r.token == nil || r.s.srv == nil || progress <= r.last {
+		return
+	}
+	r.last = progress
+
+	notification := mcp.NewProgressNotification(r.token, progress, &total, &message)
+	params := map[string]any{
+		"progressToken": notification.Params.ProgressToken,
+		"progress":      notification.Params.Progress,
+		"message":       notification.Params.Message,
+	}
+	if total > 0 {
+		params["total"] = notification.Params.Total
+	}
+
+	if err := r.s.srv.SendNotificationToClient(r.ctx, notification.Method, params); err != nil {
+		log.Debug().Err(err).Msg("failed to send progress notification")
+	}
+}
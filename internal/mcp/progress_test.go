@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReporter(t *testing.T) {
+	srv := server.NewMCPServer("test", "1.0.0")
+	s := &PortainerMCPServer{srv: srv}
+
+	t.Run("without progress token", func(t *testing.T) {
+		session := newFakeLoggingSession("session", mcp.LoggingLevelInfo)
+		ctx := srv.WithContext(context.Background(), session)
+
+		reporter := s.newProgressReporter(ctx, CreateMCPRequest(map[string]any{}))
+		reporter.report(1, 2, "halfway")
+
+		assert.Empty(t, session.received())
+	})
+
+	t.Run("progress must increase", func(t *testing.T) {
+		session := newFakeLoggingSession("session", mcp.LoggingLevelInfo)
+		ctx := srv.WithContext(context.Background(), session)
+
+		request := CreateMCPRequest(map[string]any{})
+		request.Params.Meta = &mcp.Meta{ProgressToken: float64(7)}
+
+		reporter := s.newProgressReporter(ctx, request)
+		reporter.report(1, 0, "first")
+		reporter.report(1, 0, "no progress")
+		reporter.report(3, 4, "third")
+
+		notifications := session.received()
+		require.Len(t, notifications, 2)
+		assert.Equal(t, map[string]any{"progressToken": float64(7), "progress": float64(1), "message": "first"}, notifications[0].Params.AdditionalFields)
+		assert.Equal(t, map[string]any{"progressToken": float64(7), "progress": float64(3), "total": float64(4), "message": "third"}, notifications[1].Params.AdditionalFields)
+	})
+}
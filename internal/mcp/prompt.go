@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+func (s *PortainerMCPServer) AddPromptFeatures() {
+	s.addPromptIfExists(PromptTroubleshootContainer)
+	s.addPromptIfExists(PromptAuditEnvironmentAccess)
+	s.addPromptIfExists(PromptExplainPendingPod)
+
+	if !s.readOnly {
+		s.addPromptIfExists(PromptDeployStackToEnvironmentGroup)
+	}
+}
+
+func (s *PortainerMCPServer) HandleGetPrompt(prompt toolgen.Prompt) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		messages, err := prompt.Render(request.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", prompt.Prompt.Name, err)
+		}
+
+		return mcp.NewGetPromptResult(prompt.Prompt.Description, messages), nil
+	}
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedPromptsAreValid(t *testing.T) {
+	path := t.TempDir() + "/prompts.yaml"
+	_, err := tooldef.CreatePromptsFileIfNotExists(path)
+	require.NoError(t, err)
+
+	prompts, err := toolgen.LoadPromptsFromYAML(path, MinimumPromptsVersion)
+	require.NoError(t, err)
+
+	for _, name := range []string{
+		PromptTroubleshootContainer,
+		PromptAuditEnvironmentAccess,
+		PromptDeployStackToEnvironmentGroup,
+		PromptExplainPendingPod,
+	} {
+		assert.Contains(t, prompts, name)
+	}
+}
+
+func TestHandleGetPrompt(t *testing.T) {
+	path := t.TempDir() + "/prompts.yaml"
+	_, err := tooldef.CreatePromptsFileIfNotExists(path)
+	require.NoError(t, err)
+
+	prompts, err := toolgen.LoadPromptsFromYAML(path, MinimumPromptsVersion)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		prompt        string
+		args          map[string]string
+		expectError   bool
+		expectedTexts []string
+	}{
+		{
+			name:          "troubleshoot container",
+			prompt:        PromptTroubleshootContainer,
+			args:          map[string]string{"environmentId": "3", "containerId": "web"},
+			expectedTexts: []string{`"web"`, "environment with ID 3", "inspectContainer", "getContainerLogs"},
+		},
+		{
+			name:          "deploy stack without stackId",
+			prompt:        PromptDeployStackToEnvironmentGroup,
+			args:          map[string]string{"environmentGroupId": "2", "stackName": "app"},
+			expectedTexts: []string{`no stack named "app"`},
+		},
+		{
+			name:          "deploy stack with stackId",
+			prompt:        PromptDeployStackToEnvironmentGroup,
+			args:          map[string]string{"environmentGroupId": "2", "stackName": "app", "stackId": "7"},
+			expectedTexts: []string{"stack with ID 7"},
+		},
+		{
+			name:          "explain pending pod",
+			prompt:        PromptExplainPendingPod,
+			args:          map[string]string{"environmentId": "1", "namespace": "shop", "podName": "web-1"},
+			expectedTexts: []string{"describeKubernetesResource", "getKubernetesEvents", "involvedObject pod/web-1", "getPodLogs"},
+		},
+		{
+			name:        "missing required argument",
+			prompt:      PromptExplainPendingPod,
+			args:        map[string]string{"environmentId": "1"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &PortainerMCPServer{}
+			handler := server.HandleGetPrompt(prompts[tt.prompt])
+
+			request := mcp.GetPromptRequest{}
+			request.Params.Name = tt.prompt
+			request.Params.Arguments = tt.args
+
+			result, err := handler(context.Background(), request)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 1)
+			textContent, ok := result.Messages[0].Content.(mcp.TextContent)
+			require.True(t, ok)
+			for _, expected := range tt.expectedTexts {
+				assert.Contains(t, textContent.Text, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// proxyResponseHeaders lists the response headers included in the result of the proxy tools
+var proxyResponseHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Location",
+	"Retry-After",
+	"Warning",
+	"Api-Version",
+}
+
+// proxyErrorBody is the error body returned by the Docker daemon, the Kubernetes API (Status object)
+// and Portainer itself
+type proxyErrorBody struct {
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+// proxyResult builds the result of a proxy tool from the upstream response and its body.
+// The first content of the result describes the response status and the selected headers, it is followed
+// by the body (split in chunks if needed). Non-2xx responses are marked as errors and include the upstream
+// error message when it can be found in the body. A failure to buffer the body is returned as a tool error.
+func (s *PortainerMCPServer) proxyResult(ctx context.Context, response *http.Response, body []byte) *mcp.CallToolResult {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "HTTP %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+
+	for _, header := range proxyResponseHeaders {
+		if value := response.Header.Get(header); value != "" {
+			fmt.Fprintf(&sb, "\n%s: %s", header, value)
+		}
+	}
+
+	isError := !isSuccessStatusCode(response.StatusCode)
+	if isError {
+		if message := extractProxyErrorMessage(body); message != "" {
+			fmt.Fprintf(&sb, "\nError: %s", message)
+		}
+	}
+
+	if len(body) == 0 {
+		sb.WriteString("\n(empty response body)")
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.NewTextContent(sb.String())},
+			IsError: isError,
+		}
+	}
+
+	result, err := s.responses.result(ctx, body)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to buffer response", err)
+	}
+
+	result.Content = append([]mcp.Content{mcp.NewTextContent(sb.String())}, result.Content...)
+	result.IsError = isError
+
+	return result
+}
+
+// limitedBody is a response body that fails once more than limit bytes are read from it
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n, fmt.Errorf("the response exceeds %s, narrow the request", formatSize(b.limit))
+	}
+	return n, err
+}
+
+// limitResponseBody limits the size of the upstream response body that can be read to what the
+// response buffer can keep, the body is read in full before being truncated
+func (s *PortainerMCPServer) limitResponseBody(response *http.Response) {
+	response.Body = &limitedBody{
+		ReadCloser: response.Body,
+		limit:      int64(s.responses.maxBodySize()),
+	}
+}
+
+// isSuccessStatusCode checks if an HTTP status code is in the 2xx range
+func isSuccessStatusCode(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// extractProxyErrorMessage extracts the error message from an error response body.
+// It returns an empty string if the body does not contain a known error structure.
+func extractProxyErrorMessage(body []byte) string {
+	var errorBody proxyErrorBody
+	if err := json.Unmarshal(body, &errorBody); err != nil {
+		return ""
+	}
+
+	if errorBody.Details != "" && errorBody.Details != errorBody.Message {
+		if errorBody.Message == "" {
+			return errorBody.Details
+		}
+		return fmt.Sprintf("%s: %s", errorBody.Message, errorBody.Details)
+	}
+
+	return errorBody.Message
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyResultBody returns the body of a proxy tool result, checking that the result starts
+// with the response status
+func proxyResultBody(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	texts := resultTexts(t, result)
+	require.NotEmpty(t, texts)
+	assert.True(t, strings.HasPrefix(texts[0], "HTTP "), "First content should describe the response status")
+
+	if len(texts) < 2 {
+		return ""
+	}
+	return texts[1]
+}
+
+func TestProxyResult(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		headers         http.Header
+		body            string
+		expectedStatus  string
+		expectedBody    string
+		expectedIsError bool
+	}{
+		{
+			name:           "successful response with headers",
+			statusCode:     http.StatusOK,
+			headers:        http.Header{"Content-Type": {"application/json"}, "Api-Version": {"1.48"}, "Server": {"Docker"}},
+			body:           `[{"Id":"123"}]`,
+			expectedStatus: "HTTP 200 OK\nContent-Type: application/json\nApi-Version: 1.48",
+			expectedBody:   `[{"Id":"123"}]`,
+		},
+		{
+			name:           "empty response",
+			statusCode:     http.StatusNoContent,
+			expectedStatus: "HTTP 204 No Content\n(empty response body)",
+		},
+		{
+			name:            "docker error response",
+			statusCode:      http.StatusNotFound,
+			body:            `{"message":"No such container: abc"}`,
+			expectedStatus:  "HTTP 404 Not Found\nError: No such container: abc",
+			expectedBody:    `{"message":"No such container: abc"}`,
+			expectedIsError: true,
+		},
+		{
+			name:            "portainer error response with details",
+			statusCode:      http.StatusForbidden,
+			body:            `{"message":"Access denied","details":"Permission denied to access environment"}`,
+			expectedStatus:  "HTTP 403 Forbidden\nError: Access denied: Permission denied to access environment",
+			expectedBody:    `{"message":"Access denied","details":"Permission denied to access environment"}`,
+			expectedIsError: true,
+		},
+		{
+			name:            "error response without JSON body",
+			statusCode:      http.StatusBadGateway,
+			body:            "bad gateway",
+			expectedStatus:  "HTTP 502 Bad Gateway",
+			expectedBody:    "bad gateway",
+			expectedIsError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &PortainerMCPServer{}
+			response := &http.Response{
+				StatusCode: tt.statusCode,
+				Header:     tt.headers,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			result := server.proxyResult(context.Background(), response, []byte(tt.body))
+
+			texts := resultTexts(t, result)
+			assert.Equal(t, tt.expectedStatus, texts[0])
+			assert.Equal(t, tt.expectedBody, proxyResultBody(t, result))
+			assert.Equal(t, tt.expectedIsError, result.IsError)
+		})
+	}
+}
+
+func TestProxyResultTruncated(t *testing.T) {
+	server := &PortainerMCPServer{responses: newResponseBuffer(4)}
+	response := &http.Response{StatusCode: http.StatusOK}
+
+	result := server.proxyResult(context.Background(), response, []byte("0123456789"))
+
+	texts := resultTexts(t, result)
+	require.Len(t, texts, 3)
+	assert.Equal(t, "HTTP 200 OK", texts[0])
+	assert.Equal(t, "0123", texts[1])
+	assert.Contains(t, texts[2], "response truncated")
+}
+
+func TestLimitResponseBody(t *testing.T) {
+	responses := newResponseBuffer(4)
+	responses.maxBytes = 8
+	server := &PortainerMCPServer{responses: responses}
+
+	response := &http.Response{Body: io.NopCloser(strings.NewReader("01234567"))}
+	server.limitResponseBody(response)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "01234567", string(body))
+
+	response = &http.Response{Body: io.NopCloser(strings.NewReader("0123456789"))}
+	server.limitResponseBody(response)
+	_, err = io.ReadAll(response.Body)
+	assert.ErrorContains(t, err, "the response exceeds 8B, narrow the request")
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+)
+
+// pruneCandidate is a Docker resource that would be removed by a prune
+type pruneCandidate struct {
+	name string
+	// size is the space reclaimed by removing the resource, negative when unknown
+	size int64
+}
+
+// formatPrunePreview renders the resources that would be removed by a prune tool called with confirm,
+// with the total space that would be reclaimed
+func formatPrunePreview(tool, resource string, candidates []pruneCandidate) string {
+	if len(candidates) == 0 {
+		return fmt.Sprintf("Dry run: nothing to prune, no %s would be removed.", pluralize(0, resource))
+	}
+
+	var total int64
+	unknownSize := false
+	for _, candidate := range candidates {
+		if candidate.size < 0 {
+			unknownSize = true
+			continue
+		}
+		total += candidate.size
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Dry run: %d %s would be removed", len(candidates), pluralize(len(candidates), resource))
+	switch {
+	case total > 0 && unknownSize:
+		fmt.Fprintf(&sb, ", reclaiming at least %s", formatSize(total))
+	case total > 0:
+		fmt.Fprintf(&sb, ", reclaiming %s", formatSize(total))
+	}
+	fmt.Fprintf(&sb, ". Nothing was removed, call %s again with confirm set to true to remove them.\n\n", tool)
+
+	for _, candidate := range candidates {
+		if candidate.size > 0 {
+			fmt.Fprintf(&sb, "- %s (%s)\n", candidate.name, formatSize(candidate.size))
+		} else {
+			fmt.Fprintf(&sb, "- %s\n", candidate.name)
+		}
+	}
+
+	return sb.String()
+}
+
+// formatPruneReport renders the resources removed by a prune and the space reclaimed
+func formatPruneReport(resource string, deleted []string, spaceReclaimed uint64) string {
+	if len(deleted) == 0 && spaceReclaimed == 0 {
+		return fmt.Sprintf("Nothing to prune, no %s were removed.", pluralize(0, resource))
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Removed %d %s", len(deleted), pluralize(len(deleted), resource))
+	if spaceReclaimed > 0 {
+		fmt.Fprintf(&sb, ", reclaimed %s", formatSize(int64(spaceReclaimed)))
+	}
+	sb.WriteString(".\n")
+
+	if len(deleted) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, name := range deleted {
+		fmt.Fprintf(&sb, "- %s\n", name)
+	}
+
+	return sb.String()
+}
+
+// formatSize formats a size in bytes like the docker CLI does
+func formatSize(size int64) string {
+	return units.HumanSizeWithPrecision(float64(size), 3)
+}
+
+// pluralize returns the plural of a resource name when the count is not 1
+func pluralize(count int, resource string) string {
+	if count == 1 {
+		return resource
+	}
+	return resource + "s"
+}
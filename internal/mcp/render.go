@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats supported by the list tools
+const (
+	// OutputFormatJSON renders the items as a JSON array of objects
+	OutputFormatJSON = "json"
+	// OutputFormatCompactJSON renders the items as a JSON object with the column names
+	// and one array of values per item, so that the keys are not repeated
+	OutputFormatCompactJSON = "compact-json"
+	// OutputFormatMarkdown renders the items as a markdown table
+	OutputFormatMarkdown = "markdown"
+	// OutputFormatCSV renders the items as CSV with a header row
+	OutputFormatCSV = "csv"
+	// OutputFormatYAML renders the items as a YAML sequence of mappings
+	OutputFormatYAML = "yaml"
+)
+
+// AllOutputFormats lists all the supported output formats
+var AllOutputFormats = []string{
+	OutputFormatJSON,
+	OutputFormatCompactJSON,
+	OutputFormatMarkdown,
+	OutputFormatCSV,
+	OutputFormatYAML,
+}
+
+// outputOptions holds the output format and the fields selected by the user
+type outputOptions struct {
+	format string
+	fields []string
+}
+
+// compactJSONOutput is the structure rendered by the compact-json output format
+type compactJSONOutput struct {
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// parseOutputOptions parses the outputFormat and fields parameters of a list tool
+func parseOutputOptions(parser *toolgen.ParameterParser) (outputOptions, error) {
+	format, err := parser.GetString("outputFormat", false)
+	if err != nil {
+		return outputOptions{}, err
+	}
+	if format == "" {
+		format = OutputFormatJSON
+	}
+	if !slices.Contains(AllOutputFormats, format) {
+		return outputOptions{}, fmt.Errorf("invalid output format: %s", format)
+	}
+
+	fields, err := parser.GetArrayOfStrings("fields", false)
+	if err != nil {
+		return outputOptions{}, err
+	}
+
+	return outputOptions{format: format, fields: fields}, nil
+}
+
+// isJSON returns true if the output format produces JSON
+func (o outputOptions) isJSON() bool {
+	return o.format == OutputFormatJSON || o.format == OutputFormatCompactJSON
+}
+
+// render renders a slice of items (structs with json tags) using the output options.
+// Nested values (arrays, objects) are rendered as compact JSON in the tabular formats.
+func (o outputOptions) render(items any) (string, error) {
+	if o.format == OutputFormatJSON && len(o.fields) == 0 {
+		data, err := json.Marshal(items)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	columns, err := listColumns(items)
+	if err != nil {
+		return "", err
+	}
+
+	if len(o.fields) > 0 {
+		for _, field := range o.fields {
+			if !slices.Contains(columns, field) {
+				return "", fmt.Errorf("invalid field: %s, available fields are: %s", field, strings.Join(columns, ", "))
+			}
+		}
+		columns = o.fields
+	}
+
+	rows, err := listRows(items, columns)
+	if err != nil {
+		return "", err
+	}
+
+	switch o.format {
+	case OutputFormatCompactJSON:
+		data, err := json.Marshal(compactJSONOutput{Columns: columns, Rows: rows})
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case OutputFormatMarkdown:
+		return renderMarkdownTable(columns, rows), nil
+	case OutputFormatCSV:
+		return renderCSV(columns, rows)
+	case OutputFormatYAML:
+		return renderYAML(columns, rows)
+	default:
+		return renderJSONObjects(columns, rows)
+	}
+}
+
+// listColumns returns the JSON field names of the element type of a slice of structs
+func listColumns(items any) ([]string, error) {
+	itemsType := reflect.TypeOf(items)
+	if itemsType == nil || itemsType.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("items must be a slice")
+	}
+
+	elemType := itemsType.Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("items must be a slice of structs")
+	}
+
+	columns := make([]string, 0, elemType.NumField())
+	for i := range elemType.NumField() {
+		field := elemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, nil
+}
+
+// listRows returns the values of the given columns for each item
+func listRows(items any, columns []string) ([][]any, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]any
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, len(objects))
+	for i, object := range objects {
+		row := make([]any, len(columns))
+		for j, column := range columns {
+			row[j] = object[column]
+		}
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+// formatCell formats a value for the tabular output formats
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// renderMarkdownTable renders the rows as a markdown table
+func renderMarkdownTable(columns []string, rows [][]any) string {
+	var sb strings.Builder
+
+	sb.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cell := strings.ReplaceAll(formatCell(value), "|", "\\|")
+			cells[i] = strings.ReplaceAll(cell, "\n", " ")
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return sb.String()
+}
+
+// renderCSV renders the rows as CSV with a header row
+func renderCSV(columns []string, rows [][]any) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(columns); err != nil {
+		return "", err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatCell(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+	return buf.String(), writer.Error()
+}
+
+// renderYAML renders the rows as a YAML sequence of mappings, keeping the column order
+func renderYAML(columns []string, rows [][]any) (string, error) {
+	sequence := &yaml.Node{Kind: yaml.SequenceNode}
+
+	for _, row := range rows {
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for i, value := range row {
+			valueNode := &yaml.Node{}
+			if err := valueNode.Encode(value); err != nil {
+				return "", err
+			}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: columns[i]}, valueNode)
+		}
+		sequence.Content = append(sequence.Content, mapping)
+	}
+
+	data, err := yaml.Marshal(sequence)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// renderJSONObjects renders the rows as a JSON array of objects limited to the columns. The keys are
+// written by hand to keep the column order, a map would sort them.
+func renderJSONObjects(columns []string, rows [][]any) (string, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('[')
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, column := range columns {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(column)
+			if err != nil {
+				return "", err
+			}
+			value, err := json.Marshal(row[j])
+			if err != nil {
+				return "", err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	return buf.String(), nil
+}
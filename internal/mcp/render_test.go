@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type renderTestItem struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	TagIds []int    `json:"tag_ids"`
+	Notes  string   `json:"-"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+func TestParseOutputOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expected      outputOptions
+		expectedError string
+	}{
+		{
+			name:     "defaults to json",
+			args:     map[string]any{},
+			expected: outputOptions{format: OutputFormatJSON, fields: []string{}},
+		},
+		{
+			name:     "format and fields",
+			args:     map[string]any{"outputFormat": "csv", "fields": []any{"id"}},
+			expected: outputOptions{format: OutputFormatCSV, fields: []string{"id"}},
+		},
+		{
+			name:          "invalid format",
+			args:          map[string]any{"outputFormat": "xml"},
+			expectedError: "invalid output format: xml",
+		},
+		{
+			name:          "invalid fields type",
+			args:          map[string]any{"fields": "id"},
+			expectedError: "fields must be an array",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := parseOutputOptions(toolgen.NewParameterParser(CreateMCPRequest(tt.args)))
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, options)
+		})
+	}
+}
+
+func TestOutputOptionsRender(t *testing.T) {
+	items := []renderTestItem{
+		{ID: 1, Name: "env|1", TagIds: []int{1, 2}, Notes: "hidden"},
+		{ID: 2, Name: "env,2", TagIds: []int{}},
+	}
+
+	tests := []struct {
+		name          string
+		options       outputOptions
+		items         any
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "json without fields keeps the full objects",
+			options:  outputOptions{format: OutputFormatJSON},
+			items:    items,
+			expected: `[{"id":1,"name":"env|1","tag_ids":[1,2]},{"id":2,"name":"env,2","tag_ids":[]}]`,
+		},
+		{
+			name:     "json with fields",
+			options:  outputOptions{format: OutputFormatJSON, fields: []string{"name"}},
+			items:    items,
+			expected: `[{"name":"env|1"},{"name":"env,2"}]`,
+		},
+		{
+			name:     "json with fields keeps the field order",
+			options:  outputOptions{format: OutputFormatJSON, fields: []string{"tag_ids", "name", "id"}},
+			items:    items,
+			expected: `[{"tag_ids":[1,2],"name":"env|1","id":1},{"tag_ids":[],"name":"env,2","id":2}]`,
+		},
+		{
+			name:     "json with fields and no items",
+			options:  outputOptions{format: OutputFormatJSON, fields: []string{"name"}},
+			items:    []renderTestItem{},
+			expected: `[]`,
+		},
+		{
+			name:     "compact json",
+			options:  outputOptions{format: OutputFormatCompactJSON, fields: []string{"id", "tag_ids"}},
+			items:    items,
+			expected: `{"columns":["id","tag_ids"],"rows":[[1,[1,2]],[2,[]]]}`,
+		},
+		{
+			name:    "markdown",
+			options: outputOptions{format: OutputFormatMarkdown},
+			items:   items,
+			expected: "| id | name | tag_ids | labels |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| 1 | env\\|1 | [1,2] |  |\n" +
+				"| 2 | env,2 | [] |  |\n",
+		},
+		{
+			name:     "csv",
+			options:  outputOptions{format: OutputFormatCSV, fields: []string{"name", "id"}},
+			items:    items,
+			expected: "name,id\nenv|1,1\n\"env,2\",2\n",
+		},
+		{
+			name:     "yaml keeps the field order",
+			options:  outputOptions{format: OutputFormatYAML, fields: []string{"name", "id"}},
+			items:    items,
+			expected: "- name: env|1\n  id: 1\n- name: env,2\n  id: 2\n",
+		},
+		{
+			name:     "pointer elements",
+			options:  outputOptions{format: OutputFormatCSV, fields: []string{"id"}},
+			items:    []*renderTestItem{{ID: 3}},
+			expected: "id\n3\n",
+		},
+		{
+			name:          "unknown field",
+			options:       outputOptions{format: OutputFormatCSV, fields: []string{"owner"}},
+			items:         items,
+			expectedError: "invalid field: owner, available fields are: id, name, tag_ids, labels",
+		},
+		{
+			name:          "not a slice of structs",
+			options:       outputOptions{format: OutputFormatCSV},
+			items:         []string{"a"},
+			expectedError: "items must be a slice of structs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.options.render(tt.items)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// DefaultMaxResponseSize is the default maximum size in bytes of a proxy response returned in a single tool result
+	DefaultMaxResponseSize = 100 * 1024
+	// defaultResponseBufferTTL is how long the remaining part of a truncated response is kept
+	defaultResponseBufferTTL = 5 * time.Minute
+	// defaultResponseBufferMaxEntries is the maximum number of truncated responses kept at once
+	defaultResponseBufferMaxEntries = 50
+	// defaultResponseBufferMaxBytes is the maximum total size in bytes of the truncated responses kept at once
+	defaultResponseBufferMaxBytes = 64 * 1024 * 1024
+)
+
+// bufferedResponse is the remaining part of a truncated response
+type bufferedResponse struct {
+	data      []byte
+	offset    int
+	expiresAt time.Time
+	// sessionID is the ID of the MCP session the response was returned to, only this session can fetch its chunks
+	sessionID string
+	// seq orders the responses by insertion, the oldest are evicted first when the buffer is full
+	seq uint64
+}
+
+// responseBuffer splits the responses larger than the maximum response size into chunks.
+// The first chunk is returned directly and the remaining chunks are kept in memory for a
+// short time, to be fetched with the continuation token returned alongside the first chunk
+// by the same MCP session. The number and the total size of the kept responses are bounded, the oldest responses are
+// evicted to make room for new ones.
+type responseBuffer struct {
+	mu         sync.Mutex
+	responses  map[string]*bufferedResponse
+	maxSize    int
+	maxEntries int
+	maxBytes   int
+	totalBytes int
+	seq        uint64
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+// newResponseBuffer creates a new responseBuffer.
+// A maxSize of 0 or less disables the truncation.
+func newResponseBuffer(maxSize int) *responseBuffer {
+	return &responseBuffer{
+		responses:  map[string]*bufferedResponse{},
+		maxSize:    maxSize,
+		maxEntries: defaultResponseBufferMaxEntries,
+		maxBytes:   defaultResponseBufferMaxBytes,
+		ttl:        defaultResponseBufferTTL,
+		now:        time.Now,
+	}
+}
+
+// result returns a tool result containing the data, or its first chunk if the data
+// exceeds the maximum response size. A nil buffer returns the data as is.
+// The remaining chunks are bound to the MCP session of the context.
+func (b *responseBuffer) result(ctx context.Context, data []byte) (*mcp.CallToolResult, error) {
+	if b == nil || b.maxSize <= 0 || len(data) <= b.maxSize {
+		return mcp.NewToolResultText(string(data)), nil
+	}
+
+	// A response larger than the whole buffer cannot be kept, only its first chunk is returned
+	if len(data) > b.maxBytes {
+		end := b.chunkEnd(data, 0)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.NewTextContent(string(data[:end])),
+				mcp.NewTextContent(fmt.Sprintf(
+					"[response truncated: bytes 0-%d of %d returned. The response is too large to be fetched in chunks, narrow the request to get the rest]",
+					end, len(data),
+				)),
+			},
+		}, nil
+	}
+
+	token, err := newContinuationToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate continuation token: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeExpired()
+
+	b.seq++
+	response := &bufferedResponse{
+		data:      data,
+		expiresAt: b.now().Add(b.ttl),
+		sessionID: sessionIDFromContext(ctx),
+		seq:       b.seq,
+	}
+	b.responses[token] = response
+	b.totalBytes += len(data)
+	b.evictOldest()
+
+	return b.nextChunk(token, response), nil
+}
+
+// next returns a tool result containing the next chunk of a truncated response.
+// The response must have been returned to the MCP session of the context.
+func (b *responseBuffer) next(ctx context.Context, token string) (*mcp.CallToolResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeExpired()
+
+	response, exists := b.responses[token]
+	if !exists || response.sessionID != sessionIDFromContext(ctx) {
+		return nil, fmt.Errorf("unknown or expired continuation token: %s", token)
+	}
+
+	return b.nextChunk(token, response), nil
+}
+
+// maxBodySize returns the maximum size in bytes of an upstream response body that can be read.
+// Larger bodies could not be kept in the buffer.
+func (b *responseBuffer) maxBodySize() int {
+	if b == nil || b.maxBytes <= 0 {
+		return defaultResponseBufferMaxBytes
+	}
+	return b.maxBytes
+}
+
+// chunkEnd returns the end offset of the chunk of data starting at start, without
+// splitting a multi-byte UTF-8 character across two chunks when possible
+func (b *responseBuffer) chunkEnd(data []byte, start int) int {
+	end := min(start+b.maxSize, len(data))
+	for end < len(data) && end > start && !utf8.RuneStart(data[end]) {
+		end--
+	}
+	if end == start {
+		end = min(start+b.maxSize, len(data))
+	}
+	return end
+}
+
+// nextChunk returns the chunk of the response starting at its current offset and advances the offset.
+// The response is removed from the buffer once its last chunk is returned.
+// It must be called with the lock held.
+func (b *responseBuffer) nextChunk(token string, response *bufferedResponse) *mcp.CallToolResult {
+	start := response.offset
+	end := b.chunkEnd(response.data, start)
+
+	response.offset = end
+	chunk := string(response.data[start:end])
+
+	if end >= len(response.data) {
+		b.remove(token)
+		if start == 0 {
+			return mcp.NewToolResultText(chunk)
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.NewTextContent(chunk),
+				mcp.NewTextContent(fmt.Sprintf("[end of response: bytes %d-%d of %d]", start, end, len(response.data))),
+			},
+		}
+	}
+
+	response.expiresAt = b.now().Add(b.ttl)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(chunk),
+			mcp.NewTextContent(fmt.Sprintf(
+				"[response truncated: bytes %d-%d of %d returned. Call %s with continuationToken %q to fetch the next chunk, the token expires in %s]",
+				start, end, len(response.data), ToolGetResponseChunk, token, b.ttl,
+			)),
+		},
+	}
+}
+
+// removeExpired removes the expired responses from the buffer.
+// It must be called with the lock held.
+func (b *responseBuffer) removeExpired() {
+	now := b.now()
+	for token, response := range b.responses {
+		if now.After(response.expiresAt) {
+			b.remove(token)
+		}
+	}
+}
+
+// evictOldest removes the oldest responses until the buffer is within its limits.
+// It must be called with the lock held.
+func (b *responseBuffer) evictOldest() {
+	for len(b.responses) > 0 && (len(b.responses) > b.maxEntries || b.totalBytes > b.maxBytes) {
+		oldestToken := ""
+		var oldestSeq uint64
+		for token, response := range b.responses {
+			if oldestToken == "" || response.seq < oldestSeq {
+				oldestToken, oldestSeq = token, response.seq
+			}
+		}
+		b.remove(oldestToken)
+	}
+}
+
+// remove removes a response from the buffer.
+// It must be called with the lock held.
+func (b *responseBuffer) remove(token string) {
+	if response, exists := b.responses[token]; exists {
+		b.totalBytes -= len(response.data)
+		delete(b.responses, token)
+	}
+}
+
+// sessionIDFromContext returns the ID of the MCP session of the context, or an empty string
+// when the context has no session
+func sessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// newContinuationToken generates a random continuation token
+func newContinuationToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func (s *PortainerMCPServer) HandleGetResponseChunk() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		token, err := parser.GetString("continuationToken", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid continuationToken parameter", err), nil
+		}
+
+		if s.responses == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown or expired continuation token: %s", token)), nil
+		}
+
+		result, err := s.responses.next(ctx, token)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get response chunk", err), nil
+		}
+
+		return result, nil
+	}
+}
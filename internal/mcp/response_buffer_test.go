@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var continuationTokenPattern = regexp.MustCompile(`continuationToken "([0-9a-f]+)"`)
+
+func resultTexts(t *testing.T, result *mcp.CallToolResult) []string {
+	t.Helper()
+
+	texts := make([]string, len(result.Content))
+	for i, content := range result.Content {
+		textContent, ok := content.(mcp.TextContent)
+		require.True(t, ok, "Result content should be mcp.TextContent")
+		texts[i] = textContent.Text
+	}
+	return texts
+}
+
+func continuationToken(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	texts := resultTexts(t, result)
+	require.Len(t, texts, 2, "Truncated result should contain the chunk and the truncation note")
+
+	matches := continuationTokenPattern.FindStringSubmatch(texts[1])
+	require.Len(t, matches, 2, "Truncation note should contain the continuation token")
+	return matches[1]
+}
+
+func TestResponseBufferResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxSize  int
+		data     string
+		expected []string
+	}{
+		{
+			name:     "response within the limit",
+			maxSize:  10,
+			data:     "0123456789",
+			expected: []string{"0123456789"},
+		},
+		{
+			name:     "response split in chunks",
+			maxSize:  4,
+			data:     "0123456789",
+			expected: []string{"0123", "4567", "89"},
+		},
+		{
+			name:     "truncation disabled",
+			maxSize:  0,
+			data:     "0123456789",
+			expected: []string{"0123456789"},
+		},
+		{
+			name:     "multi-byte characters are not split",
+			maxSize:  4,
+			data:     "abcé€",
+			expected: []string{"abc", "é", "€"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newResponseBuffer(tt.maxSize)
+
+			result, err := buffer.result(context.Background(), []byte(tt.data))
+			require.NoError(t, err)
+
+			var chunks []string
+			for {
+				texts := resultTexts(t, result)
+				chunks = append(chunks, texts[0])
+
+				if len(texts) == 1 || strings.HasPrefix(texts[1], "[end of response") {
+					break
+				}
+
+				result, err = buffer.next(context.Background(), continuationToken(t, result))
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expected, chunks)
+			assert.Empty(t, buffer.responses, "Buffer should be empty once the last chunk is returned")
+		})
+	}
+}
+
+func TestResponseBufferExpiration(t *testing.T) {
+	now := time.Now()
+	buffer := newResponseBuffer(4)
+	buffer.now = func() time.Time { return now }
+
+	result, err := buffer.result(context.Background(), []byte("0123456789"))
+	require.NoError(t, err)
+	token := continuationToken(t, result)
+
+	now = now.Add(defaultResponseBufferTTL + time.Second)
+
+	_, err = buffer.next(context.Background(), token)
+	assert.ErrorContains(t, err, "unknown or expired continuation token")
+	assert.Empty(t, buffer.responses)
+}
+
+func TestResponseBufferEviction(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxEntries    int
+		maxBytes      int
+		responses     []string
+		expectedKept  []int
+		expectedBytes int
+	}{
+		{
+			name:          "entry count limit",
+			maxEntries:    2,
+			maxBytes:      1024,
+			responses:     []string{"0123456789", "abcdefghij", "ABCDEFGHIJ"},
+			expectedKept:  []int{1, 2},
+			expectedBytes: 20,
+		},
+		{
+			name:          "total size limit",
+			maxEntries:    10,
+			maxBytes:      25,
+			responses:     []string{"0123456789", "abcdefghij", "ABCDEFGHIJ"},
+			expectedKept:  []int{1, 2},
+			expectedBytes: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newResponseBuffer(4)
+			buffer.maxEntries = tt.maxEntries
+			buffer.maxBytes = tt.maxBytes
+
+			tokens := make([]string, len(tt.responses))
+			for i, response := range tt.responses {
+				result, err := buffer.result(context.Background(), []byte(response))
+				require.NoError(t, err)
+				tokens[i] = continuationToken(t, result)
+			}
+
+			for i, token := range tokens {
+				_, exists := buffer.responses[token]
+				assert.Equal(t, slices.Contains(tt.expectedKept, i), exists, "response %d", i)
+			}
+			assert.Equal(t, tt.expectedBytes, buffer.totalBytes)
+
+			// The evicted responses cannot be fetched anymore
+			_, err := buffer.next(context.Background(), tokens[0])
+			assert.ErrorContains(t, err, "unknown or expired continuation token")
+		})
+	}
+}
+
+func TestResponseBufferResponseLargerThanBuffer(t *testing.T) {
+	buffer := newResponseBuffer(4)
+	buffer.maxBytes = 8
+
+	result, err := buffer.result(context.Background(), []byte("01234567"))
+	require.NoError(t, err)
+	continuationToken(t, result)
+
+	// A response larger than the whole buffer is not kept, it does not evict the others either
+	result, err = buffer.result(context.Background(), []byte("0123456789"))
+	require.NoError(t, err)
+	texts := resultTexts(t, result)
+	require.Len(t, texts, 2)
+	assert.Equal(t, "0123", texts[0])
+	assert.Contains(t, texts[1], "too large to be fetched in chunks")
+	assert.NotRegexp(t, continuationTokenPattern, texts[1])
+	assert.Len(t, buffer.responses, 1)
+	assert.Equal(t, 8, buffer.totalBytes)
+}
+
+func TestResponseBufferSession(t *testing.T) {
+	buffer := newResponseBuffer(4)
+	srv := &server.MCPServer{}
+	ctx := srv.WithContext(context.Background(), newFakeLoggingSession("owner", mcp.LoggingLevelInfo))
+	otherCtx := srv.WithContext(context.Background(), newFakeLoggingSession("other", mcp.LoggingLevelInfo))
+
+	result, err := buffer.result(ctx, []byte("0123456789"))
+	require.NoError(t, err)
+	token := continuationToken(t, result)
+
+	_, err = buffer.next(otherCtx, token)
+	assert.ErrorContains(t, err, "unknown or expired continuation token")
+
+	_, err = buffer.next(context.Background(), token)
+	assert.ErrorContains(t, err, "unknown or expired continuation token")
+
+	result, err = buffer.next(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "4567", resultTexts(t, result)[0])
+}
+
+func TestNilResponseBufferResult(t *testing.T) {
+	var buffer *responseBuffer
+
+	result, err := buffer.result(context.Background(), []byte("0123456789"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0123456789"}, resultTexts(t, result))
+}
+
+func TestHandleGetResponseChunk(t *testing.T) {
+	server := &PortainerMCPServer{responses: newResponseBuffer(4)}
+
+	result, err := server.responses.result(context.Background(), []byte("0123456789"))
+	require.NoError(t, err)
+	token := continuationToken(t, result)
+
+	handler := server.HandleGetResponseChunk()
+
+	result, err = handler(context.Background(), CreateMCPRequest(map[string]any{"continuationToken": token}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "4567", resultTexts(t, result)[0])
+
+	result, err = handler(context.Background(), CreateMCPRequest(map[string]any{"continuationToken": "unknown"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultTexts(t, result)[0], "unknown or expired continuation token")
+
+	result, err = handler(context.Background(), CreateMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultTexts(t, result)[0], "continuationToken is required")
+}
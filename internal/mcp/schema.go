@@ -37,8 +37,57 @@ const (
 	ToolUpdateEnvironmentGroupEnvironments = "updateEnvironmentGroupEnvironments"
 	ToolUpdateEnvironmentGroupTags         = "updateEnvironmentGroupTags"
 	ToolDockerProxy                        = "dockerProxy"
+	ToolDockerProxyStripped                = "getDockerResourceStripped"
 	ToolKubernetesProxy                    = "kubernetesProxy"
 	ToolKubernetesProxyStripped            = "getKubernetesResourceStripped"
+	ToolGetResponseChunk                   = "getResponseChunk"
+	ToolGetContainerLogs                   = "getContainerLogs"
+	ToolListContainers                     = "listContainers"
+	ToolInspectContainer                   = "inspectContainer"
+	ToolStartContainer                     = "startContainer"
+	ToolStopContainer                      = "stopContainer"
+	ToolRestartContainer                   = "restartContainer"
+	ToolRemoveContainer                    = "removeContainer"
+	ToolContainerTop                       = "containerTop"
+	ToolGetContainerStats                  = "getContainerStats"
+	ToolPruneContainers                    = "pruneContainers"
+	ToolListContainerFiles                 = "listContainerFiles"
+	ToolReadContainerFile                  = "readContainerFile"
+	ToolExecInContainer                    = "execInContainer"
+	ToolPullImage                          = "pullImage"
+	ToolListImages                         = "listImages"
+	ToolPruneImages                        = "pruneImages"
+	ToolListVolumes                        = "listVolumes"
+	ToolPruneVolumes                       = "pruneVolumes"
+	ToolListNetworks                       = "listNetworks"
+	ToolPruneNetworks                      = "pruneNetworks"
+	ToolListServices                       = "listServices"
+	ToolListServiceTasks                   = "listServiceTasks"
+	ToolScaleService                       = "scaleService"
+	ToolForceUpdateService                 = "forceUpdateService"
+	ToolListNodes                          = "listNodes"
+	ToolUpdateNodeAvailability             = "updateNodeAvailability"
+	ToolGetPodLogs                         = "getPodLogs"
+	ToolApplyKubernetesManifest            = "applyKubernetesManifest"
+	ToolDescribeKubernetesResource         = "describeKubernetesResource"
+	ToolGetKubernetesEvents                = "getKubernetesEvents"
+	ToolScaleWorkload                      = "scaleWorkload"
+	ToolRestartWorkload                    = "restartWorkload"
+	ToolGetRolloutStatus                   = "getRolloutStatus"
+	ToolUndoRollout                        = "undoRollout"
+	ToolListHelmReleases                   = "listHelmReleases"
+	ToolGetHelmReleaseValues               = "getHelmReleaseValues"
+	ToolGetHelmReleaseHistory              = "getHelmReleaseHistory"
+	ToolInstallHelmChart                   = "installHelmChart"
+	ToolUninstallHelmRelease               = "uninstallHelmRelease"
+)
+
+// Prompt names as defined in the prompts YAML file
+const (
+	PromptTroubleshootContainer         = "troubleshootContainer"
+	PromptAuditEnvironmentAccess        = "auditEnvironmentAccess"
+	PromptDeployStackToEnvironmentGroup = "deployStackToEnvironmentGroup"
+	PromptExplainPendingPod             = "explainPendingPod"
 )
 
 // Access levels for users and teams
@@ -1,20 +1,24 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/k8sutil"
 	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/rs/zerolog/log"
 )
 
 const (
 	// MinimumToolsVersion is the minimum supported version of the tools.yaml file
 	MinimumToolsVersion = "1.0"
+	// MinimumPromptsVersion is the minimum supported version of the prompts.yaml file
+	MinimumPromptsVersion = "1.0"
 	// SupportedPortainerVersion is the version of Portainer that is supported by this tool
 	SupportedPortainerVersion = "2.31.2"
 )
@@ -27,6 +31,7 @@ type PortainerClient interface {
 
 	// Environment methods
 	GetEnvironments() ([]models.Environment, error)
+	GetEnvironmentsWithFilter(filter models.EnvironmentFilter) ([]models.Environment, int, error)
 	UpdateEnvironmentTags(id int, tagIds []int) error
 	UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error
 	UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error
@@ -74,15 +79,29 @@ type PortainerClient interface {
 
 	// Kubernetes Proxy methods
 	ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error)
+
+	// Helm methods
+	GetHelmReleases(environmentId int, namespace string) ([]models.HelmRelease, error)
+	GetHelmReleaseValues(environmentId int, name string, namespace string, revision int) (models.HelmReleaseValues, error)
+	GetHelmReleaseHistory(environmentId int, name string, namespace string) ([]models.HelmRelease, error)
+	InstallHelmChart(environmentId int, opts models.HelmInstallOptions) (models.HelmRelease, error)
+	UninstallHelmRelease(environmentId int, name string, namespace string) error
+
+	// Registry methods
+	GetRegistries() ([]models.Registry, error)
 }
 
 // PortainerMCPServer is the main server that handles MCP protocol communication
 // with AI assistants and translates them into Portainer API calls.
 type PortainerMCPServer struct {
-	srv      *server.MCPServer
-	cli      PortainerClient
-	tools    map[string]mcp.Tool
-	readOnly bool
+	srv           *server.MCPServer
+	cli           PortainerClient
+	tools         map[string]mcp.Tool
+	prompts       map[string]toolgen.Prompt
+	responses     *responseBuffer
+	profiles      map[string]k8sutil.StrippingProfile
+	readOnly      bool
+	execAllowlist []string
 }
 
 // ServerOption is a function that configures the server
@@ -94,6 +113,11 @@ type serverOptions struct {
 	readOnly            bool
 	disableVersionCheck bool
 	basePath            string
+	promptsPath         string
+	logForwarder        *LogForwarder
+	maxResponseSize     *int
+	profilesPath        string
+	execAllowlist       []string
 }
 
 // WithClient sets a custom client for the server.
@@ -129,6 +153,48 @@ func WithBasePath(basePath string) ServerOption {
 	}
 }
 
+// WithPromptsPath sets the path to the prompts.yaml file that defines the available MCP prompts.
+// No prompts are registered if not specified.
+func WithPromptsPath(promptsPath string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.promptsPath = promptsPath
+	}
+}
+
+// WithLogForwarder sets the log forwarder used to send the server logs to the MCP clients.
+// The forwarder must also be used as an output of the zerolog logger.
+func WithLogForwarder(forwarder *LogForwarder) ServerOption {
+	return func(opts *serverOptions) {
+		opts.logForwarder = forwarder
+	}
+}
+
+// WithMaxResponseSize sets the maximum size in bytes of a proxy response returned in a single tool result.
+// Larger responses are truncated and the remaining chunks can be fetched with a continuation token.
+// The default is DefaultMaxResponseSize, a size of 0 disables the truncation.
+func WithMaxResponseSize(maxResponseSize int) ServerOption {
+	return func(opts *serverOptions) {
+		opts.maxResponseSize = &maxResponseSize
+	}
+}
+
+// WithStrippingProfilesPath sets the path to a YAML file defining custom stripping profiles
+// for the Kubernetes responses, on top of the built-in ones (minimal, standard and summary).
+func WithStrippingProfilesPath(profilesPath string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.profilesPath = profilesPath
+	}
+}
+
+// WithExecAllowlist sets the commands that can be run in the containers by the execInContainer tool.
+// A command is allowed when the name of its executable is in the list, * allows any command.
+// The default is DefaultExecAllowlist.
+func WithExecAllowlist(commands []string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.execAllowlist = commands
+	}
+}
+
 // NewPortainerMCPServer creates a new Portainer MCP server.
 //
 // This server provides an implementation of the MCP protocol for Portainer,
@@ -146,6 +212,8 @@ func WithBasePath(basePath string) ServerOption {
 //
 // Possible errors:
 //   - Failed to load tools from the specified path
+//   - Failed to load prompts from the specified path
+//   - Failed to load stripping profiles from the specified path
 //   - Failed to communicate with the Portainer server
 //   - Incompatible Portainer server version
 func NewPortainerMCPServer(serverURL, token, toolsPath string, options ...ServerOption) (*PortainerMCPServer, error) {
@@ -160,6 +228,22 @@ func NewPortainerMCPServer(serverURL, token, toolsPath string, options ...Server
 		return nil, fmt.Errorf("failed to load tools: %w", err)
 	}
 
+	prompts := map[string]toolgen.Prompt{}
+	if opts.promptsPath != "" {
+		prompts, err = toolgen.LoadPromptsFromYAML(opts.promptsPath, MinimumPromptsVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load prompts: %w", err)
+		}
+	}
+
+	profiles := k8sutil.BuiltinStrippingProfiles()
+	if opts.profilesPath != "" {
+		profiles, err = k8sutil.LoadStrippingProfilesFromYAML(opts.profilesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load stripping profiles: %w", err)
+		}
+	}
+
 	var portainerClient PortainerClient
 	if opts.client != nil {
 		portainerClient = opts.client
@@ -181,19 +265,48 @@ func NewPortainerMCPServer(serverURL, token, toolsPath string, options ...Server
 		if version != SupportedPortainerVersion {
 			return nil, fmt.Errorf("unsupported Portainer server version: %s, only version %s is supported", version, SupportedPortainerVersion)
 		}
+	} else {
+		log.Warn().Msg("Portainer server version check is disabled, unsupported versions may not work as expected")
+	}
+
+	maxResponseSize := DefaultMaxResponseSize
+	if opts.maxResponseSize != nil {
+		maxResponseSize = *opts.maxResponseSize
 	}
 
-	return &PortainerMCPServer{
+	execAllowlist := DefaultExecAllowlist
+	if opts.execAllowlist != nil {
+		execAllowlist = opts.execAllowlist
+	}
+
+	hooks := &server.Hooks{}
+	hooks.AddAfterCallTool(logToolCallError)
+	if opts.logForwarder != nil {
+		opts.logForwarder.registerHooks(hooks)
+	}
+
+	s := &PortainerMCPServer{
 		srv: server.NewMCPServer(
 			"Portainer MCP Server",
 			"0.5.1",
 			server.WithToolCapabilities(true),
+			server.WithPromptCapabilities(true),
 			server.WithLogging(),
+			server.WithHooks(hooks),
 		),
-		cli:      portainerClient,
-		tools:    tools,
-		readOnly: opts.readOnly,
-	}, nil
+		cli:           portainerClient,
+		tools:         tools,
+		prompts:       prompts,
+		responses:     newResponseBuffer(maxResponseSize),
+		profiles:      profiles,
+		readOnly:      opts.readOnly,
+		execAllowlist: execAllowlist,
+	}
+
+	// The truncated responses of any tool are fetched with the same tool, it is registered once for all the features
+	s.addToolIfExists(ToolGetResponseChunk, s.HandleGetResponseChunk())
+
+	return s, nil
 }
 
 // Start begins listening for MCP protocol messages on standard input/output.
@@ -220,6 +333,36 @@ func (s *PortainerMCPServer) addToolIfExists(toolName string, handler server.Too
 	if tool, exists := s.tools[toolName]; exists {
 		s.srv.AddTool(tool, handler)
 	} else {
-		log.Printf("Tool %s not found, will not be registered for MCP usage", toolName)
+		log.Warn().Str("tool", toolName).Msg("tool not found, will not be registered for MCP usage")
+	}
+}
+
+// addPromptIfExists adds a prompt to the server if it exists in the prompts map
+func (s *PortainerMCPServer) addPromptIfExists(promptName string) {
+	if prompt, exists := s.prompts[promptName]; exists {
+		s.srv.AddPrompt(prompt.Prompt, s.HandleGetPrompt(prompt))
+	} else {
+		log.Warn().Str("prompt", promptName).Msg("prompt not found, will not be registered for MCP usage")
+	}
+}
+
+// logToolCallError logs the tool calls that returned an error result. The errors are reported to the
+// client in the result already and usually come from invalid parameters, they are logged at debug level.
+func logToolCallError(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	if result == nil || !result.IsError {
+		return
+	}
+
+	event := log.Debug().Str("tool", message.Params.Name)
+	// The error can contain the parameters of the call, it is only forwarded to the session that made it
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		event = event.Str(LogSessionField, session.SessionID())
+	}
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			event = event.Str("error", textContent.Text)
+			break
+		}
 	}
+	event.Msg("tool call failed")
 }
@@ -21,6 +21,8 @@ func TestNewPortainerMCPServer(t *testing.T) {
 		serverURL     string
 		token         string
 		toolsPath     string
+		promptsPath   string
+		profilesPath  string
 		mockSetup     func(*MockPortainerClient)
 		expectError   bool
 		errorContains string
@@ -53,6 +55,26 @@ func TestNewPortainerMCPServer(t *testing.T) {
 			expectError:   true,
 			errorContains: "invalid version in tools.yaml",
 		},
+		{
+			name:          "invalid prompts path",
+			serverURL:     "https://portainer.example.com",
+			token:         "valid-token",
+			toolsPath:     validToolsPath,
+			promptsPath:   "testdata/nonexistent_prompts.yaml",
+			mockSetup:     func(m *MockPortainerClient) {},
+			expectError:   true,
+			errorContains: "failed to load prompts",
+		},
+		{
+			name:          "invalid stripping profiles path",
+			serverURL:     "https://portainer.example.com",
+			token:         "valid-token",
+			toolsPath:     validToolsPath,
+			profilesPath:  "testdata/nonexistent_profiles.yaml",
+			mockSetup:     func(m *MockPortainerClient) {},
+			expectError:   true,
+			errorContains: "failed to load stripping profiles",
+		},
 		{
 			name:      "API communication error",
 			serverURL: "https://portainer.example.com",
@@ -102,6 +124,14 @@ func TestNewPortainerMCPServer(t *testing.T) {
 				options = append(options, WithDisableVersionCheck(true))
 			}
 
+			if tt.promptsPath != "" {
+				options = append(options, WithPromptsPath(tt.promptsPath))
+			}
+
+			if tt.profilesPath != "" {
+				options = append(options, WithStrippingProfilesPath(tt.profilesPath))
+			}
+
 			server, err := NewPortainerMCPServer(
 				tt.serverURL,
 				tt.token,
@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -22,17 +21,24 @@ func (s *PortainerMCPServer) AddStackFeatures() {
 
 func (s *PortainerMCPServer) HandleGetStacks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
 		stacks, err := s.cli.GetStacks()
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get stacks", err), nil
 		}
 
-		data, err := json.Marshal(stacks)
+		data, err := output.render(stacks)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to marshal stacks", err), nil
+			return mcp.NewToolResultErrorFromErr("failed to render stacks", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(data), nil
 	}
 }
 
@@ -0,0 +1,467 @@
+package mcp
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// Node availabilities accepted by the updateNodeAvailability tool
+var nodeAvailabilities = []string{"active", "pause", "drain"}
+
+// Task desired states accepted by the listServiceTasks tool
+var taskDesiredStates = []string{"running", "shutdown", "accepted"}
+
+// swarmObject is a service or a node as returned by the Swarm API. The spec is kept raw so that the
+// object can be updated without losing the fields that are not known by the Docker API types.
+type swarmObject struct {
+	ID      string
+	Version struct {
+		Index uint64
+	}
+	Spec map[string]any
+}
+
+// swarmUpdateResponse is the response of a service update
+type swarmUpdateResponse struct {
+	Warnings []string
+}
+
+func (s *PortainerMCPServer) AddSwarmFeatures() {
+	s.addToolIfExists(ToolListServices, s.HandleListServices())
+	s.addToolIfExists(ToolListServiceTasks, s.HandleListServiceTasks())
+	s.addToolIfExists(ToolListNodes, s.HandleListNodes())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolScaleService, s.HandleScaleService())
+		s.addToolIfExists(ToolForceUpdateService, s.HandleForceUpdateService())
+		s.addToolIfExists(ToolUpdateNodeAvailability, s.HandleUpdateNodeAvailability())
+	}
+}
+
+func (s *PortainerMCPServer) HandleListServices() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		name, err := parser.GetString("name", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		queryParams := map[string]string{"status": "true"}
+		if name != "" {
+			encodedFilters, err := json.Marshal(map[string][]string{"name": {name}})
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to encode filters", err), nil
+			}
+			queryParams["filters"] = string(encodedFilters)
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/services",
+			Method:        "GET",
+			QueryParams:   queryParams,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var rawServices []swarm.Service
+		if err := json.Unmarshal(responseBody, &rawServices); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode services", err), nil
+		}
+
+		services := make([]models.DockerService, len(rawServices))
+		for i, rawService := range rawServices {
+			services[i] = models.ConvertToDockerService(rawService)
+		}
+		slices.SortFunc(services, func(a, b models.DockerService) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		data, err := output.render(services)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render services", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleListServiceTasks() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		serviceId, err := parser.GetString("serviceId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid serviceId parameter", err), nil
+		}
+
+		desiredState, err := parser.GetString("desiredState", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid desiredState parameter", err), nil
+		}
+		if desiredState != "" && !slices.Contains(taskDesiredStates, desiredState) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid desiredState: %s, valid states are: %s", desiredState, strings.Join(taskDesiredStates, ", "))), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		// The service is inspected first to name the tasks after it, whether it is referenced by ID or by name
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          fmt.Sprintf("/services/%s", url.PathEscape(serviceId)),
+			Method:        "GET",
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var rawService swarm.Service
+		if err := json.Unmarshal(responseBody, &rawService); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode service", err), nil
+		}
+
+		filters := map[string][]string{"service": {rawService.ID}}
+		if desiredState != "" {
+			filters["desired-state"] = []string{desiredState}
+		}
+		encodedFilters, err := json.Marshal(filters)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to encode filters", err), nil
+		}
+
+		response, responseBody, err = s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/tasks",
+			Method:        "GET",
+			QueryParams:   map[string]string{"filters": string(encodedFilters)},
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var rawTasks []swarm.Task
+		if err := json.Unmarshal(responseBody, &rawTasks); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode tasks", err), nil
+		}
+
+		nodeHostnames := s.getNodeHostnames(environmentId)
+
+		// Like docker service ps, the tasks are grouped by slot with the most recent first
+		slices.SortStableFunc(rawTasks, func(a, b swarm.Task) int {
+			if a.Slot != b.Slot {
+				return cmp.Compare(a.Slot, b.Slot)
+			}
+			return b.Status.Timestamp.Compare(a.Status.Timestamp)
+		})
+
+		tasks := make([]models.DockerTask, len(rawTasks))
+		for i, rawTask := range rawTasks {
+			tasks[i] = models.ConvertToDockerTask(rawTask, rawService.Spec.Name, nodeHostnames)
+		}
+
+		data, err := output.render(tasks)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render tasks", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleListNodes() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/nodes",
+			Method:        "GET",
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		var rawNodes []swarm.Node
+		if err := json.Unmarshal(responseBody, &rawNodes); err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to decode nodes", err), nil
+		}
+
+		nodes := make([]models.DockerNode, len(rawNodes))
+		for i, rawNode := range rawNodes {
+			nodes[i] = models.ConvertToDockerNode(rawNode)
+		}
+		slices.SortFunc(nodes, func(a, b models.DockerNode) int {
+			return cmp.Compare(a.Hostname, b.Hostname)
+		})
+
+		data, err := output.render(nodes)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render nodes", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleScaleService() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		serviceId, err := parser.GetString("serviceId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid serviceId parameter", err), nil
+		}
+
+		replicas, err := parser.GetInt("replicas", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid replicas parameter", err), nil
+		}
+		if replicas < 0 {
+			return mcp.NewToolResultError("replicas must be greater than or equal to 0"), nil
+		}
+
+		response, responseBody, err := s.updateSwarmObject(environmentId, "services", serviceId, func(spec map[string]any) error {
+			mode, _ := spec["Mode"].(map[string]any)
+			replicated, ok := mode["Replicated"].(map[string]any)
+			if !ok {
+				return fmt.Errorf("service %s is not in replicated mode", serviceId)
+			}
+			replicated["Replicas"] = replicas
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to scale service", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		return mcp.NewToolResultText(formatServiceUpdate(fmt.Sprintf("Service %s scaled to %d replicas", serviceId, replicas), responseBody)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleForceUpdateService() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		serviceId, err := parser.GetString("serviceId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid serviceId parameter", err), nil
+		}
+
+		// Incrementing ForceUpdate makes Swarm replace all the tasks even though the spec did not change,
+		// following the update config of the service
+		response, responseBody, err := s.updateSwarmObject(environmentId, "services", serviceId, func(spec map[string]any) error {
+			taskTemplate, ok := spec["TaskTemplate"].(map[string]any)
+			if !ok {
+				return fmt.Errorf("service %s has no task template", serviceId)
+			}
+			var forceUpdate uint64
+			if value, ok := taskTemplate["ForceUpdate"].(json.Number); ok {
+				parsed, err := strconv.ParseUint(value.String(), 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid ForceUpdate value of service %s: %w", serviceId, err)
+				}
+				forceUpdate = parsed
+			}
+			taskTemplate["ForceUpdate"] = forceUpdate + 1
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to force update service", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		return mcp.NewToolResultText(formatServiceUpdate(fmt.Sprintf("Rolling restart of service %s started", serviceId), responseBody)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleUpdateNodeAvailability() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		nodeId, err := parser.GetString("nodeId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid nodeId parameter", err), nil
+		}
+
+		availability, err := parser.GetString("availability", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid availability parameter", err), nil
+		}
+		if !slices.Contains(nodeAvailabilities, availability) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid availability: %s, valid values are: %s", availability, strings.Join(nodeAvailabilities, ", "))), nil
+		}
+
+		response, responseBody, err := s.updateSwarmObject(environmentId, "nodes", nodeId, func(spec map[string]any) error {
+			spec["Availability"] = availability
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to update node availability", err), nil
+		}
+		if !isSuccessStatusCode(response.StatusCode) {
+			return s.proxyResult(ctx, response, responseBody), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Node %s availability set to %s", nodeId, availability)), nil
+	}
+}
+
+// updateSwarmObject updates the spec of a service or a node. The object is inspected to get its current spec
+// and version, the spec is modified by the update function and sent back with the version, so that the update
+// is rejected if the object was modified in the meantime. The response of the inspect request is returned if
+// it is not successful.
+func (s *PortainerMCPServer) updateSwarmObject(environmentId int, kind, id string, update func(spec map[string]any) error) (*http.Response, []byte, error) {
+	path := fmt.Sprintf("/%s/%s", kind, url.PathEscape(id))
+
+	response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          path,
+		Method:        "GET",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isSuccessStatusCode(response.StatusCode) {
+		return response, responseBody, nil
+	}
+
+	// The numbers of the spec are kept as json.Number, so that the int64 values (NanoCPUs, MemoryBytes...)
+	// are sent back without losing precision
+	var object swarmObject
+	decoder := json.NewDecoder(bytes.NewReader(responseBody))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s: %w", kind, err)
+	}
+	if object.Spec == nil {
+		return nil, nil, fmt.Errorf("%s %s has no spec", kind, id)
+	}
+
+	if err := update(object.Spec); err != nil {
+		return nil, nil, err
+	}
+
+	spec, err := json.Marshal(object.Spec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode %s spec: %w", kind, err)
+	}
+
+	return s.sendDockerRequest(models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          path + "/update",
+		Method:        "POST",
+		QueryParams:   map[string]string{"version": strconv.FormatUint(object.Version.Index, 10)},
+		Headers:       map[string]string{"Content-Type": "application/json"},
+		Body:          bytes.NewReader(spec),
+	})
+}
+
+// getNodeHostnames returns the hostnames of the Swarm nodes by node ID. The task nodes are displayed with
+// their IDs if the nodes cannot be listed.
+func (s *PortainerMCPServer) getNodeHostnames(environmentId int) map[string]string {
+	hostnames := map[string]string{}
+
+	response, responseBody, err := s.sendDockerRequest(models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Path:          "/nodes",
+		Method:        "GET",
+	})
+	if err != nil || !isSuccessStatusCode(response.StatusCode) {
+		return hostnames
+	}
+
+	var rawNodes []swarm.Node
+	if err := json.Unmarshal(responseBody, &rawNodes); err != nil {
+		return hostnames
+	}
+
+	for _, rawNode := range rawNodes {
+		hostnames[rawNode.ID] = rawNode.Description.Hostname
+	}
+	return hostnames
+}
+
+// formatServiceUpdate appends the warnings returned by a service update to the result message
+func formatServiceUpdate(message string, responseBody []byte) string {
+	var updateResponse swarmUpdateResponse
+	if err := json.Unmarshal(responseBody, &updateResponse); err != nil || len(updateResponse.Warnings) == 0 {
+		return message
+	}
+
+	return message + "\nWarnings:\n- " + strings.Join(updateResponse.Warnings, "\n- ")
+}
@@ -0,0 +1,323 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListServices(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" &&
+			opts.Path == "/services" &&
+			assert.ObjectsAreEqual(map[string]string{"status": "true", "filters": `{"name":["shop"]}`}, opts.QueryParams)
+	})).Return(createMockHttpResponse(http.StatusOK, `[
+		{"ID":"s2","Spec":{"Name":"shop_web","Mode":{"Replicated":{"Replicas":3}},"TaskTemplate":{"ContainerSpec":{"Image":"nginx:1.27@sha256:0a39"}}},"ServiceStatus":{"RunningTasks":2,"DesiredTasks":3}},
+		{"ID":"s1","Spec":{"Name":"shop_db","Mode":{"Replicated":{"Replicas":1}},"TaskTemplate":{"ContainerSpec":{"Image":"postgres:17"}}},"ServiceStatus":{"RunningTasks":1,"DesiredTasks":1}}
+	]`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleListServices()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"name":          "shop",
+		"outputFormat":  "csv",
+		"fields":        []any{"name", "replicas", "image"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "name,replicas,image\nshop_db,1/1,postgres:17\nshop_web,2/3,nginx:1.27\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleListServiceTasks(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          map[string]any
+		setupMock      func(mockClient *MockPortainerClient)
+		expectedText   string
+		expectedError  bool
+		skipClientCall bool
+	}{
+		{
+			name: "tasks with failure reasons",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"serviceId":     "web",
+				"outputFormat":  "csv",
+				"fields":        []any{"name", "node", "desired_state", "state", "error"},
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/services/web"
+				})).Return(createMockHttpResponse(http.StatusOK, `{"ID":"svc-id","Spec":{"Name":"web"}}`), nil)
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/tasks" &&
+						assert.ObjectsAreEqual(map[string]string{"filters": `{"service":["svc-id"]}`}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK, `[
+					{"ID":"t3","Slot":2,"NodeID":"n2","DesiredState":"running","Status":{"Timestamp":"2025-01-01T12:05:00Z","State":"running"}},
+					{"ID":"t2","Slot":1,"NodeID":"n1","DesiredState":"running","Status":{"Timestamp":"2025-01-01T12:01:00Z","State":"running"}},
+					{"ID":"t1","Slot":1,"NodeID":"n1","DesiredState":"shutdown","Status":{"Timestamp":"2025-01-01T12:00:00Z","State":"failed","Err":"task: non-zero exit (1)"}}
+				]`), nil)
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/nodes"
+				})).Return(createMockHttpResponse(http.StatusOK, `[{"ID":"n1","Description":{"Hostname":"worker-1"}}]`), nil)
+			},
+			expectedText: "name,node,desired_state,state,error\n" +
+				"web.1,worker-1,running,running,\n" +
+				"web.1,worker-1,shutdown,failed,task: non-zero exit (1)\n" +
+				"web.2,n2,running,running,\n",
+		},
+		{
+			name: "service not found",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"serviceId":     "missing",
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/services/missing"
+				})).Return(createMockHttpResponse(http.StatusNotFound, `{"message":"service missing not found"}`), nil)
+			},
+			expectedText:  "HTTP 404 Not Found\nError: service missing not found",
+			expectedError: true,
+		},
+		{
+			name: "invalid desired state",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"serviceId":     "web",
+				"desiredState":  "failed",
+			},
+			setupMock:     func(mockClient *MockPortainerClient) {},
+			expectedText:  "invalid desiredState: failed",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleListServiceTasks()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			if tt.expectedError {
+				assert.Contains(t, resultTexts(t, result)[0], tt.expectedText)
+			} else {
+				assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleListNodes(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" && opts.Path == "/nodes"
+	})).Return(createMockHttpResponse(http.StatusOK, `[
+		{"ID":"n2","Spec":{"Role":"worker","Availability":"drain"},"Description":{"Hostname":"worker-1"},"Status":{"State":"ready"}},
+		{"ID":"n1","Spec":{"Role":"manager","Availability":"active"},"Description":{"Hostname":"manager-1"},"Status":{"State":"ready"},"ManagerStatus":{"Leader":true}}
+	]`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleListNodes()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"outputFormat":  "csv",
+		"fields":        []any{"hostname", "role", "availability", "manager_status"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "hostname,role,availability,manager_status\nmanager-1,manager,active,leader\nworker-1,worker,drain,\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+// expectSwarmUpdate mocks the inspect and update requests of a service or node and returns the spec sent with the update
+func expectSwarmUpdate(t *testing.T, mockClient *MockPortainerClient, path, object string, updateResponse *http.Response) *map[string]any {
+	t.Helper()
+
+	var sentSpec map[string]any
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "GET" && opts.Path == path
+	})).Return(createMockHttpResponse(http.StatusOK, object), nil)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Method == "POST" &&
+			opts.Path == path+"/update" &&
+			opts.QueryParams["version"] == "42" &&
+			opts.Headers["Content-Type"] == "application/json"
+	})).Run(func(args mock.Arguments) {
+		body, err := io.ReadAll(args.Get(0).(models.DockerProxyRequestOptions).Body)
+		require.NoError(t, err)
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		require.NoError(t, decoder.Decode(&sentSpec))
+	}).Return(updateResponse, nil)
+
+	return &sentSpec
+}
+
+func TestHandleScaleService(t *testing.T) {
+	service := `{"ID":"svc-id","Version":{"Index":42},"Spec":{"Name":"web","Labels":{"app":"shop"},"Mode":{"Replicated":{"Replicas":2}},"TaskTemplate":{"ContainerSpec":{"Image":"nginx"},"Resources":{"Limits":{"NanoCPUs":9007199254740993,"MemoryBytes":9223372036854775807}},"Unknown":"kept"}}}`
+
+	t.Run("scale replicated service", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+		sentSpec := expectSwarmUpdate(t, mockClient, "/services/web", service,
+			createMockHttpResponse(http.StatusOK, `{"Warnings":["image nginx could not be accessed on a registry"]}`))
+
+		server := &PortainerMCPServer{
+			cli: mockClient,
+		}
+
+		handler := server.HandleScaleService()
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"serviceId":     "web",
+			"replicas":      float64(5),
+		}))
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "Service web scaled to 5 replicas\nWarnings:\n- image nginx could not be accessed on a registry", resultTexts(t, result)[0])
+		assert.Equal(t, map[string]any{
+			"Name":   "web",
+			"Labels": map[string]any{"app": "shop"},
+			"Mode":   map[string]any{"Replicated": map[string]any{"Replicas": json.Number("5")}},
+			"TaskTemplate": map[string]any{
+				"ContainerSpec": map[string]any{"Image": "nginx"},
+				// The int64 values are sent back without losing precision
+				"Resources": map[string]any{"Limits": map[string]any{
+					"NanoCPUs":    json.Number("9007199254740993"),
+					"MemoryBytes": json.Number("9223372036854775807"),
+				}},
+				"Unknown": "kept",
+			},
+		}, *sentSpec)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("global service cannot be scaled", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+		mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+			return opts.Method == "GET" && opts.Path == "/services/agent"
+		})).Return(createMockHttpResponse(http.StatusOK, `{"ID":"agent-id","Version":{"Index":42},"Spec":{"Name":"agent","Mode":{"Global":{}}}}`), nil)
+
+		server := &PortainerMCPServer{
+			cli: mockClient,
+		}
+
+		handler := server.HandleScaleService()
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"serviceId":     "agent",
+			"replicas":      float64(2),
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultTexts(t, result)[0], "service agent is not in replicated mode")
+
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestHandleForceUpdateService(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	sentSpec := expectSwarmUpdate(t, mockClient, "/services/web",
+		`{"ID":"svc-id","Version":{"Index":42},"Spec":{"Name":"web","TaskTemplate":{"ContainerSpec":{"Image":"nginx"},"ForceUpdate":1}}}`,
+		createMockHttpResponse(http.StatusOK, `{"Warnings":null}`))
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleForceUpdateService()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"serviceId":     "web",
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Rolling restart of service web started", resultTexts(t, result)[0])
+	assert.Equal(t, json.Number("2"), (*sentSpec)["TaskTemplate"].(map[string]any)["ForceUpdate"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleUpdateNodeAvailability(t *testing.T) {
+	t.Run("drain node", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+		sentSpec := expectSwarmUpdate(t, mockClient, "/nodes/worker-1",
+			`{"ID":"n2","Version":{"Index":42},"Spec":{"Role":"worker","Availability":"active","Labels":{"zone":"a"}}}`,
+			createMockHttpResponse(http.StatusOK, ""))
+
+		server := &PortainerMCPServer{
+			cli: mockClient,
+		}
+
+		handler := server.HandleUpdateNodeAvailability()
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"nodeId":        "worker-1",
+			"availability":  "drain",
+		}))
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "Node worker-1 availability set to drain", resultTexts(t, result)[0])
+		assert.Equal(t, map[string]any{"Role": "worker", "Availability": "drain", "Labels": map[string]any{"zone": "a"}}, *sentSpec)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("invalid availability", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+
+		server := &PortainerMCPServer{
+			cli: mockClient,
+		}
+
+		handler := server.HandleUpdateNodeAvailability()
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"nodeId":        "worker-1",
+			"availability":  "offline",
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultTexts(t, result)[0], "invalid availability: offline, valid values are: active, pause, drain")
+
+		mockClient.AssertNotCalled(t, "ProxyDockerRequest", mock.Anything)
+	})
+}
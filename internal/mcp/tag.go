@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -20,17 +19,24 @@ func (s *PortainerMCPServer) AddTagFeatures() {
 
 func (s *PortainerMCPServer) HandleGetEnvironmentTags() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
 		environmentTags, err := s.cli.GetEnvironmentTags()
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get environment tags", err), nil
 		}
 
-		data, err := json.Marshal(environmentTags)
+		data, err := output.render(environmentTags)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to marshal environment tags", err), nil
+			return mcp.NewToolResultErrorFromErr("failed to render environment tags", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(data), nil
 	}
 }
 
@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -40,17 +39,24 @@ func (s *PortainerMCPServer) HandleCreateTeam() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleGetTeams() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
 		teams, err := s.cli.GetTeams()
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get teams", err), nil
 		}
 
-		data, err := json.Marshal(teams)
+		data, err := output.render(teams)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to marshal teams", err), nil
+			return mcp.NewToolResultErrorFromErr("failed to render teams", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(data), nil
 	}
 }
 
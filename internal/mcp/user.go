@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -20,17 +19,24 @@ func (s *PortainerMCPServer) AddUserFeatures() {
 
 func (s *PortainerMCPServer) HandleGetUsers() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
 		users, err := s.cli.GetUsers()
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get users", err), nil
 		}
 
-		data, err := json.Marshal(users)
+		data, err := output.render(users)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to marshal users", err), nil
+			return mcp.NewToolResultErrorFromErr("failed to render users", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return mcp.NewToolResultText(data), nil
 	}
 }
 
@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 	"slices"
+	"strconv"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -64,11 +65,32 @@ func parseKeyValueMap(items []any) (map[string]string, error) {
 	return resultMap, nil
 }
 
+// parseCursor decodes a pagination cursor, which is the offset of the first item of the page.
+// An empty cursor designates the first page.
+func parseCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	offset, err := strconv.Atoi(cursor)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+
+	return offset, nil
+}
+
 func isValidHTTPMethod(method string) bool {
 	validMethods := []string{"GET", "POST", "PUT", "DELETE", "HEAD"}
 	return slices.Contains(validMethods, method)
 }
 
+// isValidKubernetesHTTPMethod checks if the method is supported by the Kubernetes proxy,
+// which also accepts PATCH on top of the common methods
+func isValidKubernetesHTTPMethod(method string) bool {
+	return isValidHTTPMethod(method) || method == "PATCH"
+}
+
 // CreateMCPRequest creates a new MCP tool request with the given arguments
 func CreateMCPRequest(args map[string]any) mcp.CallToolRequest {
 	return mcp.CallToolRequest{
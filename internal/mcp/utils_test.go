@@ -154,6 +154,30 @@ func TestIsValidHTTPMethod(t *testing.T) {
 	}
 }
 
+func TestIsValidKubernetesHTTPMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		expect bool
+	}{
+		{"Valid GET", "GET", true},
+		{"Valid DELETE", "DELETE", true},
+		{"Valid PATCH", "PATCH", true},
+		{"Invalid lowercase patch", "patch", false},
+		{"Invalid OPTIONS", "OPTIONS", false},
+		{"Invalid Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isValidKubernetesHTTPMethod(tt.method)
+			if got != tt.expect {
+				t.Errorf("isValidKubernetesHTTPMethod(%q) = %v, want %v", tt.method, got, tt.expect)
+			}
+		})
+	}
+}
+
 func TestParseKeyValueMap(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -244,3 +268,29 @@ func TestParseKeyValueMap(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCursor(t *testing.T) {
+	tests := []struct {
+		name        string
+		cursor      string
+		expected    int
+		expectError bool
+	}{
+		{name: "first page", cursor: "", expected: 0},
+		{name: "offset", cursor: "50", expected: 50},
+		{name: "invalid cursor", cursor: "next", expectError: true},
+		{name: "negative cursor", cursor: "-1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCursor(tt.cursor)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseCursor() error = %v, expectError %v", err, tt.expectError)
+			}
+			if got != tt.expected {
+				t.Errorf("parseCursor() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+func (s *PortainerMCPServer) AddVolumeFeatures() {
+	s.addToolIfExists(ToolListVolumes, s.HandleListVolumes())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolPruneVolumes, s.HandlePruneVolumes())
+	}
+}
+
+func (s *PortainerMCPServer) HandleListVolumes() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		unused, err := parser.GetBoolean("unused", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid unused parameter", err), nil
+		}
+
+		output, err := parseOutputOptions(parser)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid output parameters", err), nil
+		}
+
+		var rawVolumes volume.ListResponse
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/volumes",
+			Method:        "GET",
+		}, &rawVolumes); result != nil || err != nil {
+			return result, err
+		}
+
+		var rawContainers []container.Summary
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/containers/json",
+			Method:        "GET",
+			QueryParams:   map[string]string{"all": "true"},
+		}, &rawContainers); result != nil || err != nil {
+			return result, err
+		}
+
+		usedBy := containerNamesBy(rawContainers, containerVolumes)
+
+		volumes := make([]models.DockerVolume, 0, len(rawVolumes.Volumes))
+		for _, rawVolume := range rawVolumes.Volumes {
+			dockerVolume := models.ConvertToDockerVolume(*rawVolume, usedBy[rawVolume.Name])
+			if unused && len(dockerVolume.UsedBy) > 0 {
+				continue
+			}
+			volumes = append(volumes, dockerVolume)
+		}
+		slices.SortFunc(volumes, func(a, b models.DockerVolume) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		data, err := output.render(volumes)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to render volumes", err), nil
+		}
+
+		return mcp.NewToolResultText(data), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandlePruneVolumes() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+		}
+
+		all, err := parser.GetBoolean("all", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid all parameter", err), nil
+		}
+
+		confirm, err := parser.GetBoolean("confirm", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid confirm parameter", err), nil
+		}
+
+		if confirm {
+			opts := models.DockerProxyRequestOptions{
+				EnvironmentID: environmentId,
+				Path:          "/volumes/prune",
+				Method:        "POST",
+			}
+			if all {
+				// Only the anonymous volumes are removed unless the all filter is set
+				opts.QueryParams = map[string]string{"filters": `{"all":["true"]}`}
+			}
+
+			var report volume.PruneReport
+			if result, err := s.decodeDockerResponse(ctx, opts, &report); result != nil || err != nil {
+				return result, err
+			}
+
+			return mcp.NewToolResultText(formatPruneReport("volume", report.VolumesDeleted, report.SpaceReclaimed)), nil
+		}
+
+		// The size and the reference count of the volumes are only computed by the disk usage endpoint
+		var usage types.DiskUsage
+		if result, err := s.decodeDockerResponse(ctx, models.DockerProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          "/system/df",
+			Method:        "GET",
+			QueryParams:   map[string]string{"type": "volume"},
+		}, &usage); result != nil || err != nil {
+			return result, err
+		}
+
+		var candidates []pruneCandidate
+		for _, rawVolume := range usage.Volumes {
+			if rawVolume.UsageData == nil || rawVolume.UsageData.RefCount != 0 {
+				continue
+			}
+			if !all && !models.IsAnonymousDockerVolume(*rawVolume) {
+				continue
+			}
+			candidates = append(candidates, pruneCandidate{name: rawVolume.Name, size: rawVolume.UsageData.Size})
+		}
+
+		return mcp.NewToolResultText(formatPrunePreview(ToolPruneVolumes, "volume", candidates)), nil
+	}
+}
+
+// containerVolumes returns the names of the volumes mounted by a container
+func containerVolumes(rawContainer container.Summary) []string {
+	var names []string
+	for _, mountPoint := range rawContainer.Mounts {
+		if mountPoint.Type == mount.TypeVolume && mountPoint.Name != "" {
+			names = append(names, mountPoint.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListVolumes(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/volumes"
+	})).Return(createMockHttpResponse(http.StatusOK, `{"Volumes":[
+		{"Name":"shop_db","Driver":"local"},
+		{"Name":"3f2a9c","Driver":"local","Labels":{"com.docker.volume.anonymous":""}},
+		{"Name":"shop_cache","Driver":"local"}
+	]}`), nil)
+	mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+		return opts.Path == "/containers/json" &&
+			assert.ObjectsAreEqual(map[string]string{"all": "true"}, opts.QueryParams)
+	})).Return(createMockHttpResponse(http.StatusOK, `[
+		{"Id":"c1","Names":["/db"],"Mounts":[{"Type":"volume","Name":"shop_db"},{"Type":"bind","Source":"/etc/localtime"}]}
+	]`), nil)
+
+	server := &PortainerMCPServer{
+		cli: mockClient,
+	}
+
+	handler := server.HandleListVolumes()
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"unused":        true,
+		"outputFormat":  "csv",
+		"fields":        []any{"name", "anonymous"},
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "name,anonymous\n3f2a9c,true\nshop_cache,false\n", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandlePruneVolumes(t *testing.T) {
+	diskUsage := `{"Volumes":[
+		{"Name":"shop_db","UsageData":{"RefCount":1,"Size":1000000000}},
+		{"Name":"3f2a9c","Labels":{"com.docker.volume.anonymous":""},"UsageData":{"RefCount":0,"Size":500000000}},
+		{"Name":"shop_cache","UsageData":{"RefCount":0,"Size":-1}}
+	]}`
+
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(mockClient *MockPortainerClient)
+		expectedText  string
+		expectedError bool
+	}{
+		{
+			name: "dry run of the anonymous volumes",
+			input: map[string]any{
+				"environmentId": float64(1),
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/system/df"
+				})).Return(createMockHttpResponse(http.StatusOK, diskUsage), nil)
+			},
+			expectedText: "Dry run: 1 volume would be removed, reclaiming 500MB. Nothing was removed, " +
+				"call pruneVolumes again with confirm set to true to remove them.\n\n" +
+				"- 3f2a9c (500MB)\n",
+		},
+		{
+			name: "dry run of all the unused volumes with an unknown size",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"all":           true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/system/df"
+				})).Return(createMockHttpResponse(http.StatusOK, diskUsage), nil)
+			},
+			expectedText: "Dry run: 2 volumes would be removed, reclaiming at least 500MB. Nothing was removed, " +
+				"call pruneVolumes again with confirm set to true to remove them.\n\n" +
+				"- 3f2a9c (500MB)\n" +
+				"- shop_cache\n",
+		},
+		{
+			name: "confirmed prune of all the unused volumes",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"all":           true,
+				"confirm":       true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Method == "POST" &&
+						opts.Path == "/volumes/prune" &&
+						assert.ObjectsAreEqual(map[string]string{"filters": `{"all":["true"]}`}, opts.QueryParams)
+				})).Return(createMockHttpResponse(http.StatusOK, `{"VolumesDeleted":["3f2a9c","shop_cache"],"SpaceReclaimed":1500000000}`), nil)
+			},
+			expectedText: "Removed 2 volumes, reclaimed 1.5GB.\n\n- 3f2a9c\n- shop_cache\n",
+		},
+		{
+			name: "confirmed prune with nothing to remove",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"confirm":       true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.MatchedBy(func(opts models.DockerProxyRequestOptions) bool {
+					return opts.Path == "/volumes/prune" && opts.QueryParams == nil
+				})).Return(createMockHttpResponse(http.StatusOK, `{"VolumesDeleted":null,"SpaceReclaimed":0}`), nil)
+			},
+			expectedText: "Nothing to prune, no volumes were removed.",
+		},
+		{
+			name: "prune already running",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"confirm":       true,
+			},
+			setupMock: func(mockClient *MockPortainerClient) {
+				mockClient.On("ProxyDockerRequest", mock.Anything).
+					Return(createMockHttpResponse(http.StatusConflict, `{"message":"a prune operation is already running"}`), nil)
+			},
+			expectedText:  "HTTP 409 Conflict\nError: a prune operation is already running",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.setupMock(mockClient)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandlePruneVolumes()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
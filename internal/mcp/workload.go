@@ -0,0 +1,499 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// deploymentRevisionAnnotation is the annotation holding the revision of a Deployment and of its ReplicaSets
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	// restartedAtAnnotation is the pod template annotation updated by kubectl rollout restart
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	// podTemplateHashLabel is the label added by the Deployment controller to the pod templates of the ReplicaSets
+	podTemplateHashLabel = "pod-template-hash"
+	// rollingUpdateStrategy is the update strategy of the workloads supporting rollouts
+	rollingUpdateStrategy = "RollingUpdate"
+	// progressDeadlineExceededReason is the reason of the Progressing condition of a Deployment whose rollout is stuck
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+// workloadResources maps the workload kinds, and their usual aliases, to their resource in the apps/v1 API
+var workloadResources = map[string]string{
+	"deployment":   "deployments",
+	"deployments":  "deployments",
+	"deploy":       "deployments",
+	"statefulset":  "statefulsets",
+	"statefulsets": "statefulsets",
+	"sts":          "statefulsets",
+	"daemonset":    "daemonsets",
+	"daemonsets":   "daemonsets",
+	"ds":           "daemonsets",
+}
+
+// workloadRef is a reference to a Deployment, a StatefulSet or a DaemonSet
+type workloadRef struct {
+	namespace string
+	resource  string
+	name      string
+}
+
+// kubernetesWorkload is the subset of a Deployment, StatefulSet or DaemonSet used by the workload tools
+type kubernetesWorkload struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int32                `json:"replicas"`
+		Selector *metav1.LabelSelector `json:"selector"`
+		Paused   bool                  `json:"paused"`
+		// UpdateStrategy is the update strategy of the StatefulSets and the DaemonSets
+		UpdateStrategy struct {
+			Type          string `json:"type"`
+			RollingUpdate *struct {
+				Partition *int32 `json:"partition"`
+			} `json:"rollingUpdate"`
+		} `json:"updateStrategy"`
+	} `json:"spec"`
+	Status struct {
+		ObservedGeneration int64                 `json:"observedGeneration"`
+		Replicas           int32                 `json:"replicas"`
+		UpdatedReplicas    int32                 `json:"updatedReplicas"`
+		ReadyReplicas      int32                 `json:"readyReplicas"`
+		AvailableReplicas  int32                 `json:"availableReplicas"`
+		CurrentRevision    string                `json:"currentRevision"`
+		UpdateRevision     string                `json:"updateRevision"`
+		Conditions         []kubernetesCondition `json:"conditions"`
+		// The replica counts of the DaemonSets
+		DesiredNumberScheduled int32 `json:"desiredNumberScheduled"`
+		UpdatedNumberScheduled int32 `json:"updatedNumberScheduled"`
+		NumberAvailable        int32 `json:"numberAvailable"`
+		NumberReady            int32 `json:"numberReady"`
+	} `json:"status"`
+}
+
+// kubernetesScale is the subset of an autoscaling/v1 Scale used by the scaleWorkload tool
+type kubernetesScale struct {
+	Spec struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"spec"`
+}
+
+// kubernetesReplicaSet is the subset of an apps/v1 ReplicaSet used by the workload tools
+type kubernetesReplicaSet struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int32         `json:"replicas"`
+		Template map[string]any `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		Replicas      int32 `json:"replicas"`
+		ReadyReplicas int32 `json:"readyReplicas"`
+	} `json:"status"`
+}
+
+// kubernetesReplicaSetList is an apps/v1 ReplicaSetList
+type kubernetesReplicaSetList struct {
+	Items []kubernetesReplicaSet `json:"items"`
+}
+
+func (s *PortainerMCPServer) HandleScaleWorkload() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, ref, result := parseWorkloadParameters(parser)
+		if result != nil {
+			return result, nil
+		}
+		if ref.resource == "daemonsets" {
+			return mcp.NewToolResultError(fmt.Sprintf("%s cannot be scaled, a DaemonSet runs one pod per eligible node", ref)), nil
+		}
+
+		replicas, err := parser.GetInt("replicas", true)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid replicas parameter", err), nil
+		}
+		if replicas < 0 {
+			return mcp.NewToolResultError("replicas must be a positive number"), nil
+		}
+
+		var scale kubernetesScale
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path() + "/scale",
+			Method:        "GET",
+		}, &scale); result != nil || err != nil {
+			return result, err
+		}
+
+		previous := scale.Spec.Replicas
+		if int(previous) == replicas {
+			return mcp.NewToolResultText(fmt.Sprintf("%s already has %d %s, nothing to do", ref, replicas, pluralize(replicas, "replica"))), nil
+		}
+
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path() + "/scale",
+			Method:        "PATCH",
+			Headers:       map[string]string{"Content-Type": patchContentTypes[PatchTypeMerge]},
+			Body:          strings.NewReader(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)),
+		}, &scale); result != nil || err != nil {
+			return result, err
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s scaled from %d to %d %s", ref, previous, scale.Spec.Replicas, pluralize(int(scale.Spec.Replicas), "replica"))), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleRestartWorkload() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, ref, result := parseWorkloadParameters(parser)
+		if result != nil {
+			return result, nil
+		}
+
+		var workload kubernetesWorkload
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path(),
+			Method:        "GET",
+		}, &workload); result != nil || err != nil {
+			return result, err
+		}
+		if workload.Spec.Paused {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is paused, resume it before restarting it", ref)), nil
+		}
+
+		patch, err := json.Marshal(map[string]any{
+			"spec": map[string]any{
+				"template": map[string]any{
+					"metadata": map[string]any{
+						"annotations": map[string]string{restartedAtAnnotation: time.Now().Format(time.RFC3339)},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to encode restart patch", err), nil
+		}
+
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path(),
+			Method:        "PATCH",
+			Headers:       map[string]string{"Content-Type": patchContentTypes[PatchTypeStrategicMerge]},
+			Body:          bytes.NewReader(patch),
+		}, &workload); result != nil || err != nil {
+			return result, err
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s restarted, its pods are being replaced. Use getRolloutStatus to follow the rollout.", ref)), nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleGetRolloutStatus() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, ref, result := parseWorkloadParameters(parser)
+		if result != nil {
+			return result, nil
+		}
+
+		var workload kubernetesWorkload
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path(),
+			Method:        "GET",
+		}, &workload); result != nil || err != nil {
+			return result, err
+		}
+
+		status, failed := rolloutStatus(ref, workload)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.NewTextContent(status + "\n" + rolloutCounts(ref, workload))},
+			IsError: failed,
+		}, nil
+	}
+}
+
+func (s *PortainerMCPServer) HandleUndoRollout() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, ref, result := parseWorkloadParameters(parser)
+		if result != nil {
+			return result, nil
+		}
+		if ref.resource != "deployments" {
+			return mcp.NewToolResultError(fmt.Sprintf("%s cannot be rolled back, only the Deployments are supported", ref)), nil
+		}
+
+		toRevision, err := parser.GetInt("toRevision", false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid toRevision parameter", err), nil
+		}
+		if toRevision < 0 {
+			return mcp.NewToolResultError("toRevision must be a positive number"), nil
+		}
+
+		var deployment kubernetesWorkload
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path(),
+			Method:        "GET",
+		}, &deployment); result != nil || err != nil {
+			return result, err
+		}
+		if deployment.Spec.Paused {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is paused, resume it before rolling it back", ref)), nil
+		}
+		if deployment.Spec.Selector == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s has no pod selector", ref)), nil
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid deployment selector", err), nil
+		}
+
+		replicaSets, err := s.listDeploymentReplicaSets(environmentId, ref.namespace, selector.String(), deployment.Metadata.UID)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to list the ReplicaSets of the deployment", err), nil
+		}
+
+		target, err := rollbackReplicaSet(replicaSets, toRevision)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("cannot roll back %s: %s", ref, err)), nil
+		}
+
+		targetRevision := replicaSetRevision(target)
+		if currentRevision, _ := strconv.Atoi(deployment.Metadata.Annotations[deploymentRevisionAnnotation]); targetRevision == currentRevision {
+			return mcp.NewToolResultText(fmt.Sprintf("%s is already at revision %d, nothing to do", ref, targetRevision)), nil
+		}
+
+		// The pod-template-hash label is added by the Deployment controller, it is not part of the Deployment template
+		template := target.Spec.Template
+		unstructured.RemoveNestedField(template, "metadata", "labels", podTemplateHashLabel)
+
+		patch, err := json.Marshal([]map[string]any{{"op": "replace", "path": "/spec/template", "value": template}})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to encode rollback patch", err), nil
+		}
+
+		if result, err := s.decodeKubernetesResponse(ctx, models.KubernetesProxyRequestOptions{
+			EnvironmentID: environmentId,
+			Path:          ref.path(),
+			Method:        "PATCH",
+			Headers:       map[string]string{"Content-Type": patchContentTypes[PatchTypeJSON]},
+			Body:          bytes.NewReader(patch),
+		}, &deployment); result != nil || err != nil {
+			return result, err
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s rolled back to revision %d (ReplicaSet %s). Use getRolloutStatus to follow the rollout.", ref, targetRevision, target.Metadata.Name)), nil
+	}
+}
+
+// listDeploymentReplicaSets lists the ReplicaSets owned by a Deployment, the most recent revision first
+func (s *PortainerMCPServer) listDeploymentReplicaSets(environmentId int, namespace, selector string, uid types.UID) ([]kubernetesReplicaSet, error) {
+	var replicaSets kubernetesReplicaSetList
+	if err := s.getKubernetesList(environmentId, kubernetesResourcePath(schema.GroupVersion{Group: "apps", Version: "v1"}, "replicasets", namespace, ""), selector, &replicaSets); err != nil {
+		return nil, err
+	}
+
+	owned := slices.DeleteFunc(replicaSets.Items, func(replicaSet kubernetesReplicaSet) bool {
+		return !slices.ContainsFunc(replicaSet.Metadata.OwnerReferences, func(owner metav1.OwnerReference) bool {
+			return owner.UID == uid
+		})
+	})
+
+	slices.SortFunc(owned, func(a, b kubernetesReplicaSet) int {
+		return replicaSetRevision(b) - replicaSetRevision(a)
+	})
+
+	return owned, nil
+}
+
+// parseWorkloadParameters parses the environmentId, namespace and workload parameters shared by the workload tools.
+// A tool result is returned when a parameter is invalid.
+func parseWorkloadParameters(parser *toolgen.ParameterParser) (int, workloadRef, *mcp.CallToolResult) {
+	environmentId, err := parser.GetInt("environmentId", true)
+	if err != nil {
+		return 0, workloadRef{}, mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err)
+	}
+
+	namespace, err := parser.GetString("namespace", true)
+	if err != nil {
+		return 0, workloadRef{}, mcp.NewToolResultErrorFromErr("invalid namespace parameter", err)
+	}
+
+	workload, err := parser.GetString("workload", true)
+	if err != nil {
+		return 0, workloadRef{}, mcp.NewToolResultErrorFromErr("invalid workload parameter", err)
+	}
+
+	ref, err := parseWorkloadRef(namespace, workload)
+	if err != nil {
+		return 0, workloadRef{}, mcp.NewToolResultErrorFromErr("invalid workload parameter", err)
+	}
+
+	return environmentId, ref, nil
+}
+
+// parseWorkloadRef parses a workload reference in the kind/name format used by kubectl, e.g. deployment/web
+func parseWorkloadRef(namespace, value string) (workloadRef, error) {
+	kind, name, found := strings.Cut(value, "/")
+	if !found || name == "" {
+		return workloadRef{}, fmt.Errorf("workload must be in the kind/name format, e.g. deployment/web: %s", value)
+	}
+
+	resource, exists := workloadResources[strings.ToLower(kind)]
+	if !exists {
+		return workloadRef{}, fmt.Errorf("unsupported workload kind: %s, supported kinds are: deployment, statefulset, daemonset", kind)
+	}
+
+	return workloadRef{namespace: namespace, resource: resource, name: name}, nil
+}
+
+// path returns the path of the workload in the Kubernetes API
+func (w workloadRef) path() string {
+	return fmt.Sprintf("/apis/apps/v1/namespaces/%s/%s/%s", url.PathEscape(w.namespace), w.resource, url.PathEscape(w.name))
+}
+
+// String returns the workload reference in the kind/name format
+func (w workloadRef) String() string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(w.resource, "s"), w.name)
+}
+
+// rolloutStatus returns the status of the rollout of a workload with the same messages as kubectl rollout status,
+// and whether the rollout failed
+func rolloutStatus(ref workloadRef, workload kubernetesWorkload) (string, bool) {
+	status := workload.Status
+	if workload.Metadata.Generation > status.ObservedGeneration {
+		return fmt.Sprintf("Waiting for %s spec update to be observed...", ref), false
+	}
+
+	switch ref.resource {
+	case "deployments":
+		for _, condition := range status.Conditions {
+			if condition.Type == "Progressing" && condition.Reason == progressDeadlineExceededReason {
+				return fmt.Sprintf("%s exceeded its progress deadline: %s", ref, condition.Message), true
+			}
+		}
+		if replicas := workload.Spec.Replicas; replicas != nil && status.UpdatedReplicas < *replicas {
+			return fmt.Sprintf("Waiting for %s rollout to finish: %d out of %d new replicas have been updated...", ref, status.UpdatedReplicas, *replicas), false
+		}
+		if status.Replicas > status.UpdatedReplicas {
+			return fmt.Sprintf("Waiting for %s rollout to finish: %d old replicas are pending termination...", ref, status.Replicas-status.UpdatedReplicas), false
+		}
+		if status.AvailableReplicas < status.UpdatedReplicas {
+			return fmt.Sprintf("Waiting for %s rollout to finish: %d of %d updated replicas are available...", ref, status.AvailableReplicas, status.UpdatedReplicas), false
+		}
+		return fmt.Sprintf("%s successfully rolled out", ref), false
+
+	case "statefulsets":
+		if workload.Spec.UpdateStrategy.Type != rollingUpdateStrategy {
+			return fmt.Sprintf("The rollout status of %s is only available with the RollingUpdate strategy, its strategy is %s", ref, workload.Spec.UpdateStrategy.Type), false
+		}
+		if status.ObservedGeneration == 0 {
+			return fmt.Sprintf("Waiting for %s spec update to be observed...", ref), false
+		}
+		if replicas := workload.Spec.Replicas; replicas != nil && status.ReadyReplicas < *replicas {
+			return fmt.Sprintf("Waiting for %d pods of %s to be ready...", *replicas-status.ReadyReplicas, ref), false
+		}
+		if rollingUpdate := workload.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil && workload.Spec.Replicas != nil {
+			expected := *workload.Spec.Replicas - *rollingUpdate.Partition
+			if status.UpdatedReplicas < expected {
+				return fmt.Sprintf("Waiting for partitioned rollout of %s to finish: %d out of %d new pods have been updated...", ref, status.UpdatedReplicas, expected), false
+			}
+			return fmt.Sprintf("Partitioned rollout of %s complete: %d new pods have been updated", ref, status.UpdatedReplicas), false
+		}
+		if status.UpdateRevision != status.CurrentRevision {
+			return fmt.Sprintf("Waiting for %s rolling update to complete: %d pods at revision %s...", ref, status.UpdatedReplicas, status.UpdateRevision), false
+		}
+		return fmt.Sprintf("%s rolling update complete: %d pods at revision %s", ref, status.Replicas, status.CurrentRevision), false
+
+	default:
+		if workload.Spec.UpdateStrategy.Type != rollingUpdateStrategy {
+			return fmt.Sprintf("The rollout status of %s is only available with the RollingUpdate strategy, its strategy is %s", ref, workload.Spec.UpdateStrategy.Type), false
+		}
+		if status.UpdatedNumberScheduled < status.DesiredNumberScheduled {
+			return fmt.Sprintf("Waiting for %s rollout to finish: %d out of %d new pods have been updated...", ref, status.UpdatedNumberScheduled, status.DesiredNumberScheduled), false
+		}
+		if status.NumberAvailable < status.DesiredNumberScheduled {
+			return fmt.Sprintf("Waiting for %s rollout to finish: %d of %d updated pods are available...", ref, status.NumberAvailable, status.DesiredNumberScheduled), false
+		}
+		return fmt.Sprintf("%s successfully rolled out", ref), false
+	}
+}
+
+// rolloutCounts renders the replica counts of a workload used to compute the status of its rollout
+func rolloutCounts(ref workloadRef, workload kubernetesWorkload) string {
+	status := workload.Status
+	if ref.resource == "daemonsets" {
+		return fmt.Sprintf("Pods: %d desired, %d updated, %d ready, %d available", status.DesiredNumberScheduled,
+			status.UpdatedNumberScheduled, status.NumberReady, status.NumberAvailable)
+	}
+
+	desired := int32(1)
+	if workload.Spec.Replicas != nil {
+		desired = *workload.Spec.Replicas
+	}
+	counts := fmt.Sprintf("Replicas: %d desired, %d updated, %d total, %d ready", desired, status.UpdatedReplicas,
+		status.Replicas, status.ReadyReplicas)
+	if ref.resource == "deployments" {
+		counts += fmt.Sprintf(", %d available", status.AvailableReplicas)
+	}
+	if revision := workload.Metadata.Annotations[deploymentRevisionAnnotation]; revision != "" {
+		counts += "\nRevision: " + revision
+	}
+	if status.UpdateRevision != "" && status.UpdateRevision != status.CurrentRevision {
+		counts += fmt.Sprintf("\nRevision: %s, updating to %s", status.CurrentRevision, status.UpdateRevision)
+	} else if status.CurrentRevision != "" {
+		counts += "\nRevision: " + status.CurrentRevision
+	}
+	return counts
+}
+
+// rollbackReplicaSet returns the ReplicaSet of the revision to roll back to, the previous revision when toRevision is 0.
+// The ReplicaSets are sorted from the most recent revision.
+func rollbackReplicaSet(replicaSets []kubernetesReplicaSet, toRevision int) (kubernetesReplicaSet, error) {
+	if toRevision == 0 {
+		if len(replicaSets) < 2 {
+			return kubernetesReplicaSet{}, fmt.Errorf("no previous revision found in the rollout history")
+		}
+		return replicaSets[1], nil
+	}
+
+	for _, replicaSet := range replicaSets {
+		if replicaSetRevision(replicaSet) == toRevision {
+			return replicaSet, nil
+		}
+	}
+
+	return kubernetesReplicaSet{}, fmt.Errorf("revision %d not found in the rollout history", toRevision)
+}
+
+// replicaSetRevision returns the revision of a ReplicaSet of a Deployment, 0 when unknown
+func replicaSetRevision(replicaSet kubernetesReplicaSet) int {
+	revision, _ := strconv.Atoi(replicaSet.Metadata.Annotations[deploymentRevisionAnnotation])
+	return revision
+}
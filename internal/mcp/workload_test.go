@@ -0,0 +1,401 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkloadRef(t *testing.T) {
+	tests := []struct {
+		value        string
+		expectedPath string
+		expectedName string
+		expectError  bool
+	}{
+		{value: "deployment/web", expectedPath: "/apis/apps/v1/namespaces/shop/deployments/web", expectedName: "deployment/web"},
+		{value: "sts/db", expectedPath: "/apis/apps/v1/namespaces/shop/statefulsets/db", expectedName: "statefulset/db"},
+		{value: "DaemonSet/agent", expectedPath: "/apis/apps/v1/namespaces/shop/daemonsets/agent", expectedName: "daemonset/agent"},
+		{value: "web", expectError: true},
+		{value: "cronjob/backup", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			ref, err := parseWorkloadRef("shop", tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPath, ref.path())
+			assert.Equal(t, tt.expectedName, ref.String())
+		})
+	}
+}
+
+// matchKubernetesPatch matches the Kubernetes API patches by path and Content-Type
+func matchKubernetesPatch(path, contentType string) any {
+	return mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+		return opts.Method == "PATCH" && opts.Path == path && opts.Headers["Content-Type"] == contentType
+	})
+}
+
+// recordBody records the body of the requests matched by a mock call
+func recordBody(t *testing.T, call *mock.Call, body *string) {
+	call.Run(func(args mock.Arguments) {
+		data, err := io.ReadAll(args.Get(0).(models.KubernetesProxyRequestOptions).Body)
+		require.NoError(t, err)
+		*body = string(data)
+	})
+}
+
+func TestHandleScaleWorkload(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		setupMock     func(t *testing.T, mockClient *MockPortainerClient, patch *string)
+		expectedText  string
+		expectedPatch string
+		expectedError bool
+	}{
+		{
+			name: "scale deployment",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "deploy/web",
+				"replicas":      float64(5),
+			},
+			setupMock: func(t *testing.T, mockClient *MockPortainerClient, patch *string) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web/scale")).
+					Return(createMockHttpResponse(http.StatusOK, `{"spec":{"replicas":2}}`), nil)
+				recordBody(t, mockClient.On("ProxyKubernetesRequest", matchKubernetesPatch("/apis/apps/v1/namespaces/shop/deployments/web/scale", "application/merge-patch+json")).
+					Return(createMockHttpResponse(http.StatusOK, `{"spec":{"replicas":5}}`), nil), patch)
+			},
+			expectedText:  "deployment/web scaled from 2 to 5 replicas",
+			expectedPatch: `{"spec":{"replicas":5}}`,
+		},
+		{
+			name: "replicas unchanged",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "statefulset/db",
+				"replicas":      float64(1),
+			},
+			setupMock: func(t *testing.T, mockClient *MockPortainerClient, patch *string) {
+				mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/statefulsets/db/scale")).
+					Return(createMockHttpResponse(http.StatusOK, `{"spec":{"replicas":1}}`), nil)
+			},
+			expectedText: "statefulset/db already has 1 replica, nothing to do",
+		},
+		{
+			name: "daemonset",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "kube-system",
+				"workload":      "ds/agent",
+				"replicas":      float64(3),
+			},
+			setupMock:     func(t *testing.T, mockClient *MockPortainerClient, patch *string) {},
+			expectedText:  "daemonset/agent cannot be scaled, a DaemonSet runs one pod per eligible node",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			var patch string
+			tt.setupMock(t, mockClient, &patch)
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleScaleWorkload()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+			assert.Equal(t, tt.expectedPatch, patch)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleRestartWorkload(t *testing.T) {
+	t.Run("restart statefulset", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+		mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/statefulsets/db")).
+			Return(createMockHttpResponse(http.StatusOK, `{"metadata":{"name":"db"}}`), nil)
+		var patch string
+		recordBody(t, mockClient.On("ProxyKubernetesRequest", matchKubernetesPatch("/apis/apps/v1/namespaces/shop/statefulsets/db", "application/strategic-merge-patch+json")).
+			Return(createMockHttpResponse(http.StatusOK, `{"metadata":{"name":"db"}}`), nil), &patch)
+
+		server := &PortainerMCPServer{cli: mockClient}
+		result, err := server.HandleRestartWorkload()(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"namespace":     "shop",
+			"workload":      "sts/db",
+		}))
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "statefulset/db restarted, its pods are being replaced. Use getRolloutStatus to follow the rollout.", resultTexts(t, result)[0])
+
+		var decoded struct {
+			Spec struct {
+				Template struct {
+					Metadata struct {
+						Annotations map[string]string `json:"annotations"`
+					} `json:"metadata"`
+				} `json:"template"`
+			} `json:"spec"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(patch), &decoded))
+		assert.NotEmpty(t, decoded.Spec.Template.Metadata.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("paused deployment", func(t *testing.T) {
+		mockClient := new(MockPortainerClient)
+		mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+			Return(createMockHttpResponse(http.StatusOK, `{"spec":{"paused":true}}`), nil)
+
+		server := &PortainerMCPServer{cli: mockClient}
+		result, err := server.HandleRestartWorkload()(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"namespace":     "shop",
+			"workload":      "deployment/web",
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "deployment/web is paused, resume it before restarting it", resultTexts(t, result)[0])
+
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestRolloutStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		workload       string
+		object         string
+		expectedStatus string
+		expectedFailed bool
+	}{
+		{
+			name:           "deployment spec update not observed",
+			workload:       "deployment/web",
+			object:         `{"metadata":{"generation":3},"spec":{"replicas":3},"status":{"observedGeneration":2}}`,
+			expectedStatus: "Waiting for deployment/web spec update to be observed...",
+		},
+		{
+			name:     "deployment updating replicas",
+			workload: "deployment/web",
+			object: `{"metadata":{"generation":3},"spec":{"replicas":3},
+				"status":{"observedGeneration":3,"replicas":4,"updatedReplicas":1,"availableReplicas":3}}`,
+			expectedStatus: "Waiting for deployment/web rollout to finish: 1 out of 3 new replicas have been updated...",
+		},
+		{
+			name:     "deployment terminating old replicas",
+			workload: "deployment/web",
+			object: `{"metadata":{"generation":3},"spec":{"replicas":3},
+				"status":{"observedGeneration":3,"replicas":4,"updatedReplicas":3,"availableReplicas":3}}`,
+			expectedStatus: "Waiting for deployment/web rollout to finish: 1 old replicas are pending termination...",
+		},
+		{
+			name:     "deployment progress deadline exceeded",
+			workload: "deployment/web",
+			object: `{"metadata":{"generation":3},"spec":{"replicas":3},"status":{"observedGeneration":3,"replicas":3,"updatedReplicas":1,
+				"conditions":[{"type":"Progressing","status":"False","reason":"ProgressDeadlineExceeded","message":"ReplicaSet \"web-7d4b9c\" has timed out progressing."}]}}`,
+			expectedStatus: "deployment/web exceeded its progress deadline: ReplicaSet \"web-7d4b9c\" has timed out progressing.",
+			expectedFailed: true,
+		},
+		{
+			name:     "deployment rolled out",
+			workload: "deployment/web",
+			object: `{"metadata":{"generation":3},"spec":{"replicas":3},
+				"status":{"observedGeneration":3,"replicas":3,"updatedReplicas":3,"availableReplicas":3}}`,
+			expectedStatus: "deployment/web successfully rolled out",
+		},
+		{
+			name:     "statefulset partitioned rollout",
+			workload: "statefulset/db",
+			object: `{"metadata":{"generation":2},"spec":{"replicas":3,"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"partition":2}}},
+				"status":{"observedGeneration":2,"replicas":3,"readyReplicas":3,"updatedReplicas":0}}`,
+			expectedStatus: "Waiting for partitioned rollout of statefulset/db to finish: 0 out of 1 new pods have been updated...",
+		},
+		{
+			name:     "statefulset rolling update",
+			workload: "statefulset/db",
+			object: `{"metadata":{"generation":2},"spec":{"replicas":3,"updateStrategy":{"type":"RollingUpdate"}},
+				"status":{"observedGeneration":2,"replicas":3,"readyReplicas":3,"updatedReplicas":1,"currentRevision":"db-1","updateRevision":"db-2"}}`,
+			expectedStatus: "Waiting for statefulset/db rolling update to complete: 1 pods at revision db-2...",
+		},
+		{
+			name:     "statefulset on delete strategy",
+			workload: "statefulset/db",
+			object: `{"metadata":{"generation":2},"spec":{"replicas":3,"updateStrategy":{"type":"OnDelete"}},
+				"status":{"observedGeneration":2}}`,
+			expectedStatus: "The rollout status of statefulset/db is only available with the RollingUpdate strategy, its strategy is OnDelete",
+		},
+		{
+			name:     "daemonset pods not available",
+			workload: "daemonset/agent",
+			object: `{"metadata":{"generation":1},"spec":{"updateStrategy":{"type":"RollingUpdate"}},
+				"status":{"observedGeneration":1,"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":2}}`,
+			expectedStatus: "Waiting for daemonset/agent rollout to finish: 2 of 3 updated pods are available...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := parseWorkloadRef("shop", tt.workload)
+			require.NoError(t, err)
+
+			var workload kubernetesWorkload
+			require.NoError(t, json.Unmarshal([]byte(tt.object), &workload))
+
+			status, failed := rolloutStatus(ref, workload)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedFailed, failed)
+		})
+	}
+}
+
+func TestHandleGetRolloutStatus(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+		Return(createMockHttpResponse(http.StatusOK, `{"metadata":{"generation":3,"annotations":{"deployment.kubernetes.io/revision":"4"}},
+			"spec":{"replicas":3},"status":{"observedGeneration":3,"replicas":3,"updatedReplicas":3,"readyReplicas":2,"availableReplicas":2}}`), nil)
+
+	server := &PortainerMCPServer{cli: mockClient}
+	result, err := server.HandleGetRolloutStatus()(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"namespace":     "shop",
+		"workload":      "deployment/web",
+	}))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Waiting for deployment/web rollout to finish: 2 of 3 updated replicas are available...\n"+
+		"Replicas: 3 desired, 3 updated, 3 total, 2 ready, 2 available\n"+
+		"Revision: 4", resultTexts(t, result)[0])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHandleUndoRollout(t *testing.T) {
+	const (
+		deployment  = `{"metadata":{"uid":"deploy-uid","annotations":{"deployment.kubernetes.io/revision":"3"}},"spec":{"selector":{"matchLabels":{"app":"web"}}}}`
+		replicaSets = `{"items":[
+			{"metadata":{"name":"web-1a","annotations":{"deployment.kubernetes.io/revision":"1"},"ownerReferences":[{"uid":"deploy-uid"}]},
+				"spec":{"template":{"metadata":{"labels":{"app":"web","pod-template-hash":"1a"}},"spec":{"containers":[{"name":"app","image":"shop/web:1.0"}]}}}},
+			{"metadata":{"name":"web-3c","annotations":{"deployment.kubernetes.io/revision":"3"},"ownerReferences":[{"uid":"deploy-uid"}]},
+				"spec":{"template":{"metadata":{"labels":{"app":"web","pod-template-hash":"3c"}},"spec":{"containers":[{"name":"app","image":"shop/web:1.2"}]}}}},
+			{"metadata":{"name":"web-2b","annotations":{"deployment.kubernetes.io/revision":"2"},"ownerReferences":[{"uid":"deploy-uid"}]},
+				"spec":{"template":{"metadata":{"labels":{"app":"web","pod-template-hash":"2b"}},"spec":{"containers":[{"name":"app","image":"shop/web:1.1"}]}}}},
+			{"metadata":{"name":"web-legacy","annotations":{"deployment.kubernetes.io/revision":"9"},"ownerReferences":[{"uid":"other-uid"}]}}
+		]}`
+	)
+
+	tests := []struct {
+		name          string
+		input         map[string]any
+		expectPatch   bool
+		expectedText  string
+		expectedPatch string
+		expectedError bool
+	}{
+		{
+			name: "previous revision",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "deployment/web",
+			},
+			expectPatch:   true,
+			expectedText:  "deployment/web rolled back to revision 2 (ReplicaSet web-2b). Use getRolloutStatus to follow the rollout.",
+			expectedPatch: `[{"op":"replace","path":"/spec/template","value":{"metadata":{"labels":{"app":"web"}},"spec":{"containers":[{"image":"shop/web:1.1","name":"app"}]}}}]`,
+		},
+		{
+			name: "specific revision",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "deployment/web",
+				"toRevision":    float64(1),
+			},
+			expectPatch:   true,
+			expectedText:  "deployment/web rolled back to revision 1 (ReplicaSet web-1a). Use getRolloutStatus to follow the rollout.",
+			expectedPatch: `[{"op":"replace","path":"/spec/template","value":{"metadata":{"labels":{"app":"web"}},"spec":{"containers":[{"image":"shop/web:1.0","name":"app"}]}}}]`,
+		},
+		{
+			name: "current revision",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "deployment/web",
+				"toRevision":    float64(3),
+			},
+			expectedText: "deployment/web is already at revision 3, nothing to do",
+		},
+		{
+			name: "unknown revision",
+			input: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "shop",
+				"workload":      "deployment/web",
+				"toRevision":    float64(9),
+			},
+			expectedText:  "cannot roll back deployment/web: revision 9 not found in the rollout history",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			mockClient.On("ProxyKubernetesRequest", matchKubernetesRequest("GET", "/apis/apps/v1/namespaces/shop/deployments/web")).
+				Return(createMockHttpResponse(http.StatusOK, deployment), nil)
+			mockClient.On("ProxyKubernetesRequest", mock.MatchedBy(func(opts models.KubernetesProxyRequestOptions) bool {
+				return opts.Path == "/apis/apps/v1/namespaces/shop/replicasets" && opts.QueryParams["labelSelector"] == "app=web"
+			})).Return(createMockHttpResponse(http.StatusOK, replicaSets), nil)
+
+			var patch string
+			if tt.expectPatch {
+				recordBody(t, mockClient.On("ProxyKubernetesRequest", matchKubernetesPatch("/apis/apps/v1/namespaces/shop/deployments/web", "application/json-patch+json")).
+					Return(createMockHttpResponse(http.StatusOK, deployment), nil), &patch)
+			}
+
+			server := &PortainerMCPServer{
+				cli: mockClient,
+			}
+
+			handler := server.HandleUndoRollout()
+			result, err := handler(context.Background(), CreateMCPRequest(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedError, result.IsError)
+			assert.Equal(t, tt.expectedText, resultTexts(t, result)[0])
+			assert.Equal(t, tt.expectedPatch, patch)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
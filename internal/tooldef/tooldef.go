@@ -8,11 +8,25 @@ import (
 //go:embed tools.yaml
 var ToolsFile []byte
 
+//go:embed prompts.yaml
+var PromptsFile []byte
+
 // CreateToolsFileIfNotExists creates the tools.yaml file if it doesn't exist
 // It returns true if the file already exists, false if it was created or an error occurred
 func CreateToolsFileIfNotExists(path string) (bool, error) {
+	return createFileIfNotExists(path, ToolsFile)
+}
+
+// CreatePromptsFileIfNotExists creates the prompts.yaml file if it doesn't exist
+// It returns true if the file already exists, false if it was created or an error occurred
+func CreatePromptsFileIfNotExists(path string) (bool, error) {
+	return createFileIfNotExists(path, PromptsFile)
+}
+
+// createFileIfNotExists writes content to path if no file exists there yet
+func createFileIfNotExists(path string, content []byte) (bool, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		err = os.WriteFile(path, ToolsFile, 0644)
+		err = os.WriteFile(path, content, 0644)
 		if err != nil {
 			return false, err
 		}
@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestCreateToolsFileIfNotExists(t *testing.T) {
@@ -66,3 +68,48 @@ func TestCreateToolsFileIfNotExists(t *testing.T) {
 		assert.False(t, exists, "Function should return false when an error occurs")
 	})
 }
+
+func TestCreatePromptsFileIfNotExists(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("File Does Not Exist", func(t *testing.T) {
+		filePath := filepath.Join(tempDir, "new-prompts.yaml")
+
+		exists, err := CreatePromptsFileIfNotExists(filePath)
+		require.NoError(t, err, "Function should not return an error")
+		assert.False(t, exists, "Function should return false when creating a new file")
+
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err, "Should be able to read the created file")
+		assert.Equal(t, PromptsFile, content, "File should contain the embedded prompts content")
+	})
+
+	t.Run("File Already Exists", func(t *testing.T) {
+		filePath := filepath.Join(tempDir, "existing-prompts.yaml")
+
+		customContent := []byte("# Custom prompts file content")
+		err := os.WriteFile(filePath, customContent, 0644)
+		require.NoError(t, err, "Failed to create test file")
+
+		exists, err := CreatePromptsFileIfNotExists(filePath)
+		require.NoError(t, err, "Function should not return an error")
+		assert.True(t, exists, "Function should return true when file already exists")
+
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err, "Should be able to read the existing file")
+		assert.Equal(t, customContent, content, "Function should not modify an existing file")
+	})
+}
+
+func TestEmbeddedToolsFileIsValid(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tools.yaml")
+	_, err := CreateToolsFileIfNotExists(filePath)
+	require.NoError(t, err)
+
+	var config toolgen.ToolsConfig
+	require.NoError(t, yaml.Unmarshal(ToolsFile, &config))
+
+	tools, err := toolgen.LoadToolsFromYAML(filePath, "v1.0")
+	require.NoError(t, err)
+	assert.Len(t, tools, len(config.Tools), "All the embedded tool definitions should be valid")
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strconv"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/portainer/client-api-go/v2/client"
+	apiclient "github.com/portainer/client-api-go/v2/pkg/client"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
+	"github.com/portainer/client-api-go/v2/pkg/client/helm"
+	"github.com/portainer/client-api-go/v2/pkg/client/registries"
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+)
+
+// totalCountHeader is the header of the paginated Portainer responses holding the total number of items
+const totalCountHeader = "X-Total-Count"
+
+// apiClient extends the Portainer SDK client with the operations that the SDK
+// does not wrap, using the generated Portainer API client directly.
+type apiClient struct {
+	*client.PortainerClient
+	api *apiclient.PortainerClientAPI
+}
+
+// newAPIClient creates an apiClient configured like the underlying SDK client.
+func newAPIClient(serverURL string, token string, options clientOptions) *apiClient {
+	var sdkOpts []client.ClientOption
+	if options.skipTLSVerify {
+		sdkOpts = append(sdkOpts, client.WithSkipTLSVerify(options.skipTLSVerify))
+	}
+	if options.basePath != "/api" {
+		sdkOpts = append(sdkOpts, client.WithBasePath(options.basePath))
+	}
+
+	transport := httptransport.New(serverURL, options.basePath, []string{"https"})
+	if options.skipTLSVerify {
+		// The default transport is cloned to keep its proxy, timeouts and HTTP/2 settings
+		httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+		httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		transport.Transport = httpTransport
+	}
+	transport.DefaultAuthentication = runtime.ClientAuthInfoWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+		return r.SetHeaderParam("x-api-key", token)
+	})
+
+	return &apiClient{
+		PortainerClient: client.NewPortainerClient(serverURL, token, sdkOpts...),
+		api:             apiclient.New(transport, nil),
+	}
+}
+
+// ListEndpointsWithParams lists a page of the endpoints matching the provided filter parameters, sorted by the
+// sort key (the ID order when empty), along with the total number of matching endpoints.
+// The generated client neither sends the sort key and the order as expected by Portainer nor exposes the total
+// count header, the operation is adjusted to do both.
+func (c *apiClient) ListEndpointsWithParams(params *endpoints.EndpointListParams, sort string, descending bool) ([]*apimodels.PortainereeEndpoint, int, error) {
+	totalCount := -1
+	resp, err := c.api.Endpoints.EndpointList(params, nil, func(op *runtime.ClientOperation) {
+		writer := op.Params
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, reg strfmt.Registry) error {
+			if err := writer.WriteToRequest(r, reg); err != nil {
+				return err
+			}
+			if sort == "" {
+				return nil
+			}
+			if err := r.SetQueryParam("sort", sort); err != nil {
+				return err
+			}
+			order := "asc"
+			if descending {
+				order = "desc"
+			}
+			return r.SetQueryParam("order", order)
+		})
+
+		reader := op.Reader
+		op.Reader = runtime.ClientResponseReaderFunc(func(response runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
+			if count, err := strconv.Atoi(response.GetHeader(totalCountHeader)); err == nil {
+				totalCount = count
+			}
+			return reader.ReadResponse(response, consumer)
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Without the header, only the returned endpoints are known
+	if totalCount < 0 {
+		totalCount = len(resp.Payload)
+		if params.Start != nil {
+			totalCount += int(*params.Start)
+		}
+	}
+
+	return resp.Payload, totalCount, nil
+}
+
+// ListHelmReleases lists the Helm releases of an environment, in all the namespaces when namespace is empty
+func (c *apiClient) ListHelmReleases(environmentId int64, namespace string) ([]*apimodels.ReleaseReleaseElement, error) {
+	params := helm.NewHelmListParams().WithID(environmentId)
+	if namespace != "" {
+		params.SetNamespace(&namespace)
+	}
+
+	resp, err := c.api.Helm.HelmList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// GetHelmRelease retrieves a revision of a Helm release, the latest revision when revision is 0
+func (c *apiClient) GetHelmRelease(environmentId int64, name string, namespace string, revision int64) (*apimodels.ReleaseRelease, error) {
+	params := helm.NewHelmGetParams().WithID(environmentId).WithName(name)
+	if namespace != "" {
+		params.SetNamespace(&namespace)
+	}
+	if revision > 0 {
+		params.SetRevision(&revision)
+	}
+
+	resp, err := c.api.Helm.HelmGet(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// GetHelmReleaseHistory retrieves the revisions of a Helm release
+func (c *apiClient) GetHelmReleaseHistory(environmentId int64, name string, namespace string) ([]*apimodels.ReleaseRelease, error) {
+	params := helm.NewHelmGetHistoryParams().WithID(environmentId).WithName(name)
+	if namespace != "" {
+		params.SetNamespace(&namespace)
+	}
+
+	resp, err := c.api.Helm.HelmGetHistory(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// InstallHelmChart installs a Helm chart, or upgrades the release when it already exists
+func (c *apiClient) InstallHelmChart(environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error) {
+	params := helm.NewHelmInstallParams().WithID(environmentId).WithPayload(payload)
+
+	resp, err := c.api.Helm.HelmInstall(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// DeleteHelmRelease uninstalls a Helm release
+func (c *apiClient) DeleteHelmRelease(environmentId int64, name string, namespace string) error {
+	params := helm.NewHelmDeleteParams().WithID(environmentId).WithRelease(name)
+	if namespace != "" {
+		params.SetNamespace(&namespace)
+	}
+
+	if _, err := c.api.Helm.HelmDelete(params, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListRegistries lists the registries configured in Portainer that the user can access
+func (c *apiClient) ListRegistries() ([]*apimodels.PortainereeRegistry, error) {
+	resp, err := c.api.Registries.RegistryList(registries.NewRegistryListParams(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
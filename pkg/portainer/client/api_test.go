@@ -0,0 +1,70 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEndpointsWithParams(t *testing.T) {
+	tests := []struct {
+		name          string
+		sort          string
+		descending    bool
+		totalCount    string
+		expectedQuery url.Values
+		expectedTotal int
+	}{
+		{
+			name:          "sorted page with the total count",
+			sort:          "Name",
+			descending:    true,
+			totalCount:    "7",
+			expectedQuery: url.Values{"sort": {"Name"}, "order": {"desc"}, "start": {"2"}, "limit": {"2"}, "excludeSnapshots": {"true"}},
+			expectedTotal: 7,
+		},
+		{
+			name:          "ID order without the total count header",
+			expectedQuery: url.Values{"start": {"2"}, "limit": {"2"}, "excludeSnapshots": {"true"}},
+			expectedTotal: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/endpoints", r.URL.Path)
+				assert.Equal(t, "test-token", r.Header.Get("x-api-key"))
+				query = r.URL.Query()
+
+				if tt.totalCount != "" {
+					w.Header().Set(totalCountHeader, tt.totalCount)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[{"Id":3,"Name":"env3"},{"Id":4,"Name":"env4"}]`))
+			}))
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL)
+			require.NoError(t, err)
+			cli := newAPIClient(serverURL.Host, "test-token", clientOptions{skipTLSVerify: true, basePath: "/api"})
+
+			start, limit, excludeSnapshots := int64(2), int64(2), true
+			params := endpoints.NewEndpointListParams().WithStart(&start).WithLimit(&limit).WithExcludeSnapshots(&excludeSnapshots)
+
+			endpointList, totalCount, err := cli.ListEndpointsWithParams(params, tt.sort, tt.descending)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedQuery, query)
+			assert.Equal(t, tt.expectedTotal, totalCount)
+			require.Len(t, endpointList, 2)
+			assert.Equal(t, int64(3), endpointList[0].ID)
+		})
+	}
+}
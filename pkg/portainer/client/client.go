@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/portainer/client-api-go/v2/client"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
 )
 
@@ -22,6 +23,7 @@ type PortainerAPIClient interface {
 	AddEnvironmentToEndpointGroup(groupId int64, environmentId int64) error
 	RemoveEnvironmentFromEndpointGroup(groupId int64, environmentId int64) error
 	ListEndpoints() ([]*apimodels.PortainereeEndpoint, error)
+	ListEndpointsWithParams(params *endpoints.EndpointListParams, sort string, descending bool) ([]*apimodels.PortainereeEndpoint, int, error)
 	GetEndpoint(id int64) (*apimodels.PortainereeEndpoint, error)
 	UpdateEndpoint(id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
 	GetSettings() (*apimodels.PortainereeSettings, error)
@@ -38,6 +40,12 @@ type PortainerAPIClient interface {
 	GetVersion() (string, error)
 	ProxyDockerRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
 	ProxyKubernetesRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
+	ListHelmReleases(environmentId int64, namespace string) ([]*apimodels.ReleaseReleaseElement, error)
+	GetHelmRelease(environmentId int64, name string, namespace string, revision int64) (*apimodels.ReleaseRelease, error)
+	GetHelmReleaseHistory(environmentId int64, name string, namespace string) ([]*apimodels.ReleaseRelease, error)
+	InstallHelmChart(environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error)
+	DeleteHelmRelease(environmentId int64, name string, namespace string) error
+	ListRegistries() ([]*apimodels.PortainereeRegistry, error)
 }
 
 // PortainerClient is a wrapper around the Portainer SDK client
@@ -92,16 +100,7 @@ func NewPortainerClient(serverURL string, token string, opts ...ClientOption) *P
 		opt(&options)
 	}
 
-	// Build client options for the underlying client
-	var clientOpts []client.ClientOption
-	if options.skipTLSVerify {
-		clientOpts = append(clientOpts, client.WithSkipTLSVerify(options.skipTLSVerify))
-	}
-	if options.basePath != "/api" {
-		clientOpts = append(clientOpts, client.WithBasePath(options.basePath))
-	}
-
 	return &PortainerClient{
-		cli: client.NewPortainerClient(serverURL, token, clientOpts...),
+		cli: newAPIClient(serverURL, token, options),
 	}
 }
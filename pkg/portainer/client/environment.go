@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/portainer/portainer-mcp/pkg/portainer/utils"
 )
@@ -26,6 +27,76 @@ func (c *PortainerClient) GetEnvironments() ([]models.Environment, error) {
 	return environments, nil
 }
 
+// GetEnvironmentsWithFilter retrieves a page of the environments matching the provided filter.
+// The filters, the sort and the pagination are passed through to the Portainer API so that they happen server-side.
+//
+// Parameters:
+//   - filter: The filter to apply, empty fields are ignored
+//
+// Returns:
+//   - A slice of Environment objects, the page starting at filter.Start
+//   - The total number of environments matching the filter
+//   - An error if the filter is invalid or the operation fails
+func (c *PortainerClient) GetEnvironmentsWithFilter(filter models.EnvironmentFilter) ([]models.Environment, int, error) {
+	excludeSnapshots := true
+	params := endpoints.NewEndpointListParams().WithExcludeSnapshots(&excludeSnapshots)
+
+	if filter.Search != "" {
+		params.SetSearch(&filter.Search)
+	}
+
+	for _, status := range filter.Statuses {
+		endpointStatus, ok := models.ConvertEnvironmentStatusToEndpointStatus(status)
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid environment status: %s", status)
+		}
+		params.Status = append(params.Status, endpointStatus)
+	}
+
+	for _, environmentType := range filter.Types {
+		endpointType, ok := models.ConvertEnvironmentTypeToEndpointType(environmentType)
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid environment type: %s", environmentType)
+		}
+		params.Types = append(params.Types, endpointType)
+	}
+
+	if len(filter.TagIds) > 0 {
+		params.SetTagIds(utils.IntToInt64Slice(filter.TagIds))
+	}
+
+	if len(filter.GroupIds) > 0 {
+		params.SetGroupIds(utils.IntToInt64Slice(filter.GroupIds))
+	}
+
+	sort, ok := models.ConvertEnvironmentSortToEndpointSort(filter.SortBy)
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid environment sort field: %s", filter.SortBy)
+	}
+
+	if filter.Start > 0 {
+		start := int64(filter.Start)
+		params.SetStart(&start)
+	}
+
+	if filter.Limit > 0 {
+		limit := int64(filter.Limit)
+		params.SetLimit(&limit)
+	}
+
+	endpointList, totalCount, err := c.cli.ListEndpointsWithParams(params, sort, filter.Descending)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+
+	environments := make([]models.Environment, len(endpointList))
+	for i, endpoint := range endpointList {
+		environments[i] = models.ConvertEndpointToEnvironment(endpoint)
+	}
+
+	return environments, totalCount, nil
+}
+
 // UpdateEnvironmentTags updates the tags associated with an environment.
 //
 // Parameters:
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/stretchr/testify/assert"
@@ -132,6 +133,122 @@ func TestGetEnvironments(t *testing.T) {
 	}
 }
 
+func TestGetEnvironmentsWithFilter(t *testing.T) {
+	search := "prod"
+
+	tests := []struct {
+		name           string
+		filter         models.EnvironmentFilter
+		expectedParams func(*endpoints.EndpointListParams)
+		expectedSort   string
+		mockEndpoints  []*apimodels.PortainereeEndpoint
+		mockTotal      int
+		mockError      error
+		expected       []models.Environment
+		expectedTotal  int
+		expectedError  string
+	}{
+		{
+			name:   "empty filter",
+			filter: models.EnvironmentFilter{},
+			expectedParams: func(p *endpoints.EndpointListParams) {
+				assert.Nil(t, p.Search)
+				assert.Empty(t, p.Status)
+				assert.Empty(t, p.Types)
+				assert.Empty(t, p.TagIds)
+				assert.Empty(t, p.GroupIds)
+				assert.Nil(t, p.Start)
+				assert.Nil(t, p.Limit)
+				assert.True(t, *p.ExcludeSnapshots)
+			},
+			mockEndpoints: []*apimodels.PortainereeEndpoint{
+				{ID: 1, Name: "env1", Status: 1, Type: 1},
+			},
+			mockTotal: 1,
+			expected: []models.Environment{
+				{ID: 1, Name: "env1", Status: models.EnvironmentStatusActive, Type: models.EnvironmentTypeDockerLocal, TagIds: []int{}, UserAccesses: map[int]string{}, TeamAccesses: map[int]string{}},
+			},
+			expectedTotal: 1,
+		},
+		{
+			name: "all filters passed through",
+			filter: models.EnvironmentFilter{
+				Search:     search,
+				Statuses:   []string{models.EnvironmentStatusInactive},
+				Types:      []string{models.EnvironmentTypeKubernetesAgent, models.EnvironmentTypeDockerEdgeAgent},
+				TagIds:     []int{3, 4},
+				GroupIds:   []int{2},
+				SortBy:     models.EnvironmentSortByName,
+				Descending: true,
+				Start:      20,
+				Limit:      10,
+			},
+			expectedParams: func(p *endpoints.EndpointListParams) {
+				assert.Equal(t, search, *p.Search)
+				assert.Equal(t, []int64{2}, p.Status)
+				assert.Equal(t, []int64{6, 4}, p.Types)
+				assert.Equal(t, []int64{3, 4}, p.TagIds)
+				assert.Equal(t, []int64{2}, p.GroupIds)
+				assert.Equal(t, int64(20), *p.Start)
+				assert.Equal(t, int64(10), *p.Limit)
+			},
+			expectedSort:  "Name",
+			mockEndpoints: []*apimodels.PortainereeEndpoint{},
+			mockTotal:     20,
+			expected:      []models.Environment{},
+			expectedTotal: 20,
+		},
+		{
+			name:          "invalid status",
+			filter:        models.EnvironmentFilter{Statuses: []string{"sleeping"}},
+			expectedError: "invalid environment status: sleeping",
+		},
+		{
+			name:          "invalid type",
+			filter:        models.EnvironmentFilter{Types: []string{"nomad"}},
+			expectedError: "invalid environment type: nomad",
+		},
+		{
+			name:          "invalid sort field",
+			filter:        models.EnvironmentFilter{SortBy: "type"},
+			expectedError: "invalid environment sort field: type",
+		},
+		{
+			name:           "list error",
+			filter:         models.EnvironmentFilter{},
+			expectedParams: func(p *endpoints.EndpointListParams) {},
+			mockError:      errors.New("api error"),
+			expectedError:  "failed to list endpoints",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			if tt.expectedParams != nil {
+				mockAPI.On("ListEndpointsWithParams", mock.MatchedBy(func(p *endpoints.EndpointListParams) bool {
+					tt.expectedParams(p)
+					return true
+				}), tt.expectedSort, tt.filter.Descending).Return(tt.mockEndpoints, tt.mockTotal, tt.mockError)
+			}
+
+			client := &PortainerClient{cli: mockAPI}
+
+			environments, totalCount, err := client.GetEnvironmentsWithFilter(tt.filter)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, environments)
+			assert.Equal(t, tt.expectedTotal, totalCount)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdateEnvironmentTags(t *testing.T) {
 	tests := []struct {
 		name          string
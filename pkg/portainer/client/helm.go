@@ -0,0 +1,135 @@
+package client
+
+import (
+	"fmt"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"gopkg.in/yaml.v3"
+)
+
+// GetHelmReleases retrieves the Helm releases of a Kubernetes environment.
+//
+// Parameters:
+//   - environmentId: The ID of the environment
+//   - namespace: The namespace of the releases, all the namespaces are searched when empty
+//
+// Returns:
+//   - A slice of HelmRelease objects, with the latest revision of each release
+//   - An error if the operation fails
+func (c *PortainerClient) GetHelmReleases(environmentId int, namespace string) ([]models.HelmRelease, error) {
+	rawReleases, err := c.cli.ListHelmReleases(int64(environmentId), namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list helm releases: %w", err)
+	}
+
+	releases := make([]models.HelmRelease, len(rawReleases))
+	for i, rawRelease := range rawReleases {
+		releases[i] = models.ConvertReleaseElementToHelmRelease(rawRelease)
+	}
+
+	return releases, nil
+}
+
+// GetHelmReleaseValues retrieves the values of a Helm release revision.
+//
+// Parameters:
+//   - environmentId: The ID of the environment
+//   - name: The name of the release
+//   - namespace: The namespace of the release
+//   - revision: The revision of the release, the latest revision is used when 0
+//
+// Returns:
+//   - The user supplied and computed values of the revision
+//   - An error if the operation fails
+func (c *PortainerClient) GetHelmReleaseValues(environmentId int, name string, namespace string, revision int) (models.HelmReleaseValues, error) {
+	rawRelease, err := c.cli.GetHelmRelease(int64(environmentId), name, namespace, int64(revision))
+	if err != nil {
+		return models.HelmReleaseValues{}, fmt.Errorf("failed to get helm release: %w", err)
+	}
+
+	if rawRelease.Values != nil {
+		return models.HelmReleaseValues{
+			UserSupplied: rawRelease.Values.UserSuppliedValues,
+			Computed:     rawRelease.Values.ComputedValues,
+		}, nil
+	}
+
+	// Without the values computed by Portainer, the user supplied values are read from the release config
+	values := models.HelmReleaseValues{}
+	if len(rawRelease.Config) > 0 {
+		config, err := yaml.Marshal(rawRelease.Config)
+		if err != nil {
+			return models.HelmReleaseValues{}, fmt.Errorf("failed to encode helm release values: %w", err)
+		}
+		values.UserSupplied = string(config)
+	}
+
+	return values, nil
+}
+
+// GetHelmReleaseHistory retrieves the revisions of a Helm release.
+//
+// Parameters:
+//   - environmentId: The ID of the environment
+//   - name: The name of the release
+//   - namespace: The namespace of the release
+//
+// Returns:
+//   - A slice of HelmRelease objects, one per revision
+//   - An error if the operation fails
+func (c *PortainerClient) GetHelmReleaseHistory(environmentId int, name string, namespace string) ([]models.HelmRelease, error) {
+	rawReleases, err := c.cli.GetHelmReleaseHistory(int64(environmentId), name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list helm release revisions: %w", err)
+	}
+
+	releases := make([]models.HelmRelease, len(rawReleases))
+	for i, rawRelease := range rawReleases {
+		releases[i] = models.ConvertReleaseToHelmRelease(rawRelease)
+	}
+
+	return releases, nil
+}
+
+// InstallHelmChart installs a Helm chart from a repository, or upgrades the release when it already exists.
+//
+// Parameters:
+//   - environmentId: The ID of the environment
+//   - opts: The release, the chart and the values to install
+//
+// Returns:
+//   - The HelmRelease revision that was deployed
+//   - An error if the operation fails
+func (c *PortainerClient) InstallHelmChart(environmentId int, opts models.HelmInstallOptions) (models.HelmRelease, error) {
+	rawRelease, err := c.cli.InstallHelmChart(int64(environmentId), &apimodels.HelmInstallChartPayload{
+		Name:      opts.Name,
+		Namespace: opts.Namespace,
+		Chart:     opts.Chart,
+		Repo:      opts.Repo,
+		Version:   opts.Version,
+		Values:    opts.Values,
+		Atomic:    opts.Atomic,
+	})
+	if err != nil {
+		return models.HelmRelease{}, fmt.Errorf("failed to install helm chart: %w", err)
+	}
+
+	return models.ConvertReleaseToHelmRelease(rawRelease), nil
+}
+
+// UninstallHelmRelease uninstalls a Helm release.
+//
+// Parameters:
+//   - environmentId: The ID of the environment
+//   - name: The name of the release
+//   - namespace: The namespace of the release
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) UninstallHelmRelease(environmentId int, name string, namespace string) error {
+	if err := c.cli.DeleteHelmRelease(int64(environmentId), name, namespace); err != nil {
+		return fmt.Errorf("failed to delete helm release: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHelmReleases(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		mockReleases  []*apimodels.ReleaseReleaseElement
+		mockError     error
+		expected      []models.HelmRelease
+		expectedError bool
+	}{
+		{
+			name:      "successful retrieval",
+			namespace: "shop",
+			mockReleases: []*apimodels.ReleaseReleaseElement{
+				{Name: "shop-db", Namespace: "shop", Revision: "2", Chart: "postgresql-15.5.0", AppVersion: "16.3.0", Status: "deployed"},
+			},
+			expected: []models.HelmRelease{
+				{Name: "shop-db", Namespace: "shop", Revision: 2, Chart: "postgresql-15.5.0", AppVersion: "16.3.0", Status: "deployed"},
+			},
+		},
+		{
+			name:         "no releases",
+			mockReleases: []*apimodels.ReleaseReleaseElement{},
+			expected:     []models.HelmRelease{},
+		},
+		{
+			name:          "list error",
+			mockError:     errors.New("failed to list releases"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ListHelmReleases", int64(1), tt.namespace).Return(tt.mockReleases, tt.mockError)
+
+			client := &PortainerClient{cli: mockAPI}
+
+			releases, err := client.GetHelmReleases(1, tt.namespace)
+
+			if tt.expectedError {
+				assert.ErrorIs(t, err, tt.mockError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, releases)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHelmReleaseValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockRelease   *apimodels.ReleaseRelease
+		mockError     error
+		expected      models.HelmReleaseValues
+		expectedError bool
+	}{
+		{
+			name: "values computed by Portainer",
+			mockRelease: &apimodels.ReleaseRelease{
+				Values: &apimodels.ReleaseValues{
+					UserSuppliedValues: "auth:\n  database: shop\n",
+					ComputedValues:     "auth:\n  database: shop\nprimary:\n  persistence:\n    size: 8Gi\n",
+				},
+			},
+			expected: models.HelmReleaseValues{
+				UserSupplied: "auth:\n  database: shop\n",
+				Computed:     "auth:\n  database: shop\nprimary:\n  persistence:\n    size: 8Gi\n",
+			},
+		},
+		{
+			name: "values from the release config",
+			mockRelease: &apimodels.ReleaseRelease{
+				Config: map[string]any{"auth": map[string]any{"database": "shop"}},
+			},
+			expected: models.HelmReleaseValues{
+				UserSupplied: "auth:\n    database: shop\n",
+			},
+		},
+		{
+			name:          "get error",
+			mockError:     errors.New("release not found"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("GetHelmRelease", int64(1), "shop-db", "shop", int64(2)).Return(tt.mockRelease, tt.mockError)
+
+			client := &PortainerClient{cli: mockAPI}
+
+			values, err := client.GetHelmReleaseValues(1, "shop-db", "shop", 2)
+
+			if tt.expectedError {
+				assert.ErrorIs(t, err, tt.mockError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, values)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHelmReleaseHistory(t *testing.T) {
+	mockAPI := new(MockPortainerAPI)
+	mockAPI.On("GetHelmReleaseHistory", int64(1), "shop-db", "shop").Return([]*apimodels.ReleaseRelease{
+		{Name: "shop-db", Namespace: "shop", Version: 1, Info: &apimodels.ReleaseInfo{Status: "superseded", Description: "Install complete"}},
+		{Name: "shop-db", Namespace: "shop", Version: 2, Info: &apimodels.ReleaseInfo{Status: "deployed", Description: "Upgrade complete"}},
+	}, nil)
+
+	client := &PortainerClient{cli: mockAPI}
+
+	history, err := client.GetHelmReleaseHistory(1, "shop-db", "shop")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.HelmRelease{
+		{Name: "shop-db", Namespace: "shop", Revision: 1, Status: "superseded", Description: "Install complete"},
+		{Name: "shop-db", Namespace: "shop", Revision: 2, Status: "deployed", Description: "Upgrade complete"},
+	}, history)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestInstallHelmChart(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockRelease   *apimodels.ReleaseRelease
+		mockError     error
+		expected      models.HelmRelease
+		expectedError bool
+	}{
+		{
+			name: "successful installation",
+			mockRelease: &apimodels.ReleaseRelease{
+				Name:      "shop-db",
+				Namespace: "shop",
+				Version:   1,
+				Info:      &apimodels.ReleaseInfo{Status: "deployed"},
+			},
+			expected: models.HelmRelease{Name: "shop-db", Namespace: "shop", Revision: 1, Status: "deployed"},
+		},
+		{
+			name:          "install error",
+			mockError:     errors.New("chart not found"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("InstallHelmChart", int64(1), &apimodels.HelmInstallChartPayload{
+				Name:      "shop-db",
+				Namespace: "shop",
+				Chart:     "postgresql",
+				Repo:      "https://charts.bitnami.com/bitnami",
+				Version:   "15.5.0",
+				Values:    "auth:\n  database: shop\n",
+				Atomic:    true,
+			}).Return(tt.mockRelease, tt.mockError)
+
+			client := &PortainerClient{cli: mockAPI}
+
+			release, err := client.InstallHelmChart(1, models.HelmInstallOptions{
+				Name:      "shop-db",
+				Namespace: "shop",
+				Chart:     "postgresql",
+				Repo:      "https://charts.bitnami.com/bitnami",
+				Version:   "15.5.0",
+				Values:    "auth:\n  database: shop\n",
+				Atomic:    true,
+			})
+
+			if tt.expectedError {
+				assert.ErrorIs(t, err, tt.mockError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, release)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUninstallHelmRelease(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockError     error
+		expectedError bool
+	}{
+		{
+			name: "successful uninstallation",
+		},
+		{
+			name:          "delete error",
+			mockError:     errors.New("release not found"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("DeleteHelmRelease", int64(1), "shop-db", "shop").Return(tt.mockError)
+
+			client := &PortainerClient{cli: mockAPI}
+
+			err := client.UninstallHelmRelease(1, "shop-db", "shop")
+
+			if tt.expectedError {
+				assert.ErrorIs(t, err, tt.mockError)
+				return
+			}
+			assert.NoError(t, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/portainer/client-api-go/v2/client"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -143,6 +144,15 @@ func (m *MockPortainerAPI) ListEndpoints() ([]*apimodels.PortainereeEndpoint, er
 	return args.Get(0).([]*apimodels.PortainereeEndpoint), args.Error(1)
 }
 
+// ListEndpointsWithParams mocks the ListEndpointsWithParams method
+func (m *MockPortainerAPI) ListEndpointsWithParams(params *endpoints.EndpointListParams, sort string, descending bool) ([]*apimodels.PortainereeEndpoint, int, error) {
+	args := m.Called(params, sort, descending)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*apimodels.PortainereeEndpoint), args.Int(1), args.Error(2)
+}
+
 // GetEndpoint mocks the GetEndpoint method
 func (m *MockPortainerAPI) GetEndpoint(id int64) (*apimodels.PortainereeEndpoint, error) {
 	args := m.Called(id)
@@ -262,3 +272,54 @@ func (m *MockPortainerAPI) ProxyKubernetesRequest(environmentId int, opts client
 	}
 	return args.Get(0).(*http.Response), args.Error(1)
 }
+
+// ListHelmReleases mocks the ListHelmReleases method
+func (m *MockPortainerAPI) ListHelmReleases(environmentId int64, namespace string) ([]*apimodels.ReleaseReleaseElement, error) {
+	args := m.Called(environmentId, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apimodels.ReleaseReleaseElement), args.Error(1)
+}
+
+// GetHelmRelease mocks the GetHelmRelease method
+func (m *MockPortainerAPI) GetHelmRelease(environmentId int64, name string, namespace string, revision int64) (*apimodels.ReleaseRelease, error) {
+	args := m.Called(environmentId, name, namespace, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apimodels.ReleaseRelease), args.Error(1)
+}
+
+// GetHelmReleaseHistory mocks the GetHelmReleaseHistory method
+func (m *MockPortainerAPI) GetHelmReleaseHistory(environmentId int64, name string, namespace string) ([]*apimodels.ReleaseRelease, error) {
+	args := m.Called(environmentId, name, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apimodels.ReleaseRelease), args.Error(1)
+}
+
+// InstallHelmChart mocks the InstallHelmChart method
+func (m *MockPortainerAPI) InstallHelmChart(environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error) {
+	args := m.Called(environmentId, payload)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apimodels.ReleaseRelease), args.Error(1)
+}
+
+// DeleteHelmRelease mocks the DeleteHelmRelease method
+func (m *MockPortainerAPI) DeleteHelmRelease(environmentId int64, name string, namespace string) error {
+	args := m.Called(environmentId, name, namespace)
+	return args.Error(0)
+}
+
+// ListRegistries mocks the ListRegistries method
+func (m *MockPortainerAPI) ListRegistries() ([]*apimodels.PortainereeRegistry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apimodels.PortainereeRegistry), args.Error(1)
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+)
+
+// GetRegistries retrieves the registries configured in Portainer that the user can access.
+//
+// Returns:
+//   - A slice of Registry objects
+//   - An error if the operation fails
+func (c *PortainerClient) GetRegistries() ([]models.Registry, error) {
+	rawRegistries, err := c.cli.ListRegistries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registries: %w", err)
+	}
+
+	registries := make([]models.Registry, len(rawRegistries))
+	for i, rawRegistry := range rawRegistries {
+		registries[i] = models.ConvertToRegistry(rawRegistry)
+	}
+
+	return registries, nil
+}
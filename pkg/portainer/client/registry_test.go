@@ -0,0 +1,61 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRegistries(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockRegistries []*apimodels.PortainereeRegistry
+		mockError      error
+		expected       []models.Registry
+		expectedError  bool
+	}{
+		{
+			name: "successful retrieval",
+			mockRegistries: []*apimodels.PortainereeRegistry{
+				{ID: 1, Name: "dockerhub", URL: "docker.io"},
+				{ID: 2, Name: "ghcr", URL: "ghcr.io"},
+			},
+			expected: []models.Registry{
+				{ID: 1, Name: "dockerhub", URL: "docker.io"},
+				{ID: 2, Name: "ghcr", URL: "ghcr.io"},
+			},
+		},
+		{
+			name:           "no registries",
+			mockRegistries: []*apimodels.PortainereeRegistry{},
+			expected:       []models.Registry{},
+		},
+		{
+			name:          "list error",
+			mockError:     errors.New("access denied"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ListRegistries").Return(tt.mockRegistries, tt.mockError)
+
+			client := &PortainerClient{cli: mockAPI}
+
+			registries, err := client.GetRegistries()
+
+			if tt.expectedError {
+				assert.ErrorIs(t, err, tt.mockError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, registries)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
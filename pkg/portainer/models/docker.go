@@ -1,6 +1,20 @@
 package models
 
-import "io"
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-units"
+)
 
 // DockerProxyRequestOptions represents the options for a Docker API request to a specific Portainer environment.
 type DockerProxyRequestOptions struct {
@@ -17,3 +31,297 @@ type DockerProxyRequestOptions struct {
 	// Body is the request body to send (set it to nil for requests that don't have a body).
 	Body io.Reader
 }
+
+// dockerShortIDLength is the length of the IDs displayed by the Docker CLI
+const dockerShortIDLength = 12
+
+type DockerContainer struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Image     string            `json:"image"`
+	State     string            `json:"state"`
+	Status    string            `json:"status"`
+	CreatedAt string            `json:"created_at"`
+	Ports     []string          `json:"ports"`
+	Labels    map[string]string `json:"labels"`
+}
+
+func ConvertToDockerContainer(rawContainer container.Summary) DockerContainer {
+	ports := make([]string, 0, len(rawContainer.Ports))
+	for _, port := range rawContainer.Ports {
+		ports = append(ports, formatDockerPort(port))
+	}
+	sort.Strings(ports)
+
+	labels := rawContainer.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return DockerContainer{
+		ID:        ShortDockerID(rawContainer.ID),
+		Name:      DockerContainerName(rawContainer),
+		Image:     rawContainer.Image,
+		State:     rawContainer.State,
+		Status:    rawContainer.Status,
+		CreatedAt: time.Unix(rawContainer.Created, 0).UTC().Format(time.RFC3339),
+		Ports:     ports,
+		Labels:    labels,
+	}
+}
+
+type DockerContainerStats struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryUsage   string  `json:"memory_usage"`
+	MemoryLimit   string  `json:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent"`
+	NetworkIO     string  `json:"network_io"`
+	BlockIO       string  `json:"block_io"`
+	PIDs          uint64  `json:"pids"`
+
+	// The raw values are used to sort the containers and are not rendered
+	MemoryBytes  uint64 `json:"-"`
+	NetworkBytes uint64 `json:"-"`
+	BlockIOBytes uint64 `json:"-"`
+}
+
+// ConvertToDockerContainerStats computes the stats displayed by docker stats from a stats snapshot
+func ConvertToDockerContainerStats(rawStats container.StatsResponse) DockerContainerStats {
+	memoryUsage := dockerMemoryUsage(rawStats.MemoryStats)
+	memoryPercent := 0.0
+	if rawStats.MemoryStats.Limit > 0 {
+		memoryPercent = float64(memoryUsage) / float64(rawStats.MemoryStats.Limit) * 100
+	}
+
+	var rx, tx uint64
+	for _, network := range rawStats.Networks {
+		rx += network.RxBytes
+		tx += network.TxBytes
+	}
+
+	var read, write uint64
+	for _, entry := range rawStats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			read += entry.Value
+		case "write":
+			write += entry.Value
+		}
+	}
+
+	return DockerContainerStats{
+		ID:            ShortDockerID(rawStats.ID),
+		Name:          strings.TrimPrefix(rawStats.Name, "/"),
+		CPUPercent:    roundPercent(dockerCPUPercent(rawStats)),
+		MemoryUsage:   units.BytesSize(float64(memoryUsage)),
+		MemoryLimit:   units.BytesSize(float64(rawStats.MemoryStats.Limit)),
+		MemoryPercent: roundPercent(memoryPercent),
+		NetworkIO:     units.HumanSizeWithPrecision(float64(rx), 3) + " / " + units.HumanSizeWithPrecision(float64(tx), 3),
+		BlockIO:       units.HumanSizeWithPrecision(float64(read), 3) + " / " + units.HumanSizeWithPrecision(float64(write), 3),
+		PIDs:          rawStats.PidsStats.Current,
+		MemoryBytes:   memoryUsage,
+		NetworkBytes:  rx + tx,
+		BlockIOBytes:  read + write,
+	}
+}
+
+// dockerCPUPercent computes the CPU usage between the two samples of a snapshot, like docker stats.
+// 100% is one CPU fully used.
+func dockerCPUPercent(rawStats container.StatsResponse) float64 {
+	cpuDelta := float64(rawStats.CPUStats.CPUUsage.TotalUsage) - float64(rawStats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(rawStats.CPUStats.SystemUsage) - float64(rawStats.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	onlineCPUs := float64(rawStats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(rawStats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// dockerMemoryUsage returns the memory usage without the page cache, like docker stats.
+// The cache is reported as total_inactive_file with cgroup v1 and inactive_file with cgroup v2.
+func dockerMemoryUsage(memoryStats container.MemoryStats) uint64 {
+	cache, exists := memoryStats.Stats["total_inactive_file"]
+	if !exists {
+		cache = memoryStats.Stats["inactive_file"]
+	}
+
+	if cache > memoryStats.Usage {
+		return memoryStats.Usage
+	}
+	return memoryStats.Usage - cache
+}
+
+// roundPercent rounds a percentage to two decimals
+func roundPercent(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+type DockerImage struct {
+	ID        string   `json:"id"`
+	Tags      []string `json:"tags"`
+	Size      string   `json:"size"`
+	CreatedAt string   `json:"created_at"`
+	UsedBy    []string `json:"used_by"`
+}
+
+// ConvertToDockerImage converts an image, usedBy being the names of the containers created from the image.
+// Dangling images have no tags.
+func ConvertToDockerImage(rawImage image.Summary, usedBy []string) DockerImage {
+	return DockerImage{
+		ID:        ShortDockerID(rawImage.ID),
+		Tags:      DockerImageTags(rawImage),
+		Size:      units.HumanSizeWithPrecision(float64(rawImage.Size), 3),
+		CreatedAt: time.Unix(rawImage.Created, 0).UTC().Format(time.RFC3339),
+		UsedBy:    nonNilStrings(usedBy),
+	}
+}
+
+type DockerVolume struct {
+	Name      string   `json:"name"`
+	Driver    string   `json:"driver"`
+	Anonymous bool     `json:"anonymous"`
+	CreatedAt string   `json:"created_at"`
+	UsedBy    []string `json:"used_by"`
+}
+
+// ConvertToDockerVolume converts a volume, usedBy being the names of the containers mounting the volume
+func ConvertToDockerVolume(rawVolume volume.Volume, usedBy []string) DockerVolume {
+	return DockerVolume{
+		Name:      rawVolume.Name,
+		Driver:    rawVolume.Driver,
+		Anonymous: IsAnonymousDockerVolume(rawVolume),
+		CreatedAt: rawVolume.CreatedAt,
+		UsedBy:    nonNilStrings(usedBy),
+	}
+}
+
+type DockerNetwork struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Driver   string   `json:"driver"`
+	Scope    string   `json:"scope"`
+	Subnets  []string `json:"subnets"`
+	Internal bool     `json:"internal"`
+	UsedBy   []string `json:"used_by"`
+}
+
+// ConvertToDockerNetwork converts a network, usedBy being the names of the containers connected to the network
+func ConvertToDockerNetwork(rawNetwork network.Summary, usedBy []string) DockerNetwork {
+	subnets := make([]string, 0, len(rawNetwork.IPAM.Config))
+	for _, config := range rawNetwork.IPAM.Config {
+		if config.Subnet != "" {
+			subnets = append(subnets, config.Subnet)
+		}
+	}
+
+	return DockerNetwork{
+		ID:       ShortDockerID(rawNetwork.ID),
+		Name:     rawNetwork.Name,
+		Driver:   rawNetwork.Driver,
+		Scope:    rawNetwork.Scope,
+		Subnets:  subnets,
+		Internal: rawNetwork.Internal,
+		UsedBy:   nonNilStrings(usedBy),
+	}
+}
+
+type DockerContainerFile struct {
+	Path       string `json:"path"`
+	Type       string `json:"type"`
+	Size       string `json:"size"`
+	Mode       string `json:"mode"`
+	ModifiedAt string `json:"modified_at"`
+	LinkTarget string `json:"link_target,omitempty"`
+}
+
+// ConvertToDockerContainerFile converts an entry of a container archive, path being the absolute path of the
+// entry in the container
+func ConvertToDockerContainerFile(header *tar.Header, path string) DockerContainerFile {
+	size := ""
+	if header.Typeflag == tar.TypeReg {
+		size = units.HumanSizeWithPrecision(float64(header.Size), 3)
+	}
+
+	return DockerContainerFile{
+		Path:       path,
+		Type:       DockerContainerFileType(header),
+		Size:       size,
+		Mode:       header.FileInfo().Mode().String(),
+		ModifiedAt: header.ModTime.UTC().Format(time.RFC3339),
+		LinkTarget: header.Linkname,
+	}
+}
+
+// DockerContainerFileType returns the type of an entry of a container archive: file, directory, symlink,
+// hardlink or other
+func DockerContainerFileType(header *tar.Header) string {
+	switch header.Typeflag {
+	case tar.TypeReg:
+		return "file"
+	case tar.TypeDir:
+		return "directory"
+	case tar.TypeSymlink:
+		return "symlink"
+	case tar.TypeLink:
+		return "hardlink"
+	default:
+		return "other"
+	}
+}
+
+// DockerContainerName returns the name of a container without the leading slash
+func DockerContainerName(rawContainer container.Summary) string {
+	if len(rawContainer.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(rawContainer.Names[0], "/")
+}
+
+// DockerImageTags returns the tags of an image, without the <none>:<none> placeholder of the dangling images
+func DockerImageTags(rawImage image.Summary) []string {
+	tags := make([]string, 0, len(rawImage.RepoTags))
+	for _, tag := range rawImage.RepoTags {
+		if tag != "<none>:<none>" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// IsAnonymousDockerVolume checks if a volume was created without a name, e.g. for a VOLUME of an image
+func IsAnonymousDockerVolume(rawVolume volume.Volume) bool {
+	_, anonymous := rawVolume.Labels["com.docker.volume.anonymous"]
+	return anonymous
+}
+
+// nonNilStrings returns an empty slice instead of nil, so that the value is rendered as an empty list
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// ShortDockerID shortens a Docker ID to the 12 characters displayed by the Docker CLI
+func ShortDockerID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > dockerShortIDLength {
+		return id[:dockerShortIDLength]
+	}
+	return id
+}
+
+// formatDockerPort formats a port like the Docker CLI (e.g. 0.0.0.0:8080->80/tcp or 443/tcp)
+func formatDockerPort(port container.Port) string {
+	if port.PublicPort == 0 {
+		return fmt.Sprintf("%d/%s", port.PrivatePort, port.Type)
+	}
+	return fmt.Sprintf("%s:%d->%d/%s", port.IP, port.PublicPort, port.PrivatePort, port.Type)
+}
@@ -0,0 +1,347 @@
+package models
+
+import (
+	"archive/tar"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToDockerContainer(t *testing.T) {
+	tests := []struct {
+		name         string
+		rawContainer container.Summary
+		want         DockerContainer
+	}{
+		{
+			name: "running container with published ports",
+			rawContainer: container.Summary{
+				ID:      "4f66ad9a0b2e1c3d5e7f9a1b3c5d7e9f4f66ad9a0b2e1c3d5e7f9a1b3c5d7e9f",
+				Names:   []string{"/web"},
+				Image:   "nginx:1.27",
+				State:   "running",
+				Status:  "Up 2 hours",
+				Created: 1735689600,
+				Ports: []container.Port{
+					{PrivatePort: 443, Type: "tcp"},
+					{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				},
+				Labels: map[string]string{"com.docker.compose.project": "shop"},
+			},
+			want: DockerContainer{
+				ID:        "4f66ad9a0b2e",
+				Name:      "web",
+				Image:     "nginx:1.27",
+				State:     "running",
+				Status:    "Up 2 hours",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				Ports:     []string{"0.0.0.0:8080->80/tcp", "443/tcp"},
+				Labels:    map[string]string{"com.docker.compose.project": "shop"},
+			},
+		},
+		{
+			name: "exited container without names, ports or labels",
+			rawContainer: container.Summary{
+				ID:      "abc",
+				Image:   "busybox",
+				State:   "exited",
+				Status:  "Exited (0) 5 minutes ago",
+				Created: 1735689600,
+			},
+			want: DockerContainer{
+				ID:        "abc",
+				Image:     "busybox",
+				State:     "exited",
+				Status:    "Exited (0) 5 minutes ago",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				Ports:     []string{},
+				Labels:    map[string]string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerContainer(tt.rawContainer)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertToDockerImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawImage image.Summary
+		usedBy   []string
+		want     DockerImage
+	}{
+		{
+			name: "tagged image used by a container",
+			rawImage: image.Summary{
+				ID:       "sha256:9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e",
+				RepoTags: []string{"nginx:1.27", "nginx:latest"},
+				Size:     192_000_000,
+				Created:  1735689600,
+			},
+			usedBy: []string{"web"},
+			want: DockerImage{
+				ID:        "9b1c2d3e4f5a",
+				Tags:      []string{"nginx:1.27", "nginx:latest"},
+				Size:      "192MB",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				UsedBy:    []string{"web"},
+			},
+		},
+		{
+			name: "unused dangling image",
+			rawImage: image.Summary{
+				ID:       "sha256:1a2b3c4d5e6f7a8b9c0d",
+				RepoTags: []string{"<none>:<none>"},
+				Size:     5_500_000,
+				Created:  1735689600,
+			},
+			want: DockerImage{
+				ID:        "1a2b3c4d5e6f",
+				Tags:      []string{},
+				Size:      "5.5MB",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				UsedBy:    []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerImage(tt.rawImage, tt.usedBy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertToDockerVolume(t *testing.T) {
+	tests := []struct {
+		name      string
+		rawVolume volume.Volume
+		usedBy    []string
+		want      DockerVolume
+	}{
+		{
+			name: "named volume used by containers",
+			rawVolume: volume.Volume{
+				Name:      "shop_db",
+				Driver:    "local",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				Labels:    map[string]string{"com.docker.compose.project": "shop"},
+			},
+			usedBy: []string{"db", "backup"},
+			want: DockerVolume{
+				Name:      "shop_db",
+				Driver:    "local",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				UsedBy:    []string{"db", "backup"},
+			},
+		},
+		{
+			name: "unused anonymous volume",
+			rawVolume: volume.Volume{
+				Name:      "3f2a9c",
+				Driver:    "local",
+				CreatedAt: "2025-01-01T00:00:00Z",
+				Labels:    map[string]string{"com.docker.volume.anonymous": ""},
+			},
+			want: DockerVolume{
+				Name:      "3f2a9c",
+				Driver:    "local",
+				Anonymous: true,
+				CreatedAt: "2025-01-01T00:00:00Z",
+				UsedBy:    []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerVolume(tt.rawVolume, tt.usedBy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertToDockerNetwork(t *testing.T) {
+	rawNetwork := network.Summary{
+		ID:       "7d86d31b1478e7cca9ebed7e73aa0fdeec46c5ca29497431d3007d2d9e15ed99",
+		Name:     "shop_default",
+		Driver:   "bridge",
+		Scope:    "local",
+		Internal: true,
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16"}, {Gateway: "172.18.0.1"}},
+		},
+	}
+
+	got := ConvertToDockerNetwork(rawNetwork, []string{"web"})
+
+	assert.Equal(t, DockerNetwork{
+		ID:       "7d86d31b1478",
+		Name:     "shop_default",
+		Driver:   "bridge",
+		Scope:    "local",
+		Subnets:  []string{"172.18.0.0/16"},
+		Internal: true,
+		UsedBy:   []string{"web"},
+	}, got)
+}
+
+func TestConvertToDockerContainerFile(t *testing.T) {
+	modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header *tar.Header
+		path   string
+		want   DockerContainerFile
+	}{
+		{
+			name:   "regular file",
+			header: &tar.Header{Typeflag: tar.TypeReg, Name: "nginx/nginx.conf", Size: 2048, Mode: 0o644, ModTime: modTime},
+			path:   "/etc/nginx/nginx.conf",
+			want: DockerContainerFile{
+				Path:       "/etc/nginx/nginx.conf",
+				Type:       "file",
+				Size:       "2.05kB",
+				Mode:       "-rw-r--r--",
+				ModifiedAt: "2025-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:   "directory",
+			header: &tar.Header{Typeflag: tar.TypeDir, Name: "nginx/conf.d/", Mode: 0o755, ModTime: modTime},
+			path:   "/etc/nginx/conf.d",
+			want: DockerContainerFile{
+				Path:       "/etc/nginx/conf.d",
+				Type:       "directory",
+				Mode:       "drwxr-xr-x",
+				ModifiedAt: "2025-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:   "symbolic link",
+			header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "nginx/mime.types", Linkname: "/usr/share/mime.types", Mode: 0o777, ModTime: modTime},
+			path:   "/etc/nginx/mime.types",
+			want: DockerContainerFile{
+				Path:       "/etc/nginx/mime.types",
+				Type:       "symlink",
+				Mode:       "Lrwxrwxrwx",
+				ModifiedAt: "2025-01-01T00:00:00Z",
+				LinkTarget: "/usr/share/mime.types",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerContainerFile(tt.header, tt.path)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestShortDockerID(t *testing.T) {
+	assert.Equal(t, "4f66ad9a0b2e", ShortDockerID("4f66ad9a0b2e1c3d5e7f9a1b3c5d"))
+	assert.Equal(t, "9b1c2d3e4f5a", ShortDockerID("sha256:9b1c2d3e4f5a6b7c8d9e"))
+	assert.Equal(t, "web", ShortDockerID("web"))
+}
+
+func TestConvertToDockerContainerStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawStats container.StatsResponse
+		want     DockerContainerStats
+	}{
+		{
+			name: "cgroup v2 stats",
+			rawStats: container.StatsResponse{
+				ID:   "4f66ad9a0b2e1c3d5e7f",
+				Name: "/web",
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 400_000_000},
+					SystemUsage: 2_000_000_000,
+					OnlineCPUs:  2,
+				},
+				PreCPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 200_000_000},
+					SystemUsage: 1_000_000_000,
+				},
+				MemoryStats: container.MemoryStats{
+					Usage: 150 * 1024 * 1024,
+					Limit: 1024 * 1024 * 1024,
+					Stats: map[string]uint64{"inactive_file": 50 * 1024 * 1024},
+				},
+				Networks: map[string]container.NetworkStats{
+					"eth0": {RxBytes: 1000, TxBytes: 2000},
+					"eth1": {RxBytes: 500},
+				},
+				BlkioStats: container.BlkioStats{
+					IoServiceBytesRecursive: []container.BlkioStatEntry{
+						{Op: "read", Value: 4096},
+						{Op: "write", Value: 8192},
+					},
+				},
+				PidsStats: container.PidsStats{Current: 5},
+			},
+			want: DockerContainerStats{
+				ID:            "4f66ad9a0b2e",
+				Name:          "web",
+				CPUPercent:    40,
+				MemoryUsage:   "100MiB",
+				MemoryLimit:   "1GiB",
+				MemoryPercent: 9.77,
+				NetworkIO:     "1.5kB / 2kB",
+				BlockIO:       "4.1kB / 8.19kB",
+				PIDs:          5,
+				MemoryBytes:   100 * 1024 * 1024,
+				NetworkBytes:  3500,
+				BlockIOBytes:  12288,
+			},
+		},
+		{
+			name: "cgroup v1 stats without previous sample",
+			rawStats: container.StatsResponse{
+				ID:   "abc",
+				Name: "/db",
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 100, PercpuUsage: []uint64{50, 50}},
+					SystemUsage: 1000,
+				},
+				MemoryStats: container.MemoryStats{
+					Usage: 2048,
+					Limit: 4096,
+					Stats: map[string]uint64{"total_inactive_file": 1024},
+				},
+			},
+			want: DockerContainerStats{
+				ID:            "abc",
+				Name:          "db",
+				CPUPercent:    20,
+				MemoryUsage:   "1KiB",
+				MemoryLimit:   "4KiB",
+				MemoryPercent: 25,
+				NetworkIO:     "0B / 0B",
+				BlockIO:       "0B / 0B",
+				MemoryBytes:   1024,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerContainerStats(tt.rawStats)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -34,6 +34,93 @@ const (
 	EnvironmentTypeUnknown             = "unknown"
 )
 
+// EnvironmentFilter represents the filters that can be applied when listing environments.
+// Empty fields are ignored.
+type EnvironmentFilter struct {
+	// Search is a search query matched by Portainer against the environment name (and related fields such as tags or groups)
+	Search string
+	// Statuses restricts the results to the environments with one of these statuses (e.g. EnvironmentStatusActive)
+	Statuses []string
+	// Types restricts the results to the environments of one of these types (e.g. EnvironmentTypeDockerLocal)
+	Types []string
+	// TagIds restricts the results to the environments associated with all of these tags
+	TagIds []int
+	// GroupIds restricts the results to the environments that are part of one of these groups
+	GroupIds []int
+	// SortBy is the field used by Portainer to sort the environments (e.g. EnvironmentSortByName), by ID when empty
+	SortBy string
+	// Descending reverses the sort order
+	Descending bool
+	// Start is the offset of the first environment to return
+	Start int
+	// Limit is the maximum number of environments to return, all of them when 0
+	Limit int
+}
+
+// Environment sort field constants
+const (
+	EnvironmentSortByID          = "id"
+	EnvironmentSortByName        = "name"
+	EnvironmentSortByGroup       = "group"
+	EnvironmentSortByStatus      = "status"
+	EnvironmentSortByLastCheckIn = "lastCheckIn"
+)
+
+// ConvertEnvironmentSortToEndpointSort converts an environment sort field to the
+// sort key of the Portainer endpoint list, empty for the ID order. It returns false for unknown fields.
+func ConvertEnvironmentSortToEndpointSort(sortBy string) (string, bool) {
+	switch sortBy {
+	case "", EnvironmentSortByID:
+		return "", true
+	case EnvironmentSortByName:
+		return "Name", true
+	case EnvironmentSortByGroup:
+		return "Group", true
+	case EnvironmentSortByStatus:
+		return "Status", true
+	case EnvironmentSortByLastCheckIn:
+		return "LastCheckIn", true
+	default:
+		return "", false
+	}
+}
+
+// ConvertEnvironmentStatusToEndpointStatus converts an environment status to the
+// corresponding Portainer endpoint status. It returns false for unknown statuses.
+func ConvertEnvironmentStatusToEndpointStatus(status string) (int64, bool) {
+	switch status {
+	case EnvironmentStatusActive:
+		return 1, true
+	case EnvironmentStatusInactive:
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// ConvertEnvironmentTypeToEndpointType converts an environment type to the
+// corresponding Portainer endpoint type. It returns false for unknown types.
+func ConvertEnvironmentTypeToEndpointType(environmentType string) (int64, bool) {
+	switch environmentType {
+	case EnvironmentTypeDockerLocal:
+		return 1, true
+	case EnvironmentTypeDockerAgent:
+		return 2, true
+	case EnvironmentTypeAzureACI:
+		return 3, true
+	case EnvironmentTypeDockerEdgeAgent:
+		return 4, true
+	case EnvironmentTypeKubernetesLocal:
+		return 5, true
+	case EnvironmentTypeKubernetesAgent:
+		return 6, true
+	case EnvironmentTypeKubernetesEdgeAgent:
+		return 7, true
+	default:
+		return 0, false
+	}
+}
+
 func ConvertEndpointToEnvironment(rawEndpoint *apimodels.PortainereeEndpoint) Environment {
 	return Environment{
 		ID:           int(rawEndpoint.ID),
@@ -231,3 +231,64 @@ func TestConvertEnvironmentType(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertEnvironmentTypeToEndpointType(t *testing.T) {
+	// Every known type must round-trip through the endpoint type conversion
+	for typeValue := int64(1); typeValue <= 7; typeValue++ {
+		environmentType := convertEnvironmentType(&models.PortainereeEndpoint{Type: typeValue})
+		got, ok := ConvertEnvironmentTypeToEndpointType(environmentType)
+		if !ok || got != typeValue {
+			t.Errorf("ConvertEnvironmentTypeToEndpointType(%s) = %v, %v, want %v, true", environmentType, got, ok, typeValue)
+		}
+	}
+
+	if _, ok := ConvertEnvironmentTypeToEndpointType(EnvironmentTypeUnknown); ok {
+		t.Errorf("ConvertEnvironmentTypeToEndpointType(%s) should not be valid", EnvironmentTypeUnknown)
+	}
+}
+
+func TestConvertEnvironmentStatusToEndpointStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   int64
+		wantOk bool
+	}{
+		{status: EnvironmentStatusActive, want: 1, wantOk: true},
+		{status: EnvironmentStatusInactive, want: 2, wantOk: true},
+		{status: EnvironmentStatusUnknown, want: 0, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			got, ok := ConvertEnvironmentStatusToEndpointStatus(tt.status)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ConvertEnvironmentStatusToEndpointStatus() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestConvertEnvironmentSortToEndpointSort(t *testing.T) {
+	tests := []struct {
+		sortBy string
+		want   string
+		wantOk bool
+	}{
+		{sortBy: "", want: "", wantOk: true},
+		{sortBy: EnvironmentSortByID, want: "", wantOk: true},
+		{sortBy: EnvironmentSortByName, want: "Name", wantOk: true},
+		{sortBy: EnvironmentSortByGroup, want: "Group", wantOk: true},
+		{sortBy: EnvironmentSortByStatus, want: "Status", wantOk: true},
+		{sortBy: EnvironmentSortByLastCheckIn, want: "LastCheckIn", wantOk: true},
+		{sortBy: "type", want: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			got, ok := ConvertEnvironmentSortToEndpointSort(tt.sortBy)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ConvertEnvironmentSortToEndpointSort() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strconv"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+)
+
+// HelmRelease is a revision of a Helm release deployed in a Kubernetes environment
+type HelmRelease struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Revision    int    `json:"revision"`
+	Chart       string `json:"chart"`
+	AppVersion  string `json:"app_version"`
+	Status      string `json:"status"`
+	Updated     string `json:"updated"`
+	Description string `json:"description"`
+}
+
+// HelmReleaseValues are the values of a Helm release revision, as YAML documents
+type HelmReleaseValues struct {
+	// UserSupplied are the values supplied when the release was installed or upgraded
+	UserSupplied string
+	// Computed are the user supplied values merged with the default values of the chart
+	Computed string
+}
+
+// HelmInstallOptions are the options of the installation or the upgrade of a Helm release
+type HelmInstallOptions struct {
+	// Name is the name of the release
+	Name string
+	// Namespace is the namespace of the release
+	Namespace string
+	// Chart is the name of the chart in the repository
+	Chart string
+	// Repo is the URL of the Helm repository
+	Repo string
+	// Version is the version of the chart, the latest version is used when empty
+	Version string
+	// Values are the values of the release, as a YAML document
+	Values string
+	// Atomic rolls back the changes when the installation or the upgrade fails
+	Atomic bool
+}
+
+func ConvertReleaseElementToHelmRelease(rawRelease *apimodels.ReleaseReleaseElement) HelmRelease {
+	revision, _ := strconv.Atoi(rawRelease.Revision)
+
+	return HelmRelease{
+		Name:       rawRelease.Name,
+		Namespace:  rawRelease.Namespace,
+		Revision:   revision,
+		Chart:      rawRelease.Chart,
+		AppVersion: rawRelease.AppVersion,
+		Status:     rawRelease.Status,
+		Updated:    rawRelease.Updated,
+	}
+}
+
+func ConvertReleaseToHelmRelease(rawRelease *apimodels.ReleaseRelease) HelmRelease {
+	release := HelmRelease{
+		Name:       rawRelease.Name,
+		Namespace:  rawRelease.Namespace,
+		Revision:   int(rawRelease.Version),
+		AppVersion: rawRelease.AppVersion,
+	}
+
+	if rawRelease.Chart != nil && rawRelease.Chart.Metadata != nil {
+		release.Chart = rawRelease.Chart.Metadata.Name + "-" + rawRelease.Chart.Metadata.Version
+		if release.AppVersion == "" {
+			release.AppVersion = rawRelease.Chart.Metadata.AppVersion
+		}
+	}
+
+	if rawRelease.Info != nil {
+		release.Status = rawRelease.Info.Status
+		release.Updated = rawRelease.Info.LastDeployed
+		release.Description = rawRelease.Info.Description
+	}
+
+	return release
+}
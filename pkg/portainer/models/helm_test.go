@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertReleaseElementToHelmRelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawRelease *apimodels.ReleaseReleaseElement
+		want       HelmRelease
+	}{
+		{
+			name: "deployed release",
+			rawRelease: &apimodels.ReleaseReleaseElement{
+				Name:       "shop-db",
+				Namespace:  "shop",
+				Revision:   "3",
+				Chart:      "postgresql-15.5.0",
+				AppVersion: "16.3.0",
+				Status:     "deployed",
+				Updated:    "2025-01-01 12:00:00.000000000 +0000 UTC",
+			},
+			want: HelmRelease{
+				Name:       "shop-db",
+				Namespace:  "shop",
+				Revision:   3,
+				Chart:      "postgresql-15.5.0",
+				AppVersion: "16.3.0",
+				Status:     "deployed",
+				Updated:    "2025-01-01 12:00:00.000000000 +0000 UTC",
+			},
+		},
+		{
+			name: "invalid revision",
+			rawRelease: &apimodels.ReleaseReleaseElement{
+				Name:     "broken",
+				Revision: "unknown",
+				Status:   "failed",
+			},
+			want: HelmRelease{
+				Name:   "broken",
+				Status: "failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ConvertReleaseElementToHelmRelease(tt.rawRelease))
+		})
+	}
+}
+
+func TestConvertReleaseToHelmRelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawRelease *apimodels.ReleaseRelease
+		want       HelmRelease
+	}{
+		{
+			name: "release with chart and info",
+			rawRelease: &apimodels.ReleaseRelease{
+				Name:      "shop-db",
+				Namespace: "shop",
+				Version:   2,
+				Chart: &apimodels.ReleaseChart{
+					Metadata: &apimodels.ReleaseMetadata{Name: "postgresql", Version: "15.5.0", AppVersion: "16.3.0"},
+				},
+				Info: &apimodels.ReleaseInfo{
+					Status:       "superseded",
+					LastDeployed: "2025-01-01T12:00:00Z",
+					Description:  "Upgrade complete",
+				},
+			},
+			want: HelmRelease{
+				Name:        "shop-db",
+				Namespace:   "shop",
+				Revision:    2,
+				Chart:       "postgresql-15.5.0",
+				AppVersion:  "16.3.0",
+				Status:      "superseded",
+				Updated:     "2025-01-01T12:00:00Z",
+				Description: "Upgrade complete",
+			},
+		},
+		{
+			name: "release without chart and info",
+			rawRelease: &apimodels.ReleaseRelease{
+				Name:       "shop-cache",
+				Namespace:  "shop",
+				Version:    1,
+				AppVersion: "7.2.5",
+			},
+			want: HelmRelease{
+				Name:       "shop-cache",
+				Namespace:  "shop",
+				Revision:   1,
+				AppVersion: "7.2.5",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ConvertReleaseToHelmRelease(tt.rawRelease))
+		})
+	}
+}
@@ -17,3 +17,17 @@ type KubernetesProxyRequestOptions struct {
 	// Body is the request body to send (set it to nil for requests that don't have a body).
 	Body io.Reader
 }
+
+// KubernetesEvent is a Kubernetes event, the occurrences of the events with the same type, reason and object
+// being merged
+type KubernetesEvent struct {
+	Namespace string `json:"namespace"`
+	Object    string `json:"object"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count"`
+	FirstSeen string `json:"first_seen"`
+	LastSeen  string `json:"last_seen"`
+	Source    string `json:"source"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+)
+
+// DockerHubRegistryHost is the host of the images that do not name their registry
+const DockerHubRegistryHost = "docker.io"
+
+type Registry struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func ConvertToRegistry(rawRegistry *apimodels.PortainereeRegistry) Registry {
+	return Registry{
+		ID:   int(rawRegistry.ID),
+		Name: rawRegistry.Name,
+		URL:  rawRegistry.URL,
+	}
+}
+
+// Host returns the host of the registry URL, without the scheme and the path.
+// The aliases of Docker Hub are all reported as docker.io.
+func (r Registry) Host() string {
+	host := r.URL
+	if _, rest, found := strings.Cut(host, "://"); found {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return DockerHubRegistryHost
+	}
+	return host
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToRegistry(t *testing.T) {
+	registry := ConvertToRegistry(&apimodels.PortainereeRegistry{
+		ID:       3,
+		Name:     "ghcr",
+		URL:      "ghcr.io",
+		Username: "bot",
+		Password: "secret",
+	})
+
+	assert.Equal(t, Registry{ID: 3, Name: "ghcr", URL: "ghcr.io"}, registry)
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "ghcr.io", expected: "ghcr.io"},
+		{url: "https://registry.example.com:5000/", expected: "registry.example.com:5000"},
+		{url: "registry.gitlab.com/group/project", expected: "registry.gitlab.com"},
+		{url: "docker.io", expected: "docker.io"},
+		{url: "https://index.docker.io/v1/", expected: "docker.io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Registry{URL: tt.url}.Host())
+		})
+	}
+}
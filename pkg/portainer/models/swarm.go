@@ -0,0 +1,159 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+type DockerService struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Mode         string   `json:"mode"`
+	Replicas     string   `json:"replicas"`
+	Image        string   `json:"image"`
+	Ports        []string `json:"ports"`
+	UpdateStatus string   `json:"update_status,omitempty"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+type DockerTask struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	Node         string `json:"node"`
+	DesiredState string `json:"desired_state"`
+	State        string `json:"state"`
+	Error        string `json:"error,omitempty"`
+	ExitCode     *int   `json:"exit_code,omitempty"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type DockerNode struct {
+	ID            string `json:"id"`
+	Hostname      string `json:"hostname"`
+	Role          string `json:"role"`
+	Availability  string `json:"availability"`
+	State         string `json:"state"`
+	ManagerStatus string `json:"manager_status,omitempty"`
+	Address       string `json:"address"`
+	EngineVersion string `json:"engine_version"`
+}
+
+func ConvertToDockerService(rawService swarm.Service) DockerService {
+	ports := make([]string, 0, len(rawService.Endpoint.Ports))
+	for _, port := range rawService.Endpoint.Ports {
+		ports = append(ports, fmt.Sprintf("*:%d->%d/%s", port.PublishedPort, port.TargetPort, port.Protocol))
+	}
+
+	updateStatus := ""
+	if rawService.UpdateStatus != nil && rawService.UpdateStatus.State != "" {
+		updateStatus = string(rawService.UpdateStatus.State)
+		if rawService.UpdateStatus.Message != "" {
+			updateStatus += ": " + rawService.UpdateStatus.Message
+		}
+	}
+
+	return DockerService{
+		ID:           ShortDockerID(rawService.ID),
+		Name:         rawService.Spec.Name,
+		Mode:         dockerServiceMode(rawService.Spec.Mode),
+		Replicas:     dockerServiceReplicas(rawService),
+		Image:        trimImageDigest(rawService.Spec.TaskTemplate.ContainerSpec),
+		Ports:        ports,
+		UpdateStatus: updateStatus,
+		UpdatedAt:    rawService.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// ConvertToDockerTask converts a Swarm task. The node hostnames are used instead of the node IDs when known.
+func ConvertToDockerTask(rawTask swarm.Task, serviceName string, nodeHostnames map[string]string) DockerTask {
+	name := serviceName
+	if rawTask.Slot > 0 {
+		name = fmt.Sprintf("%s.%d", serviceName, rawTask.Slot)
+	}
+
+	node := rawTask.NodeID
+	if hostname, exists := nodeHostnames[rawTask.NodeID]; exists {
+		node = hostname
+	}
+
+	var exitCode *int
+	if rawTask.Status.ContainerStatus != nil && rawTask.Status.State != swarm.TaskStateRunning && rawTask.Status.ContainerStatus.ContainerID != "" {
+		code := rawTask.Status.ContainerStatus.ExitCode
+		exitCode = &code
+	}
+
+	return DockerTask{
+		ID:           ShortDockerID(rawTask.ID),
+		Name:         name,
+		Image:        trimImageDigest(rawTask.Spec.ContainerSpec),
+		Node:         node,
+		DesiredState: string(rawTask.DesiredState),
+		State:        string(rawTask.Status.State),
+		Error:        rawTask.Status.Err,
+		ExitCode:     exitCode,
+		UpdatedAt:    rawTask.Status.Timestamp.UTC().Format(time.RFC3339),
+	}
+}
+
+func ConvertToDockerNode(rawNode swarm.Node) DockerNode {
+	managerStatus := ""
+	if rawNode.ManagerStatus != nil {
+		managerStatus = string(rawNode.ManagerStatus.Reachability)
+		if rawNode.ManagerStatus.Leader {
+			managerStatus = "leader"
+		}
+	}
+
+	return DockerNode{
+		ID:            ShortDockerID(rawNode.ID),
+		Hostname:      rawNode.Description.Hostname,
+		Role:          string(rawNode.Spec.Role),
+		Availability:  string(rawNode.Spec.Availability),
+		State:         string(rawNode.Status.State),
+		ManagerStatus: managerStatus,
+		Address:       rawNode.Status.Addr,
+		EngineVersion: rawNode.Description.Engine.EngineVersion,
+	}
+}
+
+// dockerServiceMode returns the mode of a service (replicated, global, replicated-job or global-job)
+func dockerServiceMode(mode swarm.ServiceMode) string {
+	switch {
+	case mode.Global != nil:
+		return "global"
+	case mode.ReplicatedJob != nil:
+		return "replicated-job"
+	case mode.GlobalJob != nil:
+		return "global-job"
+	default:
+		return "replicated"
+	}
+}
+
+// dockerServiceReplicas returns the running and desired tasks of a service (e.g. 2/3), as displayed by
+// docker service ls. The service status is only returned by the Docker API when requested with status=true.
+func dockerServiceReplicas(rawService swarm.Service) string {
+	if rawService.ServiceStatus != nil {
+		return fmt.Sprintf("%d/%d", rawService.ServiceStatus.RunningTasks, rawService.ServiceStatus.DesiredTasks)
+	}
+
+	if replicated := rawService.Spec.Mode.Replicated; replicated != nil && replicated.Replicas != nil {
+		return fmt.Sprintf("?/%d", *replicated.Replicas)
+	}
+
+	return ""
+}
+
+// trimImageDigest removes the digest pinned by Swarm from the image of a service or task
+func trimImageDigest(containerSpec *swarm.ContainerSpec) string {
+	if containerSpec == nil {
+		return ""
+	}
+
+	image, _, _ := strings.Cut(containerSpec.Image, "@")
+	return image
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToDockerService(t *testing.T) {
+	replicas := uint64(3)
+	updatedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		rawService swarm.Service
+		want       DockerService
+	}{
+		{
+			name: "replicated service with status",
+			rawService: swarm.Service{
+				ID:   "qz7u3e0w1x2y3z4a5b6c",
+				Meta: swarm.Meta{UpdatedAt: updatedAt},
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "shop_web"},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.27@sha256:0a399eb1"},
+					},
+					Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+				},
+				Endpoint: swarm.Endpoint{
+					Ports: []swarm.PortConfig{{Protocol: "tcp", TargetPort: 80, PublishedPort: 8080}},
+				},
+				UpdateStatus:  &swarm.UpdateStatus{State: swarm.UpdateStatePaused, Message: "update paused due to failure"},
+				ServiceStatus: &swarm.ServiceStatus{RunningTasks: 2, DesiredTasks: 3},
+			},
+			want: DockerService{
+				ID:           "qz7u3e0w1x2y",
+				Name:         "shop_web",
+				Mode:         "replicated",
+				Replicas:     "2/3",
+				Image:        "nginx:1.27",
+				Ports:        []string{"*:8080->80/tcp"},
+				UpdateStatus: "paused: update paused due to failure",
+				UpdatedAt:    "2025-01-01T12:00:00Z",
+			},
+		},
+		{
+			name: "global service without status",
+			rawService: swarm.Service{
+				ID:   "abc",
+				Meta: swarm.Meta{UpdatedAt: updatedAt},
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "agent"},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{Image: "portainer/agent"},
+					},
+					Mode: swarm.ServiceMode{Global: &swarm.GlobalService{}},
+				},
+			},
+			want: DockerService{
+				ID:        "abc",
+				Name:      "agent",
+				Mode:      "global",
+				Image:     "portainer/agent",
+				Ports:     []string{},
+				UpdatedAt: "2025-01-01T12:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerService(tt.rawService)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertToDockerTask(t *testing.T) {
+	timestamp := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	exitCode := 137
+
+	tests := []struct {
+		name    string
+		rawTask swarm.Task
+		want    DockerTask
+	}{
+		{
+			name: "failed task on a known node",
+			rawTask: swarm.Task{
+				ID:           "t1a2b3c4d5e6f7g8",
+				Slot:         2,
+				NodeID:       "node-1-id",
+				DesiredState: swarm.TaskStateShutdown,
+				Spec:         swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.27@sha256:0a39"}},
+				Status: swarm.TaskStatus{
+					Timestamp:       timestamp,
+					State:           swarm.TaskStateFailed,
+					Err:             "task: non-zero exit (137)",
+					ContainerStatus: &swarm.ContainerStatus{ContainerID: "c1", ExitCode: 137},
+				},
+			},
+			want: DockerTask{
+				ID:           "t1a2b3c4d5e6",
+				Name:         "web.2",
+				Image:        "nginx:1.27",
+				Node:         "worker-1",
+				DesiredState: "shutdown",
+				State:        "failed",
+				Error:        "task: non-zero exit (137)",
+				ExitCode:     &exitCode,
+				UpdatedAt:    "2025-01-01T12:00:00Z",
+			},
+		},
+		{
+			name: "running task on an unknown node",
+			rawTask: swarm.Task{
+				ID:           "t2",
+				NodeID:       "node-2-id",
+				DesiredState: swarm.TaskStateRunning,
+				Status: swarm.TaskStatus{
+					Timestamp:       timestamp,
+					State:           swarm.TaskStateRunning,
+					ContainerStatus: &swarm.ContainerStatus{ContainerID: "c2"},
+				},
+			},
+			want: DockerTask{
+				ID:           "t2",
+				Name:         "web",
+				Node:         "node-2-id",
+				DesiredState: "running",
+				State:        "running",
+				UpdatedAt:    "2025-01-01T12:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerTask(tt.rawTask, "web", map[string]string{"node-1-id": "worker-1"})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertToDockerNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawNode swarm.Node
+		want    DockerNode
+	}{
+		{
+			name: "leader manager",
+			rawNode: swarm.Node{
+				ID:            "n1a2b3c4d5e6f7g8",
+				Spec:          swarm.NodeSpec{Role: swarm.NodeRoleManager, Availability: swarm.NodeAvailabilityActive},
+				Description:   swarm.NodeDescription{Hostname: "manager-1", Engine: swarm.EngineDescription{EngineVersion: "28.0.1"}},
+				Status:        swarm.NodeStatus{State: swarm.NodeStateReady, Addr: "10.0.0.1"},
+				ManagerStatus: &swarm.ManagerStatus{Leader: true, Reachability: swarm.ReachabilityReachable},
+			},
+			want: DockerNode{
+				ID:            "n1a2b3c4d5e6",
+				Hostname:      "manager-1",
+				Role:          "manager",
+				Availability:  "active",
+				State:         "ready",
+				ManagerStatus: "leader",
+				Address:       "10.0.0.1",
+				EngineVersion: "28.0.1",
+			},
+		},
+		{
+			name: "drained worker",
+			rawNode: swarm.Node{
+				ID:          "n2",
+				Spec:        swarm.NodeSpec{Role: swarm.NodeRoleWorker, Availability: swarm.NodeAvailabilityDrain},
+				Description: swarm.NodeDescription{Hostname: "worker-1"},
+				Status:      swarm.NodeStatus{State: swarm.NodeStateDown, Addr: "10.0.0.2"},
+			},
+			want: DockerNode{
+				ID:           "n2",
+				Hostname:     "worker-1",
+				Role:         "worker",
+				Availability: "drain",
+				State:        "down",
+				Address:      "10.0.0.2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToDockerNode(tt.rawNode)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
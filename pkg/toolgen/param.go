@@ -99,6 +99,33 @@ func (p *ParameterParser) GetArrayOfIntegers(name string, required bool) ([]int,
 	return parseArrayOfIntegers(arrayValue)
 }
 
+// GetArrayOfStrings extracts an array of strings parameter from the request
+func (p *ParameterParser) GetArrayOfStrings(name string, required bool) ([]string, error) {
+	value, ok := p.args[name]
+	if !ok || value == nil {
+		if required {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+		return []string{}, nil
+	}
+
+	arrayValue, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array", name)
+	}
+
+	result := make([]string, 0, len(arrayValue))
+	for _, item := range arrayValue {
+		strValue, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("failed to parse '%v' as string", item)
+		}
+		result = append(result, strValue)
+	}
+
+	return result, nil
+}
+
 // GetArrayOfObjects extracts an array of objects parameter from the request
 func (p *ParameterParser) GetArrayOfObjects(name string, required bool) ([]any, error) {
 	value, ok := p.args[name]
@@ -579,3 +579,64 @@ func TestGetArrayOfIntegers(t *testing.T) {
 		})
 	}
 }
+
+func TestGetArrayOfStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]any
+		param    string
+		required bool
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "valid array of strings",
+			args:     map[string]any{"names": []any{"a", "b"}},
+			param:    "names",
+			required: true,
+			want:     []string{"a", "b"},
+		},
+		{
+			name:     "missing required param",
+			args:     map[string]any{},
+			param:    "names",
+			required: true,
+			wantErr:  true,
+		},
+		{
+			name:     "missing optional param",
+			args:     map[string]any{},
+			param:    "names",
+			required: false,
+			want:     []string{},
+		},
+		{
+			name:     "invalid array with number",
+			args:     map[string]any{"names": []any{"a", float64(1)}},
+			param:    "names",
+			required: true,
+			wantErr:  true,
+		},
+		{
+			name:     "wrong type (string instead of array)",
+			args:     map[string]any{"names": "a"},
+			param:    "names",
+			required: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(tt.args)
+			got, err := p.GetArrayOfStrings(tt.param, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetArrayOfStrings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetArrayOfStrings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package toolgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// PromptsConfig represents the entire prompts YAML configuration
+type PromptsConfig struct {
+	Version string             `yaml:"version"`
+	Prompts []PromptDefinition `yaml:"prompts"`
+}
+
+// PromptDefinition represents a single prompt in the YAML config
+type PromptDefinition struct {
+	Name        string                     `yaml:"name"`
+	Description string                     `yaml:"description"`
+	Arguments   []PromptArgumentDefinition `yaml:"arguments"`
+	Messages    []PromptMessageDefinition  `yaml:"messages"`
+}
+
+// PromptArgumentDefinition represents a prompt argument in the YAML config
+type PromptArgumentDefinition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+}
+
+// PromptMessageDefinition represents a prompt message template in the YAML config.
+// The content is a Go text/template that receives the prompt arguments as a map.
+type PromptMessageDefinition struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content"`
+}
+
+// Prompt is a loaded prompt, ready to be registered on an MCP server
+type Prompt struct {
+	Prompt    mcp.Prompt
+	roles     []mcp.Role
+	templates []*template.Template
+}
+
+// LoadPromptsFromYAML loads prompt definitions from a YAML file
+// It returns the prompts indexed by name
+func LoadPromptsFromYAML(filePath string, minimumVersion string) (map[string]Prompt, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config PromptsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Version == "" {
+		return nil, fmt.Errorf("missing version in prompts.yaml")
+	}
+
+	if !semver.IsValid(config.Version) {
+		return nil, fmt.Errorf("invalid version in prompts.yaml: %s", config.Version)
+	}
+
+	if semver.Compare(config.Version, minimumVersion) < 0 {
+		return nil, fmt.Errorf("prompts.yaml version %s is below the minimum required version %s", config.Version, minimumVersion)
+	}
+
+	return convertPromptDefinitions(config.Prompts), nil
+}
+
+// convertPromptDefinitions converts YAML prompt definitions to Prompt objects
+func convertPromptDefinitions(defs []PromptDefinition) map[string]Prompt {
+	prompts := make(map[string]Prompt, len(defs))
+
+	for _, def := range defs {
+		prompt, err := convertPromptDefinition(def)
+		if err != nil {
+			log.Warn().Err(err).Str("prompt", def.Name).Msg("skipping invalid prompt definition")
+			continue
+		}
+
+		prompts[def.Name] = prompt
+	}
+
+	return prompts
+}
+
+// convertPromptDefinition converts a single YAML prompt definition to a Prompt
+func convertPromptDefinition(def PromptDefinition) (Prompt, error) {
+	if def.Name == "" {
+		return Prompt{}, fmt.Errorf("prompt name is required")
+	}
+
+	if def.Description == "" {
+		return Prompt{}, fmt.Errorf("prompt description is required for prompt '%s'", def.Name)
+	}
+
+	if len(def.Messages) == 0 {
+		return Prompt{}, fmt.Errorf("at least one message is required for prompt '%s'", def.Name)
+	}
+
+	options := []mcp.PromptOption{
+		mcp.WithPromptDescription(def.Description),
+	}
+
+	for _, arg := range def.Arguments {
+		if arg.Name == "" {
+			return Prompt{}, fmt.Errorf("argument name is required for prompt '%s'", def.Name)
+		}
+		options = append(options, convertPromptArgument(arg))
+	}
+
+	prompt := Prompt{
+		Prompt: mcp.NewPrompt(def.Name, options...),
+	}
+
+	for i, msg := range def.Messages {
+		role := mcp.Role(msg.Role)
+		if role != mcp.RoleUser && role != mcp.RoleAssistant {
+			return Prompt{}, fmt.Errorf("invalid role '%s' for message %d of prompt '%s'", msg.Role, i, def.Name)
+		}
+
+		tmpl, err := template.New(fmt.Sprintf("%s-%d", def.Name, i)).Option("missingkey=zero").Parse(msg.Content)
+		if err != nil {
+			return Prompt{}, fmt.Errorf("invalid template for message %d of prompt '%s': %w", i, def.Name, err)
+		}
+
+		prompt.roles = append(prompt.roles, role)
+		prompt.templates = append(prompt.templates, tmpl)
+	}
+
+	return prompt, nil
+}
+
+// convertPromptArgument converts a YAML prompt argument definition to an mcp option
+func convertPromptArgument(arg PromptArgumentDefinition) mcp.PromptOption {
+	var options []mcp.ArgumentOption
+
+	options = append(options, mcp.ArgumentDescription(arg.Description))
+
+	if arg.Required {
+		options = append(options, mcp.RequiredArgument())
+	}
+
+	return mcp.WithArgument(arg.Name, options...)
+}
+
+// Render renders the prompt messages using the provided arguments.
+// It returns an error if a required argument is missing.
+func (p Prompt) Render(args map[string]string) ([]mcp.PromptMessage, error) {
+	data := make(map[string]string, len(p.Prompt.Arguments))
+	for _, arg := range p.Prompt.Arguments {
+		value := args[arg.Name]
+		if value == "" && arg.Required {
+			return nil, fmt.Errorf("%s is required", arg.Name)
+		}
+		data[arg.Name] = value
+	}
+
+	messages := make([]mcp.PromptMessage, 0, len(p.templates))
+	for i, tmpl := range p.templates {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render message %d: %w", i, err)
+		}
+		messages = append(messages, mcp.NewPromptMessage(p.roles[i], mcp.NewTextContent(buf.String())))
+	}
+
+	return messages, nil
+}
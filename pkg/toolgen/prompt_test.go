@@ -0,0 +1,233 @@
+package toolgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPromptsFromYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	validPath := writeFile("valid.yaml", `version: "v1.0.0"
+prompts:
+  - name: testPrompt
+    description: A test prompt
+    arguments:
+      - name: environmentId
+        description: The environment ID
+        required: true
+    messages:
+      - role: user
+        content: "Inspect environment {{.environmentId}}"
+  - name: invalidPrompt
+    description: A prompt without messages
+`)
+
+	olderPath := writeFile("older.yaml", `version: "v0.9.0"
+prompts: []
+`)
+
+	missingVersionPath := writeFile("missing.yaml", `prompts: []
+`)
+
+	invalidVersionPath := writeFile("invalid.yaml", `version: "latest"
+prompts: []
+`)
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedNames []string
+		errorContains string
+	}{
+		{
+			name:          "valid file skips invalid prompts",
+			path:          validPath,
+			expectedNames: []string{"testPrompt"},
+		},
+		{
+			name:          "older version",
+			path:          olderPath,
+			errorContains: "below the minimum required version",
+		},
+		{
+			name:          "missing version",
+			path:          missingVersionPath,
+			errorContains: "missing version in prompts.yaml",
+		},
+		{
+			name:          "invalid version",
+			path:          invalidVersionPath,
+			errorContains: "invalid version in prompts.yaml",
+		},
+		{
+			name:          "nonexistent file",
+			path:          filepath.Join(tmpDir, "nonexistent.yaml"),
+			errorContains: "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts, err := LoadPromptsFromYAML(tt.path, "v1.0.0")
+
+			if tt.errorContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, prompts, len(tt.expectedNames))
+			for _, name := range tt.expectedNames {
+				assert.Contains(t, prompts, name)
+			}
+		})
+	}
+}
+
+func TestConvertPromptDefinition(t *testing.T) {
+	tests := []struct {
+		name          string
+		def           PromptDefinition
+		errorContains string
+	}{
+		{
+			name: "valid definition",
+			def: PromptDefinition{
+				Name:        "valid",
+				Description: "A valid prompt",
+				Arguments:   []PromptArgumentDefinition{{Name: "id", Description: "An ID", Required: true}},
+				Messages:    []PromptMessageDefinition{{Role: "user", Content: "ID is {{.id}}"}},
+			},
+		},
+		{
+			name:          "missing name",
+			def:           PromptDefinition{Description: "No name"},
+			errorContains: "prompt name is required",
+		},
+		{
+			name:          "missing description",
+			def:           PromptDefinition{Name: "noDescription"},
+			errorContains: "prompt description is required",
+		},
+		{
+			name:          "missing messages",
+			def:           PromptDefinition{Name: "noMessages", Description: "No messages"},
+			errorContains: "at least one message is required",
+		},
+		{
+			name: "missing argument name",
+			def: PromptDefinition{
+				Name:        "noArgName",
+				Description: "Argument without a name",
+				Arguments:   []PromptArgumentDefinition{{Description: "Nameless"}},
+				Messages:    []PromptMessageDefinition{{Role: "user", Content: "Hello"}},
+			},
+			errorContains: "argument name is required",
+		},
+		{
+			name: "invalid role",
+			def: PromptDefinition{
+				Name:        "invalidRole",
+				Description: "Invalid role",
+				Messages:    []PromptMessageDefinition{{Role: "system", Content: "Hello"}},
+			},
+			errorContains: "invalid role 'system'",
+		},
+		{
+			name: "invalid template",
+			def: PromptDefinition{
+				Name:        "invalidTemplate",
+				Description: "Invalid template",
+				Messages:    []PromptMessageDefinition{{Role: "user", Content: "{{.id"}},
+			},
+			errorContains: "invalid template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt, err := convertPromptDefinition(tt.def)
+
+			if tt.errorContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.def.Name, prompt.Prompt.Name)
+			assert.Equal(t, tt.def.Description, prompt.Prompt.Description)
+			assert.Len(t, prompt.Prompt.Arguments, len(tt.def.Arguments))
+		})
+	}
+}
+
+func TestPromptRender(t *testing.T) {
+	prompt, err := convertPromptDefinition(PromptDefinition{
+		Name:        "render",
+		Description: "Render test",
+		Arguments: []PromptArgumentDefinition{
+			{Name: "environmentId", Required: true},
+			{Name: "stackId"},
+		},
+		Messages: []PromptMessageDefinition{
+			{Role: "user", Content: "Environment {{.environmentId}}{{if .stackId}}, stack {{.stackId}}{{end}}"},
+			{Role: "assistant", Content: "Understood"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		args          map[string]string
+		expectedText  string
+		errorContains string
+	}{
+		{
+			name:         "required argument only",
+			args:         map[string]string{"environmentId": "1"},
+			expectedText: "Environment 1",
+		},
+		{
+			name:         "optional argument provided",
+			args:         map[string]string{"environmentId": "1", "stackId": "2"},
+			expectedText: "Environment 1, stack 2",
+		},
+		{
+			name:          "missing required argument",
+			args:          map[string]string{"stackId": "2"},
+			errorContains: "environmentId is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := prompt.Render(tt.args)
+
+			if tt.errorContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, messages, 2)
+			assert.Equal(t, mcp.RoleUser, messages[0].Role)
+			assert.Equal(t, tt.expectedText, messages[0].Content.(mcp.TextContent).Text)
+			assert.Equal(t, mcp.RoleAssistant, messages[1].Role)
+		})
+	}
+}
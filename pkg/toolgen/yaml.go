@@ -2,10 +2,10 @@ package toolgen
 
 import (
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v3"
 )
@@ -78,7 +78,7 @@ func convertToolDefinitions(defs []ToolDefinition) map[string]mcp.Tool {
 	for _, def := range defs {
 		tool, err := convertToolDefinition(def)
 		if err != nil {
-			log.Printf("skipping invalid tool definition %s: %s", def.Name, err)
+			log.Warn().Err(err).Str("tool", def.Name).Msg("skipping invalid tool definition")
 			continue
 		}
 
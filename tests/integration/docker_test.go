@@ -3,6 +3,7 @@ package integration
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	mcpmodels "github.com/mark3labs/mcp-go/mcp"
@@ -65,9 +66,10 @@ func TestDockerProxy(t *testing.T) {
 
 		require.NoError(t, err, "Handler execution failed")
 		require.NotNil(t, result, "Handler returned nil result")
-		require.Len(t, result.Content, 1, "Expected exactly one content item in result")
+		require.Len(t, result.Content, 2, "Expected the status and the body content items in result")
+		assertProxyStatus(t, result, "HTTP 200 OK")
 
-		textContent, ok := result.Content[0].(mcpmodels.TextContent)
+		textContent, ok := result.Content[1].(mcpmodels.TextContent)
 		require.True(t, ok, "Expected text content in result")
 		require.NotEmpty(t, textContent.Text, "Result text content should not be empty")
 
@@ -103,9 +105,10 @@ func TestDockerProxy(t *testing.T) {
 
 		require.NoError(t, err, "Create Volume handler execution failed")
 		require.NotNil(t, result, "Create Volume handler returned nil result")
-		require.Len(t, result.Content, 1, "Expected one content item for Create Volume")
+		require.Len(t, result.Content, 2, "Expected the status and the body content items for Create Volume")
+		assertProxyStatus(t, result, "HTTP 201 Created")
 
-		textContent, ok := result.Content[0].(mcpmodels.TextContent)
+		textContent, ok := result.Content[1].(mcpmodels.TextContent)
 		require.True(t, ok, "Expected text content for Create Volume")
 		require.NotEmpty(t, textContent.Text, "Create Volume response text should not be empty")
 
@@ -135,9 +138,10 @@ func TestDockerProxy(t *testing.T) {
 
 		require.NoError(t, err, "List Volumes handler execution failed")
 		require.NotNil(t, result, "List Volumes handler returned nil result")
-		require.Len(t, result.Content, 1, "Expected one content item for List Volumes")
+		require.Len(t, result.Content, 2, "Expected the status and the body content items for List Volumes")
+		assertProxyStatus(t, result, "HTTP 200 OK")
 
-		textContent, ok := result.Content[0].(mcpmodels.TextContent)
+		textContent, ok := result.Content[1].(mcpmodels.TextContent)
 		require.True(t, ok, "Expected text content for List Volumes")
 		require.NotEmpty(t, textContent.Text, "List Volumes response text should not be empty")
 
@@ -153,7 +157,7 @@ func TestDockerProxy(t *testing.T) {
 	// Verifies that:
 	// - A DELETE request to /volumes/{name} proxies correctly.
 	// - The volume created earlier is successfully removed.
-	// - The handler response is empty (reflecting Docker's 204 No Content).
+	// - The handler response only reports the status (reflecting Docker's 204 No Content).
 	t.Run("Remove Volume", func(t *testing.T) {
 		request := mcp.CreateMCPRequest(map[string]any{
 			"environmentId": float64(testLocalEndpointID),
@@ -166,10 +170,19 @@ func TestDockerProxy(t *testing.T) {
 
 		require.NoError(t, err, "Remove Volume handler execution failed")
 		require.NotNil(t, result, "Remove Volume handler returned nil result")
-		require.Len(t, result.Content, 1, "Expected one content item for Remove Volume")
+		require.Len(t, result.Content, 1, "Expected only the status content item for Remove Volume")
 
 		textContent, ok := result.Content[0].(mcpmodels.TextContent)
 		require.True(t, ok, "Expected text content for Remove Volume")
-		assert.Empty(t, textContent.Text, "Remove Volume response text should be empty for 204 No Content")
+		assert.Equal(t, "HTTP 204 No Content\n(empty response body)", textContent.Text, "Remove Volume response should report the empty body of 204 No Content")
 	})
 }
+
+// assertProxyStatus checks that the first content item of a proxy result starts with the expected status line
+func assertProxyStatus(t *testing.T, result *mcpmodels.CallToolResult, expectedStatus string) {
+	t.Helper()
+
+	statusContent, ok := result.Content[0].(mcpmodels.TextContent)
+	require.True(t, ok, "Expected text content for the response status")
+	assert.True(t, strings.HasPrefix(statusContent.Text, expectedStatus), "Unexpected response status: %s", statusContent.Text)
+}
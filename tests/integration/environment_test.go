@@ -64,10 +64,15 @@ func TestEnvironmentManagement(t *testing.T) {
 		textContent, ok := result.Content[0].(mcpmodels.TextContent)
 		assert.True(t, ok, "Expected text content in MCP response")
 
-		var environments []models.Environment
-		err = json.Unmarshal([]byte(textContent.Text), &environments)
+		var page struct {
+			Environments []models.Environment `json:"environments"`
+			TotalCount   int                  `json:"total_count"`
+		}
+		err = json.Unmarshal([]byte(textContent.Text), &page)
 		require.NoError(t, err, "Failed to unmarshal environments from MCP response")
-		require.Len(t, environments, 1, "Expected exactly one environment after unmarshalling")
+		require.Len(t, page.Environments, 1, "Expected exactly one environment after unmarshalling")
+		assert.Equal(t, 1, page.TotalCount, "Expected a total count of one environment")
+		environments := page.Environments
 
 		// Extract the environment for subsequent tests
 		environment = environments[0]